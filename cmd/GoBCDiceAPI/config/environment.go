@@ -8,11 +8,21 @@ import (
 
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/declarative"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/external"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/script"
 )
 
 var (
 	// Environment is the environment for the application
 	Environment = os.Getenv("ECHO_ENV")
+	// DieFeeder is the name of the die feeder used for dice rolls
+	DieFeeder = os.Getenv("DIE_FEEDER")
+	// DiceBotDir is the directory containing declarative dicebot definitions and dicebot scripts
+	DiceBotDir = os.Getenv("DICEBOT_DIR")
+	// DiceBotPluginDir is the directory containing executables of out-of-process dicebots
+	DiceBotPluginDir = os.Getenv("DICEBOT_PLUGIN_DIR")
 )
 
 func Setup(e *echo.Echo) {
@@ -20,6 +30,22 @@ func Setup(e *echo.Echo) {
 		Environment = "development"
 	}
 
+	if DiceBotDir != "" {
+		if _, err := declarative.LoadDir(DiceBotDir); err != nil {
+			panic(err)
+		}
+
+		if _, err := script.LoadDir(DiceBotDir); err != nil {
+			panic(err)
+		}
+	}
+
+	if DiceBotPluginDir != "" {
+		if _, err := external.LoadDir(DiceBotPluginDir, external.Config{Stderr: os.Stderr}); err != nil {
+			panic(err)
+		}
+	}
+
 	if Environment == "production" {
 		tmpdir := filepath.Join(os.TempDir(), "GoBCDiceAPI")
 		os.MkdirAll(tmpdir, 0700)
@@ -36,4 +62,23 @@ func Setup(e *echo.Echo) {
 	}
 }
 
+// Teardown stops the processes of out-of-process dicebots
+func Teardown() {
+	external.CloseAll()
+}
+
+// DEFAULT_DIE_FEEDER is the name of the die feeder used when DIE_FEEDER is not set
+const DEFAULT_DIE_FEEDER = "crypto"
+
+// NewDieFeeder creates the die feeder named by DIE_FEEDER.
+// See feeder.NewByName for the available names.
+func NewDieFeeder() (feeder.DieFeeder, error) {
+	name := DieFeeder
+	if name == "" {
+		name = DEFAULT_DIE_FEEDER
+	}
+
+	return feeder.NewByName(name)
+}
+
 // vi:syntax=go
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+)
+
+func TestNewDieFeeder(t *testing.T) {
+	testcases := []struct {
+		name     string
+		expected feeder.DieFeeder
+	}{
+		{"", &feeder.Crypto{}},
+		{"crypto", &feeder.Crypto{}},
+		{"MT", &feeder.MT19937{}},
+		{"ruby", &feeder.Ruby{}},
+		{"queue", &feeder.Queue{}},
+	}
+
+	original := DieFeeder
+	defer func() {
+		DieFeeder = original
+	}()
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			DieFeeder = test.name
+
+			f, err := NewDieFeeder()
+			if err != nil {
+				t.Fatalf("構築エラー: %s", err)
+			}
+
+			if reflect.TypeOf(f) != reflect.TypeOf(test.expected) {
+				t.Errorf("異なる種類のダイス供給機: got %T, want %T", f, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDieFeeder_UnknownName(t *testing.T) {
+	original := DieFeeder
+	defer func() {
+		DieFeeder = original
+	}()
+
+	DieFeeder = "unknown"
+
+	if _, err := NewDieFeeder(); err == nil {
+		t.Error("未知の名前でエラーが発生しなかった")
+	}
+}
@@ -0,0 +1,95 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+// diceRollResponse は /v1/diceroll の応答
+type diceRollResponse struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+	Result  struct {
+		GameID string `json:"gameId"`
+		Text   string `json:"text"`
+		Dice   []struct {
+			Value int `json:"value"`
+			Sides int `json:"sides"`
+		} `json:"dice"`
+	} `json:"result"`
+}
+
+func TestGetDiceRoll(t *testing.T) {
+	testcases := []struct {
+		system   string
+		command  string
+		dice     []dice.Die
+		expected string
+	}{
+		{"", "2D6", []dice.Die{{3, 6}, {4, 6}}, "DiceBot : (2D6) ＞ 7[3,4] ＞ 7"},
+		{"DiceBot", "1D100<=50", []dice.Die{{42, 100}}, "DiceBot : (1D100<=50) ＞ 42[42] ＞ 42 ＞ 成功"},
+	}
+
+	s := S{}
+	s.SetUpSuite(nil)
+
+	for _, test := range testcases {
+		t.Run(test.command, func(t *testing.T) {
+			s.DieFeeder.Set(test.dice)
+
+			params := url.Values{"command": {test.command}}
+			if test.system != "" {
+				params.Set("system", test.system)
+			}
+
+			rec := s.PerformRequest("GET", "/v1/diceroll", params)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("wrong code: got=%v want=%v (%s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+
+			var r diceRollResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &r); err != nil {
+				t.Fatal(err)
+			}
+
+			if !r.OK {
+				t.Fatalf("wrong response: %+v", r)
+			}
+
+			if r.Result.Text != test.expected {
+				t.Errorf("wrong text: got=%q want=%q", r.Result.Text, test.expected)
+			}
+
+			if len(r.Result.Dice) != len(test.dice) {
+				t.Errorf("wrong number of dice: got=%d want=%d", len(r.Result.Dice), len(test.dice))
+			}
+		})
+	}
+}
+
+func TestGetDiceRoll_Error(t *testing.T) {
+	testcases := []struct {
+		name   string
+		params url.Values
+	}{
+		{"コマンドなし", url.Values{}},
+		{"未知のゲームシステム", url.Values{"system": {"Unknown"}, "command": {"2D6"}}},
+		{"不正なコマンド", url.Values{"command": {"XYZ"}}},
+	}
+
+	s := S{}
+	s.SetUpSuite(nil)
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			rec := s.PerformRequest("GET", "/v1/diceroll", test.params)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("wrong code: got=%v want=%v", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -3,21 +3,26 @@ package controllers
 import (
 	"github.com/labstack/echo"
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/controllers/v1"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
 )
 
 // Setup はすべてのコントローラの初期設定を行う。
-func Setup(e *echo.Echo) {
+//
+// f: ダイスロールに使うダイス供給機。
+func Setup(e *echo.Echo, f feeder.DieFeeder) {
 	root := NewRootController(e.Router())
 	root.Setup()
 
 	gV1 := e.Group("/v1")
-	setupV1(gV1)
+	setupV1(gV1, f)
 }
 
 // setupV1 は v1/ 以下のコントローラの初期設定を行う。
-func setupV1(g *echo.Group) {
+func setupV1(g *echo.Group, f feeder.DieFeeder) {
 	version := v1.NewVersionController(g)
 	version.Setup()
 	systems := v1.NewSystemsController(g)
 	systems.Setup()
+	diceRoll := v1.NewDiceRollController(g, f)
+	diceRoll.Setup()
 }
@@ -9,6 +9,10 @@ import (
 
 	"github.com/labstack/echo"
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/controllers"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+
+	// すべてのゲームシステムを登録する
+	_ "github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/all"
 
 	. "gopkg.in/check.v1"
 )
@@ -17,13 +21,16 @@ func Test(t *testing.T) { TestingT(t) }
 
 type S struct {
 	Server *echo.Echo
+	// ダイスロールで使われるダイス供給機
+	DieFeeder *feeder.Queue
 }
 
 var _ = Suite(&S{})
 
 func (s *S) SetUpSuite(c *C) {
 	s.Server = echo.New()
-	controllers.Setup(s.Server)
+	s.DieFeeder = feeder.NewEmptyQueue()
+	controllers.Setup(s.Server, s.DieFeeder)
 }
 
 func (s *S) TearDownSuite(c *C) {
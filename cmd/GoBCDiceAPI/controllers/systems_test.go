@@ -0,0 +1,81 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// systemsResponse は /v1/systems の応答
+type systemsResponse struct {
+	OK      bool   `json:"ok"`
+	Message string `json:"message"`
+	Systems []struct {
+		GameID   string   `json:"gameId"`
+		Name     string   `json:"name"`
+		SortKey  string   `json:"sortKey"`
+		Help     string   `json:"help"`
+		Prefixes []string `json:"prefixes"`
+	} `json:"systems"`
+}
+
+func TestGetSystems(t *testing.T) {
+	testcases := []struct {
+		locale       string
+		expectedName string
+	}{
+		{"", "ダイスボット (指定無し)"},
+		{"ja", "ダイスボット (指定無し)"},
+		{"en", "DiceBot (no game system)"},
+	}
+
+	s := S{}
+	s.SetUpSuite(nil)
+
+	for _, test := range testcases {
+		t.Run(test.locale, func(t *testing.T) {
+			params := url.Values{}
+			if test.locale != "" {
+				params.Set("locale", test.locale)
+			}
+
+			rec := s.PerformRequest("GET", "/v1/systems", params)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("wrong code: got=%v want=%v", rec.Code, http.StatusOK)
+			}
+
+			var r systemsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &r); err != nil {
+				t.Fatal(err)
+			}
+
+			if !r.OK || len(r.Systems) == 0 {
+				t.Fatalf("wrong response: %+v", r)
+			}
+
+			basic := r.Systems[0]
+			if basic.GameID != "DiceBot" {
+				t.Errorf("wrong first system: got=%q want=%q", basic.GameID, "DiceBot")
+			}
+
+			if basic.Name != test.expectedName {
+				t.Errorf("wrong name: got=%q want=%q", basic.Name, test.expectedName)
+			}
+
+			if basic.SortKey == "" || basic.Help == "" || basic.Prefixes == nil {
+				t.Errorf("metadata is missing: %+v", basic)
+			}
+		})
+	}
+}
+
+func TestGetSystems_UnknownLocale(t *testing.T) {
+	s := S{}
+	s.SetUpSuite(nil)
+
+	rec := s.PerformRequest("GET", "/v1/systems", url.Values{"locale": {"xx"}})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("wrong code: got=%v want=%v", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"github.com/labstack/echo"
+	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/helpers"
+	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/models"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	dicebotlist "github.com/raa0121/GoBCDice/pkg/dicebot/list"
+)
+
+// DiceRollController はコマンドを実行するコントローラ。
+type DiceRollController struct {
+	Group *echo.Group
+	// ダイスロールに使うダイス供給機
+	DieFeeder feeder.DieFeeder
+}
+
+// NewDiceRollController は新しいDiceRollControllerを返す。
+func NewDiceRollController(g *echo.Group, f feeder.DieFeeder) *DiceRollController {
+	return &DiceRollController{
+		Group:     g,
+		DieFeeder: f,
+	}
+}
+
+// getDiceRoll は、指定されたゲームシステムでコマンドを実行し、その結果を返す。
+//
+// クエリパラメータ system でゲーム識別子を、command で実行するコマンドを指定する。
+// system を省略した場合は、ゲームシステムを指定しないダイスボットを使う。
+func (controller *DiceRollController) getDiceRoll(c echo.Context) error {
+	gameID := c.QueryParam("system")
+	if gameID == "" {
+		gameID = dicebotlist.BASIC_GAME_ID
+	}
+
+	command := c.QueryParam("command")
+	if command == "" {
+		return helpers.JSONResponseError(c, helpers.NewResponseError(400, "command is required"))
+	}
+
+	diceRoll, err := models.NewDiceRoll(controller.DieFeeder, gameID, command)
+	if err != nil {
+		return helpers.JSONResponseError(c, helpers.NewResponseError(400, err.Error()))
+	}
+
+	return helpers.JSONResponseObject(c, 200, diceRoll)
+}
+
+// Setup はコントローラの初期設定を行う。
+func (controller *DiceRollController) Setup() {
+	controller.Group.Add("GET", "/diceroll", controller.getDiceRoll)
+}
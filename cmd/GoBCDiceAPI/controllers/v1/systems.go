@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo"
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/helpers"
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/models"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 )
 
 type SystemsController struct {
@@ -16,8 +17,24 @@ func NewSystemsController(g *echo.Group) *SystemsController {
 	}
 }
 
+// getSystems は利用可能なゲームシステムの一覧を返す。
+//
+// クエリパラメータ locale でゲームシステム名とヘルプメッセージのロケールを指定できる。
 func (controller *SystemsController) getSystems(c echo.Context) error {
-	systems := models.NewSystems()
+	l := locale.DEFAULT
+	if name := c.QueryParam("locale"); name != "" {
+		parsed, err := locale.Parse(name)
+		if err != nil {
+			return helpers.JSONResponseError(c, helpers.NewResponseError(400, err.Error()))
+		}
+
+		l = parsed
+	}
+
+	systems, err := models.NewSystems(l)
+	if err != nil {
+		return helpers.JSONResponseError(c, helpers.NewResponseError(500, err.Error()))
+	}
 
 	return helpers.JSONResponseObject(c, 200, systems)
 }
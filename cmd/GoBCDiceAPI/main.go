@@ -6,6 +6,9 @@ import (
 	"github.com/labstack/echo"
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/config"
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/controllers"
+
+	// すべてのゲームシステムを登録する
+	_ "github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/all"
 )
 
 func getPort() string {
@@ -21,10 +24,16 @@ func main() {
 	e := echo.New()
 
 	config.Setup(e)
-	controllers.Setup(e)
+	defer config.Teardown()
 
-	err := e.Start(":" + getPort())
+	f, err := config.NewDieFeeder()
 	if err != nil {
 		panic(err)
 	}
+
+	controllers.Setup(e, f)
+
+	if err := e.Start(":" + getPort()); err != nil {
+		panic(err)
+	}
 }
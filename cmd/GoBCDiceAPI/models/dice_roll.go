@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/helpers"
+	"github.com/raa0121/GoBCDice/pkg/bcdice"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+)
+
+// DiceRoll はダイスロールの結果。
+type DiceRoll struct {
+	Result *command.Result
+}
+
+// NewDiceRoll は、指定されたゲームシステムでコマンドを実行し、その結果を返す。
+//
+// f: ダイス供給機,
+// gameID: ゲーム識別子,
+// c: 実行するコマンド。
+func NewDiceRoll(f feeder.DieFeeder, gameID string, c string) (*DiceRoll, error) {
+	b := bcdice.New(f)
+	if err := b.SetDiceBotByGameID(gameID); err != nil {
+		return nil, err
+	}
+
+	result, err := b.ExecuteCommand(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DiceRoll{Result: result}, nil
+}
+
+func (d *DiceRoll) ToResponseMap() helpers.ResponseMap {
+	return helpers.ResponseMap{
+		"result": d.Result,
+	}
+}
@@ -2,19 +2,63 @@ package models
 
 import (
 	"github.com/raa0121/GoBCDice/cmd/GoBCDiceAPI/helpers"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
 	"github.com/raa0121/GoBCDice/pkg/dicebot/list"
 )
 
+// System はゲームシステムの情報。
+type System struct {
+	GameID   string
+	Name     string
+	SortKey  string
+	Help     string
+	Prefixes []string
+}
+
+// Systems は利用可能なゲームシステムの一覧。
 type Systems struct {
-	Names []string
+	Systems []System
 }
 
-func NewSystems() *Systems {
-	return &Systems{Names: list.AvailableGameIDs(true)}
+// NewSystems は、指定されたロケールでゲームシステムの一覧を作る。
+// ゲームシステムは並べ替えのキーの順に並べられる。
+func NewSystems(l locale.Locale) (*Systems, error) {
+	gameIDs := list.AvailableGameIDs(true)
+	systems := make([]System, 0, len(gameIDs))
+
+	for _, gameID := range gameIDs {
+		constructor, err := list.Find(gameID)
+		if err != nil {
+			return nil, err
+		}
+
+		b := constructor()
+		systems = append(systems, System{
+			GameID:   gameID,
+			Name:     dicebot.GameName(b, l),
+			SortKey:  dicebot.SortKey(b),
+			Help:     dicebot.HelpMessage(b, l),
+			Prefixes: dicebot.CommandPrefixes(b),
+		})
+	}
+
+	return &Systems{Systems: systems}, nil
 }
 
 func (s *Systems) ToResponseMap() helpers.ResponseMap {
+	systems := make([]helpers.ResponseMap, 0, len(s.Systems))
+	for _, system := range s.Systems {
+		systems = append(systems, helpers.ResponseMap{
+			"gameId":   system.GameID,
+			"name":     system.Name,
+			"sortKey":  system.SortKey,
+			"help":     system.Help,
+			"prefixes": system.Prefixes,
+		})
+	}
+
 	return helpers.ResponseMap{
-		"systems": s.Names,
+		"systems": systems,
 	}
 }
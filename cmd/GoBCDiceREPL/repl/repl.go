@@ -10,33 +10,25 @@ import (
 	"github.com/chzyer/readline"
 	"github.com/raa0121/GoBCDice/pkg/bcdice"
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/parser"
 	"github.com/raa0121/GoBCDice/pkg/core/util"
 	dicebotlist "github.com/raa0121/GoBCDice/pkg/dicebot/list"
-	dicebottesting "github.com/raa0121/GoBCDice/pkg/dicebot/testing"
 )
 
 const (
-	// 書式設定をリセットするエスケープシーケンス
-	ESC_RESET = "\033[0m"
-	// 太字にするエスケープシーケンス
-	ESC_BOLD = "\033[1m"
-	// 文字色を赤色にするエスケープシーケンス
-	ESC_RED = "\033[31m"
-	// 文字色を黄色にするエスケープシーケンス
-	ESC_YELLOW = "\033[33m"
-	// 文字色をシアンにするエスケープシーケンス
-	ESC_CYAN = "\033[36m"
-
 	// REPLのプロンプト
-	PROMPT = ESC_YELLOW + ">>" + ESC_RESET + " "
+	PROMPT = command.ANSI_YELLOW + ">>" + command.ANSI_RESET + " "
+	// ダイスの出目を入力してもらうときのプロンプトの書式
+	DIE_PROMPT_FORMAT = command.ANSI_YELLOW + "D%dの出目>" + command.ANSI_RESET + " "
 	// 結果の初めに出力する文字列
-	RESULT_HEADER = ESC_CYAN + "=>" + ESC_RESET + " "
+	RESULT_HEADER = command.ANSI_CYAN + "=>" + command.ANSI_RESET + " "
 	// シークレットロールであることを表すヘッダ文字列
-	SECRET_HEADER = ESC_YELLOW + "[secret]" + ESC_RESET + " "
+	SECRET_HEADER = command.ANSI_SECRET_HEADER
 
 	COMMAND_AST            = "ast"
 	COMMAND_EVAL           = "eval"
@@ -45,6 +37,10 @@ const (
 	COMMAND_SET_DICE_QUEUE = "set-dice-queue"
 	COMMAND_SET_GAME       = "set-game"
 	COMMAND_LIST_GAMES     = "list-games"
+	COMMAND_SET_LOCALE     = "set-locale"
+
+	// 手動入力型ダイス供給機の名前
+	DIE_FEEDER_MANUAL = "manual"
 
 	COMMAND_HELP = "help"
 	COMMAND_QUIT = "quit"
@@ -91,6 +87,14 @@ var (
 	tailSpacesRe = regexp.MustCompile(`\s+\z`)
 )
 
+// 取り出されるダイスの列を設定できるダイス供給機のインターフェース。
+type diceQueue interface {
+	// Dice は、現在のダイスの列をコピーして返す。
+	Dice() []dice.Die
+	// Set は、取り出されるダイスの列を設定する。
+	Set(ds []dice.Die)
+}
+
 // REPLで使用するデータを格納する構造体。
 type REPL struct {
 	in         io.Reader
@@ -100,6 +104,8 @@ type REPL struct {
 	diceRoller *roller.DiceRoller
 	bcDice     *bcdice.BCDice
 	completer  *readline.PrefixCompleter
+	// 行の読み込みに使うreadlineのインスタンス
+	readline *readline.Instance
 }
 
 // init はパッケージを初期化する。
@@ -134,19 +140,21 @@ func init() {
 			Description: "利用可能なゲームシステムの識別子の一覧を出力します",
 			Handler:     listGames,
 		},
+		{
+			Name:            COMMAND_SET_LOCALE,
+			ArgsDescription: "ja/en",
+			Description:     "結果のメッセージの言語を設定します",
+			Handler:         setLocale,
+		},
 		{
 			Name:            COMMAND_SET_DIE_FEEDER,
-			ArgsDescription: "mt/queue",
-			Description:     "ダイスの供給方法を設定します - mt: ランダム、queue: 手動指定",
+			ArgsDescription: "mt/ruby/crypto/queue/hybrid/manual",
+			Description:     "ダイスの供給方法を設定します - mt: ランダム、ruby: Ruby版BCDice互換のランダム、crypto: 予測困難なランダム、queue: 手動指定、hybrid: 手動指定（指定がなければランダム）、manual: 実際に振ったダイスの出目を入力",
 			Handler:         setDieFeeder,
-			Completers: []readline.PrefixCompleterInterface{
-				readline.PcItem("mt"),
-				readline.PcItem("queue"),
-			},
 		},
 		{
 			Name:            COMMAND_SET_DICE_QUEUE,
-			ArgsDescription: "[値/面数[, 値/面数]...]",
+			ArgsDescription: "[値/面数[, 値/面数]...]（hybridでは値に?も指定可）",
 			Description:     "ダイスロール時に取り出されるダイスの列を設定します",
 			Handler:         setDiceQueue,
 		},
@@ -167,6 +175,23 @@ func init() {
 		commandMap[c.Name] = c
 	}
 
+	commandSetDieFeeder := commandMap[COMMAND_SET_DIE_FEEDER]
+	dieFeederNames := append(feeder.AvailableNames(), DIE_FEEDER_MANUAL)
+	for _, name := range dieFeederNames {
+		commandSetDieFeeder.Completers = append(
+			commandSetDieFeeder.Completers,
+			readline.PcItem(name),
+		)
+	}
+
+	commandSetLocale := commandMap[COMMAND_SET_LOCALE]
+	for _, l := range locale.Available() {
+		commandSetLocale.Completers = append(
+			commandSetLocale.Completers,
+			readline.PcItem(l.String()),
+		)
+	}
+
 	commandSetGame := commandMap[COMMAND_SET_GAME]
 	for _, gameId := range dicebotlist.AvailableGameIDs(true) {
 		commandSetGame.Completers = append(
@@ -227,6 +252,8 @@ func (r *REPL) Start() {
 	}
 	defer l.Close()
 
+	r.readline = l
+
 	r.printWelcomeMessage()
 
 	for !r.terminated {
@@ -279,7 +306,7 @@ func (r *REPL) printCommandUsage(c *Command) {
 
 // printWelcomeMessage は起動時の歓迎メッセージを出力する。
 func (r *REPL) printWelcomeMessage() {
-	fmt.Fprintln(r.out, ESC_BOLD+"GoBCDice REPL"+ESC_RESET)
+	fmt.Fprintln(r.out, command.ANSI_BOLD+"GoBCDice REPL"+command.ANSI_RESET)
 	fmt.Fprintln(r.out, "\n* BCDiceコマンドを入力すると、その評価結果を出力します")
 	fmt.Fprintln(r.out, "* \".help\" と入力すると、利用できるコマンドの使用法と説明を出力します")
 	fmt.Fprintln(r.out, "* \".q\" または \".quit\" と入力すると終了します")
@@ -288,12 +315,12 @@ func (r *REPL) printWelcomeMessage() {
 
 // printOK はコマンドの実行に成功した旨のメッセージを出力する。
 func (r *REPL) printOK() {
-	fmt.Fprintln(r.out, ESC_CYAN+"OK"+ESC_RESET)
+	fmt.Fprintln(r.out, command.ANSI_CYAN+"OK"+command.ANSI_RESET)
 }
 
 // printError はエラーメッセージを強調して出力する。
 func (r *REPL) printError(err error) {
-	fmt.Fprintln(r.out, ESC_RED+err.Error()+ESC_RESET)
+	fmt.Fprintln(r.out, command.ANSI_RED+err.Error()+command.ANSI_RESET)
 }
 
 // printSExp は、inputを構文解析し、得られたASTをS式の形で出力する。
@@ -330,28 +357,22 @@ func eval(r *REPL, c *Command, input string) {
 	}
 
 	// ダイスロール結果を指定していた場合は、評価後にそれを復元する
-	if r.dieFeeder.CanSpecifyDie() {
-		ds := r.dieFeeder.(*feeder.Queue).Dice()
-		defer func() {
-			f := r.dieFeeder.(*feeder.Queue)
-			f.Set(ds)
-		}()
+	if q, ok := r.dieFeeder.(diceQueue); ok {
+		ds := q.Dice()
+		defer q.Set(ds)
 	}
 
-	command, isSecret := util.CheckIfInputMayBeASecretRoll(input)
-	result, err := r.bcDice.ExecuteCommand(command)
+	defer r.discardPendingDieValues()
+
+	// シークレットロールの表示は整形器が行う
+	result, err := r.bcDice.ExecuteCommand(input)
 	if err != nil {
 		r.printError(err)
 		return
 	}
 
 	fmt.Fprint(r.out, RESULT_HEADER)
-
-	if isSecret {
-		fmt.Fprint(r.out, SECRET_HEADER)
-	}
-
-	fmt.Fprintln(r.out, result.Message())
+	fmt.Fprintln(r.out, command.ANSIFormatter{}.Format(result))
 }
 
 var rollDiceRe = regexp.MustCompile(`\A(\d+)\s+(\d+)\z`)
@@ -366,14 +387,13 @@ func rollDice(r *REPL, c *Command, input string) {
 	}
 
 	// ダイスロール結果を指定していた場合は、評価後にそれを復元する
-	if r.dieFeeder.CanSpecifyDie() {
-		ds := r.dieFeeder.(*feeder.Queue).Dice()
-		defer func() {
-			f := r.dieFeeder.(*feeder.Queue)
-			f.Set(ds)
-		}()
+	if q, ok := r.dieFeeder.(diceQueue); ok {
+		ds := q.Dice()
+		defer q.Set(ds)
 	}
 
+	defer r.discardPendingDieValues()
+
 	num, _ := strconv.Atoi(matches[1])
 	sides, _ := strconv.Atoi(matches[2])
 
@@ -411,23 +431,43 @@ func listGames(r *REPL, c *Command, input string) {
 	}
 }
 
+// setLocale は、結果のメッセージの言語を設定する。
+// inputが空の場合は、現在の言語を出力する。
+func setLocale(r *REPL, c *Command, input string) {
+	if input == "" {
+		fmt.Fprintln(r.out, r.bcDice.Locale())
+		return
+	}
+
+	if err := r.bcDice.SetLocaleByName(input); err != nil {
+		r.printError(err)
+		return
+	}
+
+	r.printOK()
+}
+
 // setDieFeeder は、ダイス供給機を設定する。
 // inputには以下を指定できる。
 //
-// * "queue": 出目を指定する。
-// * "mt"   : ランダムな出目とする。
+// * "queue" : 出目を指定する。
+// * "mt"    : ランダムな出目とする。
+// * "ruby"  : Ruby版BCDiceと同じ方法でランダムな出目とする。
+// * "crypto": 予測困難なランダムな出目とする。
+// * "hybrid": 出目を指定する。指定した出目がなくなったらランダムな出目とする。
+// * "manual": ダイスが振られるたびに、実際に振ったダイスの出目を入力してもらう。
 func setDieFeeder(r *REPL, c *Command, input string) {
 	var f feeder.DieFeeder
 
-	feederType := strings.ToLower(input)
-	switch feederType {
-	case "queue":
-		f = feeder.NewEmptyQueue()
-	case "mt":
-		f = feeder.NewMT19937WithSeedFromTime()
-	default:
-		r.printCommandUsage(c)
-		return
+	if strings.ToLower(input) == DIE_FEEDER_MANUAL {
+		f = feeder.NewManual(r.inputDieValues)
+	} else {
+		var err error
+		f, err = feeder.NewByName(input)
+		if err != nil {
+			r.printCommandUsage(c)
+			return
+		}
 	}
 
 	r.dieFeeder = f
@@ -439,28 +479,78 @@ func setDieFeeder(r *REPL, c *Command, input string) {
 
 // setDiceQueue は、ダイスロールで取り出されるダイスの列を設定する。
 // inputは "値/面数, 値/面数, ..." という形にする。
+// ダイス供給方法がhybridの場合は、値の代わりに "?" を指定できる。
 func setDiceQueue(r *REPL, c *Command, input string) {
-	if !r.dieFeeder.CanSpecifyDie() {
+	q, ok := r.dieFeeder.(diceQueue)
+	if !ok {
 		r.printError(fmt.Errorf("現在のダイス供給方法では、取り出されるダイスの列を設定できません"))
 		return
 	}
 
-	ds, err := dicebottesting.ParseDice(input)
+	parseDice := dice.ParseDice
+	if _, isHybrid := q.(*feeder.Hybrid); isHybrid {
+		parseDice = feeder.ParseHybridDice
+	}
+
+	ds, err := parseDice(input)
 	if err != nil {
 		r.printError(err)
 		return
 	}
 
-	f := r.dieFeeder.(*feeder.Queue)
-	f.Set(ds)
+	q.Set(ds)
 
 	r.printOK()
 }
 
+// inputDieValues は、実際に振ったダイスの出目を入力してもらう。
+// 出目は "3 5 6" のように空白区切りで複数入力できる。
+// 入力が正しくなければ、正しく入力されるまで繰り返し尋ねる。
+//
+// sides: ダイスの面の数
+func (r *REPL) inputDieValues(sides int) ([]int, error) {
+	if r.readline == nil {
+		return nil, fmt.Errorf("出目を入力できません")
+	}
+
+	r.readline.SetPrompt(fmt.Sprintf(DIE_PROMPT_FORMAT, sides))
+	defer r.readline.SetPrompt(PROMPT)
+
+	for {
+		line, err := r.readline.Readline()
+		if err != nil {
+			// ^C や ^D が押されたら入力を中断する
+			return nil, fmt.Errorf("出目の入力が中断されました")
+		}
+
+		values, err := feeder.ParseManualInput(line, sides)
+		if err != nil {
+			r.printError(err)
+			continue
+		}
+
+		return values, nil
+	}
+}
+
+// discardPendingDieValues は、入力されたが使われなかった出目を破棄する。
+// 余分に入力された出目が次のコマンドで使われることを防ぐ。
+func (r *REPL) discardPendingDieValues() {
+	f, ok := r.dieFeeder.(*feeder.Manual)
+	if !ok {
+		return
+	}
+
+	discarded := f.Discard()
+	if len(discarded) > 0 {
+		r.printError(fmt.Errorf("使われなかった出目を破棄しました: %s", dice.FormatDice(discarded)))
+	}
+}
+
 // printHelp は、利用できるコマンドの使用法と説明を出力する
 func printHelp(r *REPL, _ *Command, _ string) {
 	for _, c := range commands {
-		fmt.Fprint(r.out, ESC_BOLD+"."+c.Name+ESC_RESET)
+		fmt.Fprint(r.out, command.ANSI_BOLD+"."+c.Name+command.ANSI_RESET)
 		if c.ArgsDescription != "" {
 			fmt.Fprint(r.out, " "+c.ArgsDescription)
 		}
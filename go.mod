@@ -1,6 +1,7 @@
 module github.com/raa0121/GoBCDice
 
 require (
+	github.com/BurntSushi/toml v0.4.1
 	github.com/andlabs/ui v0.0.0-20180902183112-867a9e5a498d
 	github.com/chzyer/logex v1.1.10 // indirect
 	github.com/chzyer/readline v0.0.0-20180603132655-2972be24d48e
@@ -11,6 +12,9 @@ require (
 	github.com/labstack/gommon v0.3.0 // indirect
 	github.com/mattn/go-colorable v0.1.2
 	github.com/seehuhn/mt19937 v0.0.0-20180715112136-cc7708819361
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
 	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4 // indirect
+	golang.org/x/sys v0.7.0 // indirect
 	gopkg.in/check.v1 v0.0.0-20161208181325-20d25e280405
+	gopkg.in/yaml.v2 v2.2.2
 )
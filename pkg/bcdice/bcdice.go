@@ -1,90 +1,204 @@
+/*
+BCDiceの全体動作を統括するパッケージ。
+
+並行処理について
+
+BCDiceのメソッドは、複数のゴルーチンから同時に呼び出しても安全である。
+ただし、以下の条件を満たす必要がある。
+
+* 設定されているダイス供給機が、複数のゴルーチンから同時に使用しても安全であること。
+feeder パッケージのダイス供給機はすべてこの条件を満たす。
+
+* DiceBot フィールドを直接書き換えないこと。
+ダイスボットを変更する場合は SetDiceBotByGameID を使用する。
+
+コマンドの評価環境および評価器は、コマンドを実行するたびに新しく構築されるため、
+ゴルーチン間で共有されない。したがって、HTTPサーバのハンドラなどで1つのBCDiceを共有することができる。
+なお、複数のゴルーチンが同じダイス供給機からダイスを取り出す場合、
+どのコマンドにどのダイスが供給されるかは不定となる。
+*/
 package bcdice
 
 import (
 	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/command"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/parser"
 	"github.com/raa0121/GoBCDice/pkg/core/util"
 	"github.com/raa0121/GoBCDice/pkg/dicebot"
+	_ "github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/all"
 	dicebotlist "github.com/raa0121/GoBCDice/pkg/dicebot/list"
 )
 
 // BCDiceの全体動作を統括する構造体。
 type BCDice struct {
 	// 設定されているダイスボット
-	DiceBot    dicebot.DiceBot
+	DiceBot dicebot.DiceBot
+	// 設定へのアクセスを保護する
+	mu         sync.RWMutex
 	dieFeeder  feeder.DieFeeder
 	diceRoller *roller.DiceRoller
+	// メッセージのロケール
+	locale locale.Locale
 }
 
 // New は新しいBCDiceを構築する。
 func New(f feeder.DieFeeder) *BCDice {
-	b := &BCDice{}
+	b := &BCDice{
+		locale: locale.DEFAULT,
+	}
 
 	b.SetDieFeeder(f)
-	b.SetDiceBotByGameID("DiceBot")
+	b.SetDiceBotByGameID(dicebotlist.BASIC_GAME_ID)
 
 	return b
 }
 
 // SetDiceBotByGameID は、指定された識別子を持つゲームシステムのダイスボットを使用するよう設定する。
+// 識別子の照合では大文字小文字を区別しない。別名で指定することもできる。
 func (b *BCDice) SetDiceBotByGameID(gameID string) error {
 	diceBotConstructor, err := dicebotlist.Find(gameID)
 	if err != nil {
 		return err
 	}
 
-	b.DiceBot = diceBotConstructor()
+	diceBot := diceBotConstructor()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.DiceBot = diceBot
 
 	return nil
 }
 
 // DieFeeder は設定されているダイス供給機を返す。
 func (b *BCDice) DieFeeder() feeder.DieFeeder {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	return b.dieFeeder
 }
 
 // SetDieFeeder はダイス供給機を指定されたものに設定する。
 // 合わせてダイスローラーも設定される。
 func (b *BCDice) SetDieFeeder(f feeder.DieFeeder) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.dieFeeder = f
 	b.diceRoller = roller.New(f)
 }
 
+// Locale は設定されているメッセージのロケールを返す。
+func (b *BCDice) Locale() locale.Locale {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.locale
+}
+
+// SetLocale はメッセージのロケールを設定する。
+// 既定のロケールは日本語。
+func (b *BCDice) SetLocale(l locale.Locale) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.locale = l
+}
+
+// SetLocaleByName は、メッセージのロケールを指定された名前のものに設定する。
+// 利用できる名前については locale.Parse を参照。
+// 指定された名前のロケールが見つからなかった場合はエラーを返す。
+func (b *BCDice) SetLocaleByName(name string) error {
+	l, err := locale.Parse(name)
+	if err != nil {
+		return err
+	}
+
+	b.SetLocale(l)
+
+	return nil
+}
+
+// GameName は、設定されているダイスボットのゲームシステム名を設定されているロケールで返す。
+func (b *BCDice) GameName() string {
+	diceBot, _, l := b.settings()
+	return dicebot.GameName(diceBot, l)
+}
+
+// Usage は、設定されているダイスボットの使用法の説明を設定されているロケールで返す。
+func (b *BCDice) Usage() string {
+	diceBot, _, l := b.settings()
+	return dicebot.Usage(diceBot, l)
+}
+
+// settings は、現在設定されているダイスボット、ダイスローラーおよびロケールを返す。
+// コマンドの実行中に設定が変更されても影響を受けないように、実行開始時に呼び出す。
+func (b *BCDice) settings() (dicebot.DiceBot, *roller.DiceRoller, locale.Locale) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.DiceBot, b.diceRoller, b.locale
+}
+
+// SetDieFeederByName は、ダイス供給機を指定された名前の種類のものに設定する。
+// 利用できる名前については feeder.NewByName を参照。
+// 指定された名前の種類が見つからなかった場合はエラーを返す。
+func (b *BCDice) SetDieFeederByName(name string) error {
+	f, err := feeder.NewByName(name)
+	if err != nil {
+		return err
+	}
+
+	b.SetDieFeeder(f)
+
+	return nil
+}
+
 // 空白で区切られた入力文字列から最初の部分を取り出すための正規表現
 var commandFirstPartRe = regexp.MustCompile(`\A([^\s]*)(\s.*)?`)
 
 // ExecuteCommand は指定されたコマンドを実行する。
+//
+// 空白で区切られた最初の部分のみをコマンドとして実行した場合、
+// 残りの部分はコメントとして結果に記録される。
 func (b *BCDice) ExecuteCommand(input string) (*command.Result, error) {
+	diceBot, diceRoller, l := b.settings()
 	command, isSecret := util.CheckIfInputMayBeASecretRoll(input)
 
 	separated := commandFirstPartRe.FindStringSubmatch(command)
 	firstPart := separated[1]
+	comment := strings.TrimSpace(separated[2])
 
 	{
-		result, err := b.ExecuteDiceBotCommand(firstPart)
+		result, err := executeDiceBotCommand(diceBot, diceRoller, l, firstPart)
 		if err == nil {
 			result.IsSecret = isSecret
+			result.Comment = comment
 			return result, nil
 		}
 	}
 
 	{
-		result, err := b.ExecuteBasicCommand(command)
+		result, err := executeBasicCommand(diceBot, diceRoller, l, command)
 		if err == nil {
 			result.IsSecret = isSecret
 			return result, nil
 		}
 	}
 	{
-		result, err := b.ExecuteBasicCommand(firstPart)
+		result, err := executeBasicCommand(diceBot, diceRoller, l, firstPart)
 		if err == nil {
 			result.IsSecret = isSecret
+			result.Comment = comment
 			return result, nil
 		}
 
@@ -94,10 +208,41 @@ func (b *BCDice) ExecuteCommand(input string) (*command.Result, error) {
 
 // ExecuteDiceBotCommand は設定されているダイスボットを使用して指定されたコマンドを実行する。
 func (b *BCDice) ExecuteDiceBotCommand(c string) (*command.Result, error) {
+	diceBot, diceRoller, l := b.settings()
+	return executeDiceBotCommand(diceBot, diceRoller, l, c)
+}
+
+// ExecuteBasicCommand はBCDiceの基本コマンドを実行する。
+func (b *BCDice) ExecuteBasicCommand(c string) (*command.Result, error) {
+	diceBot, diceRoller, l := b.settings()
+	return executeBasicCommand(diceBot, diceRoller, l, c)
+}
+
+// newEvaluator は、ダイスボットのコマンドを実行するための評価器を返す。
+// ロケールおよびゲームシステムの評価の既定値が設定される。
+func newEvaluator(
+	diceBot dicebot.DiceBot,
+	diceRoller *roller.DiceRoller,
+	l locale.Locale,
+) *evaluator.Evaluator {
 	env := evaluator.NewEnvironment()
-	ev := evaluator.NewEvaluator(b.diceRoller, env)
+	ev := evaluator.NewEvaluator(diceRoller, env)
+	ev.Locale = l
+	dicebot.ApplyEvaluationDefaults(diceBot, ev)
 
-	result, err := b.DiceBot.ExecuteCommand(c, ev)
+	return ev
+}
+
+// executeDiceBotCommand は指定されたダイスボットを使用してコマンドを実行する。
+func executeDiceBotCommand(
+	diceBot dicebot.DiceBot,
+	diceRoller *roller.DiceRoller,
+	l locale.Locale,
+	c string,
+) (*command.Result, error) {
+	ev := newEvaluator(diceBot, diceRoller, l)
+
+	result, err := diceBot.ExecuteCommand(c, ev)
 	if err != nil {
 		return nil, err
 	}
@@ -105,15 +250,19 @@ func (b *BCDice) ExecuteDiceBotCommand(c string) (*command.Result, error) {
 	return result, nil
 }
 
-// ExecuteBasicCommand はBCDiceの基本コマンドを実行する。
-func (b *BCDice) ExecuteBasicCommand(c string) (*command.Result, error) {
+// executeBasicCommand はBCDiceの基本コマンドを実行する。
+func executeBasicCommand(
+	diceBot dicebot.DiceBot,
+	diceRoller *roller.DiceRoller,
+	l locale.Locale,
+	c string,
+) (*command.Result, error) {
 	node, parseErr := parser.Parse("input", []byte(c))
 	if parseErr != nil {
 		return nil, parseErr
 	}
 
-	env := evaluator.NewEnvironment()
-	ev := evaluator.NewEvaluator(b.diceRoller, env)
+	ev := newEvaluator(diceBot, diceRoller, l)
 
-	return command.Execute(node.(ast.Node), b.DiceBot.GameID(), ev)
+	return command.ExecuteWithHooks(node.(ast.Node), diceBot.GameID(), diceBot, ev)
 }
@@ -1,7 +1,17 @@
 package bcdice
 
 import (
+	"errors"
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+	"sync"
 	"testing"
 )
 
@@ -25,3 +35,505 @@ func TestSetUnknownDiceBot(t *testing.T) {
 		t.Fatal("未知のダイスボットを設定できてしまった")
 	}
 }
+
+func TestSetDieFeederByName(t *testing.T) {
+	f := feeder.NewEmptyQueue()
+	b := New(f)
+
+	err := b.SetDieFeederByName("crypto")
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if _, ok := b.DieFeeder().(*feeder.Crypto); !ok {
+		t.Fatalf("ダイス供給機が設定されていない: %T", b.DieFeeder())
+	}
+
+	if _, err := b.ExecuteCommand("2D6"); err != nil {
+		t.Fatalf("コマンド実行エラー: %s", err)
+	}
+}
+
+func TestSetUnknownDieFeeder(t *testing.T) {
+	f := feeder.NewEmptyQueue()
+	b := New(f)
+
+	err := b.SetDieFeederByName("Unknown")
+	if err == nil {
+		t.Fatal("未知のダイス供給機を設定できてしまった")
+	}
+
+	if b.DieFeeder() != f {
+		t.Fatal("ダイス供給機が変更されてしまった")
+	}
+}
+
+// 1つのBCDiceを複数のゴルーチンで共有する場合のテスト。
+// go test -race で実行すると、データ競合を検出できる。
+func TestExecuteCommand_Concurrent(t *testing.T) {
+	const (
+		numOfGoroutines = 32
+		numOfCommands   = 50
+	)
+
+	commands := []string{
+		"2D6+1",
+		"2D6>=7",
+		"3B6>=4",
+		"2R6>=5",
+		"2U6[6]",
+		"C(1+2*3)",
+		"CHOICE[a,b,c]",
+		"S1D100<=50",
+	}
+
+	dieFeeders := map[string]func() feeder.DieFeeder{
+		"MT19937": func() feeder.DieFeeder {
+			return feeder.NewMT19937(1)
+		},
+		"Crypto": func() feeder.DieFeeder {
+			return feeder.NewCrypto()
+		},
+		"Recorder": func() feeder.DieFeeder {
+			return feeder.NewRecorder(feeder.NewMT19937(1))
+		},
+	}
+
+	for name, newDieFeeder := range dieFeeders {
+		t.Run(name, func(t *testing.T) {
+			b := New(newDieFeeder())
+
+			var wg sync.WaitGroup
+			errs := make(chan error, numOfGoroutines*numOfCommands)
+
+			for i := 0; i < numOfGoroutines; i++ {
+				wg.Add(1)
+
+				go func(i int) {
+					defer wg.Done()
+
+					for j := 0; j < numOfCommands; j++ {
+						c := commands[(i+j)%len(commands)]
+						if _, err := b.ExecuteCommand(c); err != nil {
+							errs <- fmt.Errorf("%q: %s", c, err)
+						}
+					}
+				}(i)
+			}
+
+			// 実行中に設定を変更しても安全であることを確認する
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < numOfCommands; j++ {
+					b.SetDieFeeder(newDieFeeder())
+					b.SetDiceBotByGameID("DiceBot")
+				}
+			}()
+
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestExecuteCommand_ProvablyFair(t *testing.T) {
+	f := feeder.NewProvablyFair([]byte("server-seed"), "player1")
+	b := New(f)
+
+	commitment := f.Commitment()
+
+	result, err := b.ExecuteCommand("3D6+2D10")
+	if err != nil {
+		t.Fatalf("コマンド実行エラー: %s", err)
+	}
+
+	if len(result.Nonces) != len(result.RolledDice) {
+		t.Fatalf("ノンスの数が異なる: got %d, want %d",
+			len(result.Nonces), len(result.RolledDice))
+	}
+
+	serverSeed := f.Reveal()
+
+	if verifyErr := result.VerifyProvablyFair(commitment, serverSeed, "player1"); verifyErr != nil {
+		t.Errorf("検証エラー: %s", verifyErr)
+	}
+}
+
+// 他のダイス供給機で包んでも、結果を検証できることを確認する
+func TestExecuteCommand_ProvablyFair_Wrapped(t *testing.T) {
+	testcases := []struct {
+		name string
+		wrap func(f feeder.DieFeeder) feeder.DieFeeder
+	}{
+		{
+			name: "Recorder",
+			wrap: func(f feeder.DieFeeder) feeder.DieFeeder {
+				return feeder.NewRecorder(f)
+			},
+		},
+		{
+			name: "Hybrid",
+			wrap: func(f feeder.DieFeeder) feeder.DieFeeder {
+				return feeder.NewHybrid(f)
+			},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f := feeder.NewProvablyFair([]byte("server-seed"), "player1")
+			b := New(test.wrap(f))
+
+			commitment := f.Commitment()
+
+			result, err := b.ExecuteCommand("3D6+2D10")
+			if err != nil {
+				t.Fatalf("コマンド実行エラー: %s", err)
+			}
+
+			serverSeed := f.Reveal()
+
+			if verifyErr := result.VerifyProvablyFair(commitment, serverSeed, "player1"); verifyErr != nil {
+				t.Errorf("検証エラー: %s", verifyErr)
+			}
+		})
+	}
+}
+
+// 複数のゴルーチンが同じダイス供給器を共有しても、各結果を検証できることを確認する
+func TestExecuteCommand_ProvablyFair_Concurrent(t *testing.T) {
+	const numOfGoroutines = 8
+	const numOfCommands = 20
+
+	f := feeder.NewProvablyFair([]byte("server-seed"), "player1")
+	b := New(f)
+
+	commitment := f.Commitment()
+
+	var wg sync.WaitGroup
+	results := make(chan *command.Result, numOfGoroutines*numOfCommands)
+	errs := make(chan error, numOfGoroutines*numOfCommands)
+
+	for i := 0; i < numOfGoroutines; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < numOfCommands; j++ {
+				result, err := b.ExecuteCommand("3D6+2D10")
+				if err != nil {
+					errs <- err
+					continue
+				}
+
+				results <- result
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("コマンド実行エラー: %s", err)
+	}
+
+	serverSeed := f.Reveal()
+
+	for result := range results {
+		if err := result.VerifyProvablyFair(commitment, serverSeed, "player1"); err != nil {
+			t.Errorf("検証エラー: %s: %s", result.RolledDice, err)
+		}
+	}
+}
+
+func TestExecuteCommand_Comment(t *testing.T) {
+	testcases := []struct {
+		input    string
+		comment  string
+		isSecret bool
+	}{
+		{"2D6", "", false},
+		{"2D6 攻撃", "攻撃", false},
+		{"S2D6  こっそり  攻撃 ", "こっそり  攻撃", true},
+		{"CHOICE[A, B]", "", false},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%q", test.input), func(t *testing.T) {
+			b := New(feeder.NewMT19937(1))
+
+			result, err := b.ExecuteCommand(test.input)
+			if err != nil {
+				t.Fatalf("コマンド実行エラー: %s", err)
+			}
+
+			if result.Comment != test.comment {
+				t.Errorf("異なるコメント: got %q, want %q", result.Comment, test.comment)
+			}
+
+			if result.IsSecret != test.isSecret {
+				t.Errorf("異なるシークレットロールの判定: got %t, want %t",
+					result.IsSecret, test.isSecret)
+			}
+		})
+	}
+}
+
+func TestSetLocaleByName(t *testing.T) {
+	testcases := []struct {
+		locale   string
+		input    string
+		dice     []dice.Die
+		expected string
+	}{
+		{
+			locale:   "ja",
+			input:    "2D6>=7",
+			dice:     []dice.Die{{3, 6}, {4, 6}},
+			expected: "DiceBot : (2D6>=7) ＞ 7[3,4] ＞ 7 ＞ 成功",
+		},
+		{
+			locale:   "en",
+			input:    "2D6>=7",
+			dice:     []dice.Die{{3, 6}, {4, 6}},
+			expected: "DiceBot : (2D6>=7) ＞ 7[3,4] ＞ 7 ＞ Success",
+		},
+		{
+			locale:   "en",
+			input:    "3B6>=4",
+			dice:     []dice.Die{{3, 6}, {4, 6}, {6, 6}},
+			expected: "DiceBot : (3B6>=4) ＞ 3,4,6 ＞ Successes: 2",
+		},
+		{
+			locale:   "en",
+			input:    "2U6[6]",
+			dice:     []dice.Die{{6, 6}, {2, 6}, {3, 6}},
+			expected: "DiceBot : (2U6[6]) ＞ 8[6,2],3 ＞ 8/11 (max/sum)",
+		},
+		{
+			locale:   "en",
+			input:    "2R6",
+			expected: "DiceBot : (2R6) ＞ Specify a reroll threshold, e.g. 2R6>=5 or 2R6[5]",
+		},
+		{
+			locale:   "en",
+			input:    "C(1+2)",
+			expected: "DiceBot : C(1+2) ＞ Result ＞ 3",
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%s/%q", test.locale, test.input), func(t *testing.T) {
+			b := New(feeder.NewQueue(test.dice))
+
+			if err := b.SetLocaleByName(test.locale); err != nil {
+				t.Fatalf("ロケール設定エラー: %s", err)
+			}
+
+			result, err := b.ExecuteCommand(test.input)
+			if err != nil {
+				t.Fatalf("コマンド実行エラー: %s", err)
+			}
+
+			if actual := result.Message(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestSetUnknownLocale(t *testing.T) {
+	b := New(feeder.NewEmptyQueue())
+
+	if err := b.SetLocaleByName("Unknown"); err == nil {
+		t.Fatal("未知のロケールを設定できてしまった")
+	}
+
+	if b.Locale() != locale.DEFAULT {
+		t.Fatalf("ロケールが変更されてしまった: %s", b.Locale())
+	}
+}
+
+func TestGameName(t *testing.T) {
+	b := New(feeder.NewEmptyQueue())
+
+	if actual := b.GameName(); actual != "ダイスボット (指定無し)" {
+		t.Errorf("ja: got %q", actual)
+	}
+
+	b.SetLocale(locale.EN)
+
+	if actual := b.GameName(); actual != "DiceBot (no game system)" {
+		t.Errorf("en: got %q", actual)
+	}
+}
+
+func TestSetDiceBotByGameID_CaseInsensitive(t *testing.T) {
+	f := feeder.NewEmptyQueue()
+	b := New(f)
+
+	err := b.SetDiceBotByGameID("dicebot")
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	expected := "DiceBot"
+	actual := b.DiceBot.GameID()
+	if actual != expected {
+		t.Fatalf("got: %q, want: %q", actual, expected)
+	}
+}
+
+// 既定値と異なる評価の既定値および成功判定のフックを持つ、テスト用のダイスボット。
+type customGameDiceBot struct{}
+
+var _ dicebot.EvaluationDefaultsDiceBot = (*customGameDiceBot)(nil)
+var _ command.Check2D6Hook = (*customGameDiceBot)(nil)
+
+func (b *customGameDiceBot) GameID() string   { return "CustomGame" }
+func (b *customGameDiceBot) GameName() string { return "独自システム" }
+func (b *customGameDiceBot) Usage() string    { return "" }
+
+func (b *customGameDiceBot) ExecuteCommand(c string, ev *evaluator.Evaluator) (*command.Result, error) {
+	return nil, errors.New("no game-system-specific command")
+}
+
+func (b *customGameDiceBot) EvaluationDefaults() dicebot.EvaluationDefaults {
+	return dicebot.EvaluationDefaults{
+		RoundingMethod:  ast.ROUNDING_METHOD_ROUND,
+		SortBRollValues: true,
+		SortD66:         true,
+	}
+}
+
+func (b *customGameDiceBot) Check2D6(c *command.SuccessCheck, o *command.SuccessCheckOutcome) {
+	if c.DiceTotal == 12 {
+		o.SetCritical()
+	}
+}
+
+func TestExecuteBasicCommand_GameSystemDefaults(t *testing.T) {
+	testcases := []struct {
+		diceBot  dicebot.DiceBot
+		input    string
+		dice     []dice.Die
+		expected string
+	}{
+		{
+			diceBot:  &customGameDiceBot{},
+			input:    "C(10/4)",
+			expected: "CustomGame : C(10/4) ＞ 計算結果 ＞ 3",
+		},
+		{
+			diceBot:  &customGameDiceBot{},
+			input:    "C(9/4U)",
+			expected: "CustomGame : C(9/4U) ＞ 計算結果 ＞ 3",
+		},
+		{
+			diceBot:  &customGameDiceBot{},
+			input:    "3B6",
+			dice:     []dice.Die{{5, 6}, {1, 6}, {3, 6}},
+			expected: "CustomGame : (3B6) ＞ 1,3,5",
+		},
+		{
+			diceBot:  &customGameDiceBot{},
+			input:    "2D6>=7",
+			dice:     []dice.Die{{6, 6}, {6, 6}},
+			expected: "CustomGame : (2D6>=7) ＞ 12[6,6] ＞ 12 ＞ 成功 ＞ クリティカル",
+		},
+		{
+			input:    "C(10/4)",
+			expected: "DiceBot : C(10/4) ＞ 計算結果 ＞ 2",
+		},
+		{
+			input:    "3B6",
+			dice:     []dice.Die{{5, 6}, {1, 6}, {3, 6}},
+			expected: "DiceBot : (3B6) ＞ 5,1,3",
+		},
+		{
+			input:    "2D6>=7",
+			dice:     []dice.Die{{6, 6}, {6, 6}},
+			expected: "DiceBot : (2D6>=7) ＞ 12[6,6] ＞ 12 ＞ 成功",
+		},
+	}
+
+	for _, test := range testcases {
+		diceBot := test.diceBot
+		if diceBot == nil {
+			diceBot = New(feeder.NewEmptyQueue()).DiceBot
+		}
+
+		t.Run(fmt.Sprintf("%s/%q", diceBot.GameID(), test.input), func(t *testing.T) {
+			diceRoller := roller.New(feeder.NewQueue(test.dice))
+
+			result, err := executeBasicCommand(diceBot, diceRoller, locale.DEFAULT, test.input)
+			if err != nil {
+				t.Fatalf("コマンド実行エラー: %s", err)
+			}
+
+			if actual := result.Message(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewEvaluator_GameSystemDefaults(t *testing.T) {
+	ev := newEvaluator(&customGameDiceBot{}, roller.New(feeder.NewEmptyQueue()), locale.EN)
+
+	if ev.Locale != locale.EN {
+		t.Errorf("Locale: got %v, want %v", ev.Locale, locale.EN)
+	}
+
+	if ev.DefaultRoundingMethod != ast.ROUNDING_METHOD_ROUND {
+		t.Errorf("DefaultRoundingMethod: got %v, want %v",
+			ev.DefaultRoundingMethod, ast.ROUNDING_METHOD_ROUND)
+	}
+
+	if !ev.SortBRollValues {
+		t.Error("SortBRollValues が設定されていない")
+	}
+
+	if !ev.SortD66 {
+		t.Error("SortD66 が設定されていない")
+	}
+}
+
+// ダイスローラーおよびダイス供給機のエラーが、設定されたロケールのメッセージになることを確認する。
+func TestExecuteCommand_LocalizedRollError(t *testing.T) {
+	testcases := []struct {
+		locale   locale.Locale
+		command  string
+		expected string
+	}{
+		{locale.JA, "2D6", "取り出せるダイスがありません"},
+		{locale.EN, "2D6", "No dice left to feed"},
+		{locale.EN, "2B6", "No dice left to feed"},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%s/%s", test.locale, test.command), func(t *testing.T) {
+			b := New(feeder.NewEmptyQueue())
+			b.SetLocale(test.locale)
+
+			_, err := b.ExecuteCommand(test.command)
+			if err == nil {
+				t.Fatal("エラーが発生しなかった")
+				return
+			}
+
+			if actual := err.Error(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
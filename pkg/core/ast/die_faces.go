@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ダイスの面の指定を表すノード。
+// 加算ロールおよびバラバラロールの面数の代わりに使う。
+// 一次式。
+//
+// 登録されたダイスの定義の名前を指定する場合（{avg} など）と、
+// 各面の数値を列挙する場合（{1,1,2,2,3,4} など）がある。
+//
+// 列挙できるのは整数の面のみで、記号を持つ面（{fudge} の "+" など）は列挙できない。
+// 記号を持つ面のダイスは、dice.Definition を定義して登録し、名前で指定する。
+//
+// ダイスの定義はノードに保存せず、評価時に評価器が求める。
+type DieFaces struct {
+	NodeImpl
+	NonNilNode
+	ConstNode
+
+	// ダイスの定義の名前。
+	// 各面の数値を列挙した場合は空文字列。
+	Name string
+	// 列挙された各面の数値。
+	// ダイスの定義の名前を指定した場合はnil。
+	Values []int
+}
+
+// DieFaces がNodeを実装していることの確認。
+var _ Node = (*DieFaces)(nil)
+
+// NewNamedDieFaces は、ダイスの定義の名前を指定したノードを返す。
+//
+// name: ダイスの定義の名前
+func NewNamedDieFaces(name string) *DieFaces {
+	return &DieFaces{
+		NodeImpl: NodeImpl{
+			nodeType:            DIE_FACES_NODE,
+			isPrimaryExpression: true,
+		},
+
+		Name: name,
+	}
+}
+
+// NewListedDieFaces は、各面の数値を列挙したノードを返す。
+//
+// values: 各面の数値
+func NewListedDieFaces(values []int) *DieFaces {
+	n := &DieFaces{
+		NodeImpl: NodeImpl{
+			nodeType:            DIE_FACES_NODE,
+			isPrimaryExpression: true,
+		},
+
+		Values: make([]int, len(values)),
+	}
+
+	copy(n.Values, values)
+
+	return n
+}
+
+// Notation はダイスの面の指定の表記を返す。
+// 結果は "{名前}" または "{数値,数値,...}" という形式。
+func (n *DieFaces) Notation() string {
+	if n.Values == nil {
+		return "{" + n.Name + "}"
+	}
+
+	valueStrs := make([]string, 0, len(n.Values))
+	for _, v := range n.Values {
+		valueStrs = append(valueStrs, fmt.Sprintf("%d", v))
+	}
+
+	return "{" + strings.Join(valueStrs, ",") + "}"
+}
+
+// SExp はノードのS式を返す。
+func (n *DieFaces) SExp() string {
+	return n.Notation()
+}
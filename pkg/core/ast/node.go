@@ -47,6 +47,7 @@ const (
 	STRING_NODE
 	NIL_NODE
 	SUM_ROLL_RESULT_NODE
+	DIE_FACES_NODE
 )
 
 // ノードの種類とそれを表す文字列との対応。
@@ -85,6 +86,7 @@ var nodeTypeString = map[NodeType]string{
 	STRING_NODE:          "String",
 	NIL_NODE:             "Nil",
 	SUM_ROLL_RESULT_NODE: "SumRollResult",
+	DIE_FACES_NODE:       "DieFaces",
 }
 
 // 抽象構文木のノードのインターフェース。
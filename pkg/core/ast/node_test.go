@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"testing"
 )
 
@@ -38,6 +39,7 @@ func TestNode_Type(t *testing.T) {
 		{NewString(""), "String"},
 		{NilInstance(), "Nil"},
 		{NewSumRollResult(nil), "SumRollResult"},
+		{NewNamedDieFaces("avg"), "DieFaces"},
 	}
 
 	for _, test := range testcases {
@@ -84,6 +86,7 @@ func TestNode_IsNil(t *testing.T) {
 		{NewString(""), false},
 		{NilInstance(), true},
 		{NewSumRollResult(nil), false},
+		{NewNamedDieFaces("avg"), false},
 	}
 
 	for _, test := range testcases {
@@ -129,6 +132,7 @@ func TestNode_IsPrimaryExpression(t *testing.T) {
 		{NewString(""), true},
 		{NilInstance(), true},
 		{NewSumRollResult(nil), true},
+		{NewNamedDieFaces("avg"), true},
 	}
 
 	for _, test := range testcases {
@@ -154,6 +158,10 @@ func TestNode_IsVariable(t *testing.T) {
 			node:     NewInt(42),
 			expected: false,
 		},
+		{
+			node:     NewListedDieFaces([]int{1, 1, 2}),
+			expected: false,
+		},
 		{
 			node: NewDRoll(
 				NewInt(2),
@@ -402,3 +410,37 @@ func TestNode_IsVariable(t *testing.T) {
 		})
 	}
 }
+
+func TestDieFaces_SExp(t *testing.T) {
+	testcases := []struct {
+		node     *DieFaces
+		expected string
+	}{
+		{NewNamedDieFaces("avg"), "{avg}"},
+		{NewListedDieFaces([]int{1, 1, 2, 2, 3, 4}), "{1,1,2,2,3,4}"},
+		{NewListedDieFaces([]int{-1, 0, 1}), "{-1,0,1}"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.expected, func(t *testing.T) {
+			actual := test.node.SExp()
+			if actual != test.expected {
+				t.Errorf("got: %q, want: %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestSumRollResult_Faced(t *testing.T) {
+	avg, _ := dice.LookupDefinition("avg")
+	n := NewFacedSumRollResult([]dice.Die{{1, 6}, {4, 6}, {6, 6}}, avg)
+
+	if n.Value() != 11 {
+		t.Errorf("wrong value: got %d, want %d", n.Value(), 11)
+	}
+
+	expectedSExp := "(SumRollResult {avg} (Die 1 6) (Die 4 6) (Die 6 6))"
+	if n.SExp() != expectedSExp {
+		t.Errorf("wrong SExp: got %q, want %q", n.SExp(), expectedSExp)
+	}
+}
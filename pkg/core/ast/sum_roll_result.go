@@ -14,6 +14,9 @@ type SumRollResult struct {
 
 	// 振られたダイスの配列
 	Dice []dice.Die
+	// ダイスの定義。
+	// 通常のダイスを振った場合はnil。
+	Definition *dice.Definition
 }
 
 // SumRollResult がNodeを実装していることの確認。
@@ -37,12 +40,46 @@ func NewSumRollResult(rolledDice []dice.Die) *SumRollResult {
 	return r
 }
 
+// NewFacedSumRollResult は、定義したダイスを振った加算ロール結果のノードを返す。
+//
+// rolledDice: 振られたダイスのスライス,
+// definition: ダイスの定義。
+func NewFacedSumRollResult(
+	rolledDice []dice.Die,
+	definition *dice.Definition,
+) *SumRollResult {
+	r := NewSumRollResult(rolledDice)
+	r.Definition = definition
+
+	return r
+}
+
+// Faces は、出目に対応する面のスライスを返す。
+// 通常のダイスの場合、面の数値は出目となる。
+func (n *SumRollResult) Faces() []dice.Face {
+	faces := make([]dice.Face, 0, len(n.Dice))
+
+	for _, d := range n.Dice {
+		if n.Definition == nil {
+			faces = append(faces, dice.Face{Value: d.Value})
+			continue
+		}
+
+		// 出目は評価時に定義と照合済み
+		f, _ := n.Definition.Face(d)
+		faces = append(faces, f)
+	}
+
+	return faces
+}
+
 // Value は出目の合計を返す。
+// 定義したダイスの場合は、面の数値の合計を返す。
 func (n *SumRollResult) Value() int {
 	sum := 0
 
-	for _, d := range n.Dice {
-		sum += d.Value
+	for _, f := range n.Faces() {
+		sum += f.Value
 	}
 
 	return sum
@@ -56,5 +93,10 @@ func (n *SumRollResult) SExp() string {
 		diceStrs = append(diceStrs, d.SExp())
 	}
 
+	if n.Definition != nil {
+		return "(SumRollResult " + n.Definition.Notation() + " " +
+			strings.Join(diceStrs, " ") + ")"
+	}
+
 	return "(SumRollResult " + strings.Join(diceStrs, " ") + ")"
 }
@@ -23,10 +23,28 @@ func Execute(
 	node ast.Node,
 	gameID string,
 	evaluator *evaluator.Evaluator,
+) (*Result, error) {
+	return ExecuteWithHooks(node, gameID, nil, evaluator)
+}
+
+// ExecuteWithHooks は、ゲームシステム固有のフックを呼び出しながら、指定されたコマンドを実行する。
+//
+// hooks には、Check2D6Hook などのフックのインターフェースを実装した値（通常はダイスボット）を指定する。
+// 実装されていないフックは呼び出されない。nilの場合は Execute と同じ動作となる。
+//
+// node: コマンドのノード,
+// gameID: ゲーム識別子,
+// hooks: フック,
+// evaluator: 評価器。
+func ExecuteWithHooks(
+	node ast.Node,
+	gameID string,
+	hooks interface{},
+	evaluator *evaluator.Evaluator,
 ) (*Result, error) {
 	switch c := node.(type) {
 	case *ast.Command:
-		return executeCommand(c, gameID, evaluator)
+		return executeCommand(c, gameID, hooks, evaluator)
 	case *ast.BRollList:
 		return executeBRollList(c, gameID, evaluator)
 	case *ast.RRollList:
@@ -44,13 +62,14 @@ func Execute(
 func executeCommand(
 	node *ast.Command,
 	gameID string,
+	hooks interface{},
 	evaluator *evaluator.Evaluator,
 ) (*Result, error) {
 	switch node.Type() {
 	case ast.D_ROLL_EXPR_NODE:
 		return executeDRollExpr(node, gameID, evaluator)
 	case ast.D_ROLL_COMP_NODE:
-		return executeDRollComp(node, gameID, evaluator)
+		return executeDRollComp(node, gameID, hooks, evaluator)
 	case ast.B_ROLL_COMP_NODE:
 		return executeBRollComp(node, gameID, evaluator)
 	case ast.R_ROLL_COMP_NODE:
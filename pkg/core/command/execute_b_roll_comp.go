@@ -3,6 +3,7 @@ package command
 import (
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
@@ -15,6 +16,7 @@ func executeBRollComp(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 左辺の可変ノードの引数および右辺を評価する
@@ -34,11 +36,16 @@ func executeBRollComp(
 
 	resultObj := obj.(*object.BRollCompResult)
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+	result.Values = integerValues(resultObj.Values)
+	result.setNumOfSuccesses(resultObj.NumOfSuccesses.Value)
 
 	// 結果のメッセージを作る
 	result.appendMessagePart(notation.Parenthesize(infixNotation))
-	result.appendMessagePart(resultObj.Values.JoinedElements(","))
-	result.appendMessagePart("成功数" + resultObj.NumOfSuccesses.Inspect())
+	result.appendMessagePartWithDice(formatBRollValues(result, resultObj.Values))
+	result.appendMessagePart(result.Locale.Sprintf(
+		locale.MSG_NUM_OF_SUCCESSES, resultObj.NumOfSuccesses.Value))
 
 	return result, nil
 }
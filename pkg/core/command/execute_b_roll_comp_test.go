@@ -98,6 +98,11 @@ func TestExecuteBRollComp(t *testing.T) {
 			expected: "DiceBot : (2B6+3B8+5B12<5) ＞ 3,4,7,1,5,11,3,4,10,9 ＞ 成功数5",
 			dice:     []dice.Die{{3, 6}, {4, 6}, {7, 8}, {1, 8}, {5, 8}, {11, 12}, {3, 12}, {4, 12}, {10, 12}, {9, 12}},
 		},
+		{
+			input:    "4b{fudge}>=1",
+			expected: "DiceBot : (4B{fudge}>=1) ＞ -1,0,1,1 ＞ 成功数2",
+			dice:     []dice.Die{{1, 6}, {4, 6}, {5, 6}, {6, 6}},
+		},
 		{
 			input:    "[1...3]b6>3",
 			expected: "DiceBot : (1B6>3) ＞ 5 ＞ 成功数1",
@@ -15,6 +15,7 @@ func executeBRollList(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 可変ノードの引数を評価して整数に変換する
@@ -32,10 +33,29 @@ func executeBRollList(
 	arrayObj := obj.(*object.Array)
 
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+	result.Values = integerValues(arrayObj)
 
 	// 結果のメッセージを作る
 	result.appendMessagePart(notation.Parenthesize(infixNotation))
-	result.appendMessagePart(arrayObj.JoinedElements(","))
+	result.appendMessagePartWithDice(formatBRollValues(result, arrayObj))
 
 	return result, nil
 }
+
+// formatBRollValues はバラバラロールの出目を整形する。
+// 返り値は整形した出目と、その中のダイスの出目の範囲。
+func formatBRollValues(r *Result, values *object.Array) (string, []notation.DieSpan) {
+	b := newMessagePartBuilder(r, values.Length())
+
+	for i, v := range values.Elements {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		b.WriteDie(v.Inspect())
+	}
+
+	return b.String(), b.Dice()
+}
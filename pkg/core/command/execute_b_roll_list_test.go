@@ -58,6 +58,16 @@ func TestExecuteBRollList(t *testing.T) {
 			expected: "DiceBot : (2B6) ＞ 3,4",
 			dice:     []dice.Die{{3, 6}, {4, 6}},
 		},
+		{
+			input:    "2b{fudge}",
+			expected: "DiceBot : (2B{fudge}) ＞ -1,1",
+			dice:     []dice.Die{{2, 6}, {6, 6}},
+		},
+		{
+			input:    "1b6+2b{avg}",
+			expected: "DiceBot : (1B6+2B{avg}) ＞ 3,2,5",
+			dice:     []dice.Die{{3, 6}, {1, 6}, {6, 6}},
+		},
 		{
 			input:    "([1...3]+1)b6",
 			expected: "DiceBot : (3B6) ＞ 2,4,3",
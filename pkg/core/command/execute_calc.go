@@ -3,7 +3,9 @@ package command
 import (
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
+	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
 
 // executeCalc は計算を実行する。
@@ -14,6 +16,7 @@ func executeCalc(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 抽象構文木を中置表記に変換する
@@ -28,9 +31,13 @@ func executeCalc(
 		return nil, evalErr
 	}
 
+	if intObj, ok := obj.(*object.Integer); ok {
+		result.setTotal(intObj.Value)
+	}
+
 	// 結果のメッセージを作る
 	result.appendMessagePart(infixNotation)
-	result.appendMessagePart("計算結果")
+	result.appendMessagePart(result.Locale.Text(locale.MSG_CALC_RESULT))
 	result.appendMessagePart(obj.Inspect())
 
 	return result, nil
@@ -15,6 +15,7 @@ func executeChoice(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 中置表記を記録しておく
@@ -31,6 +32,8 @@ func executeChoice(
 
 	resultObj := obj.(*object.String)
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
 
 	// 結果のメッセージを作る
 	result.appendMessagePart(notation.Parenthesize(infixNotation))
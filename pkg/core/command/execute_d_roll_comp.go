@@ -10,13 +10,17 @@ import (
 )
 
 // executeDRollComp は加算ロール式の成功判定を実行する。
+//
+// 成功判定の後、ダイスの構成に対応するフックが hooks に実装されていれば、それを呼び出す。
 func executeDRollComp(
 	node *ast.Command,
 	gameID string,
+	hooks interface{},
 	evaluator *evaluator.Evaluator,
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	if node.Expression.Type() != ast.COMPARE_NODE {
@@ -31,12 +35,20 @@ func executeDRollComp(
 	}
 
 	// 加算ロールなどの可変ノードの値を決定する
-	infixNotation2, determineValuesErr :=
+	infixNotation2, dieSpans, determineValuesErr :=
 		determineCompareValues(compareNode, evaluator)
 	if determineValuesErr != nil {
 		return nil, determineValuesErr
 	}
 
+	// 左辺の最上位の加減算の各項を評価する
+	terms, evalTermsErr := evalTermValues(compareNode.Left(), evaluator)
+	if evalTermsErr != nil {
+		return nil, evalTermsErr
+	}
+
+	result.Terms = terms
+
 	// 左辺を評価する
 	leftObj, leftEvalErr := evaluator.EvalCompareLeft(compareNode)
 	if leftEvalErr != nil {
@@ -50,45 +62,60 @@ func executeDRollComp(
 	}
 
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
 
-	var successCheckResultMessage string
+	leftIntObj, leftIsInteger := leftObj.(*object.Integer)
+	if leftIsInteger {
+		result.setTotal(leftIntObj.Value)
+	}
 
 	boolObj, objIsBoolean := obj.(*object.Boolean)
 	if !objIsBoolean {
 		return nil, fmt.Errorf("DRollComp: result is not a Boolean: %s", obj.Type())
 	}
 
-	if boolObj.Value == true {
-		result.SuccessCheckResult = SUCCESS_CHECK_SUCCESS
-		successCheckResultMessage = "成功"
-	} else {
-		result.SuccessCheckResult = SUCCESS_CHECK_FAILURE
-		successCheckResultMessage = "失敗"
+	outcome := newSuccessCheckOutcome(boolObj.Value, result.Locale)
+
+	if hooks != nil && leftIsInteger {
+		// 目標値を求める
+		rightObj, rightEvalErr := evaluator.Eval(compareNode.Right())
+		if rightEvalErr != nil {
+			return nil, rightEvalErr
+		}
+
+		rightIntObj, rightIsInteger := rightObj.(*object.Integer)
+		if !rightIsInteger {
+			return nil, fmt.Errorf("DRollComp: right is not an Integer: %s", rightObj.Type())
+		}
+
+		check := newSuccessCheck(
+			leftIntObj.Value,
+			result.RolledDice,
+			compareNode.Operator(),
+			rightIntObj.Value,
+		)
+		callSuccessCheckHook(hooks, check, outcome)
 	}
 
 	result.appendMessagePart(notation.Parenthesize(infixNotation1))
-	result.appendMessagePart(infixNotation2)
+	result.appendMessagePartWithDice(infixNotation2, dieSpans)
 	result.appendMessagePart(leftObj.Inspect())
-	result.appendMessagePart(successCheckResultMessage)
+	outcome.apply(result)
 
 	return result, nil
 }
 
 // determineCompareValues は比較式の可変ノードの値を決定する。
-// 返り値はその結果の中置表記とエラー。
+// 返り値は左辺の結果の中置表記、その中のダイスの出目の範囲、エラー。
 func determineCompareValues(
 	node *ast.BasicInfixExpression,
 	evaluator *evaluator.Evaluator,
-) (string, error) {
+) (string, []notation.DieSpan, error) {
 	determineValuesErr := evaluator.DetermineValues(node)
 	if determineValuesErr != nil {
-		return "", determineValuesErr
-	}
-
-	infixNotation, infixNotationErr := notation.InfixNotation(node.Left(), true)
-	if infixNotationErr != nil {
-		return "", infixNotationErr
+		return "", nil, determineValuesErr
 	}
 
-	return infixNotation, nil
+	return notation.InfixNotationWithDice(node.Left(), true)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
+	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
 
 // executeDRollExpr は加算ロールを実行する。
@@ -14,6 +15,7 @@ func executeDRollExpr(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 加算ロールなどの可変ノードの引数を評価して整数に変換する
@@ -23,11 +25,19 @@ func executeDRollExpr(
 	}
 
 	// 加算ロールなどの可変ノードの値を決定する
-	infixNotation2, determineValuesErr := determineValues(node, evaluator)
+	infixNotation2, dieSpans, determineValuesErr := determineValues(node, evaluator)
 	if determineValuesErr != nil {
 		return nil, determineValuesErr
 	}
 
+	// 最上位の加減算の各項を評価する
+	terms, evalTermsErr := evalTermValues(node.Expression, evaluator)
+	if evalTermsErr != nil {
+		return nil, evalTermsErr
+	}
+
+	result.Terms = terms
+
 	// 変換された抽象構文木を評価する
 	obj, evalErr := evaluator.Eval(node)
 	if evalErr != nil {
@@ -35,27 +45,31 @@ func executeDRollExpr(
 	}
 
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+
+	if intObj, ok := obj.(*object.Integer); ok {
+		result.setTotal(intObj.Value)
+	}
 
 	// 結果のメッセージを作る
 	result.appendMessagePart(notation.Parenthesize(infixNotation1))
-	result.appendMessagePart(infixNotation2)
+	result.appendMessagePartWithDice(infixNotation2, dieSpans)
 	result.appendMessagePart(obj.Inspect())
 
 	return result, nil
 }
 
 // determineValues は加算ロールなどの可変ノードの値を決定する。
-// 返り値はその結果の中置表記とエラー。
-func determineValues(node ast.Node, evaluator *evaluator.Evaluator) (string, error) {
+// 返り値はその結果の中置表記、その中のダイスの出目の範囲、エラー。
+func determineValues(
+	node ast.Node,
+	evaluator *evaluator.Evaluator,
+) (string, []notation.DieSpan, error) {
 	determineValuesErr := evaluator.DetermineValues(node)
 	if determineValuesErr != nil {
-		return "", determineValuesErr
-	}
-
-	infixNotation, infixNotationErr := notation.InfixNotation(node, true)
-	if infixNotationErr != nil {
-		return "", infixNotationErr
+		return "", nil, determineValuesErr
 	}
 
-	return infixNotation, nil
+	return notation.InfixNotationWithDice(node, true)
 }
@@ -188,6 +188,21 @@ func TestExecuteDRollExpr(t *testing.T) {
 			expected: "DiceBot : (3D6-1) ＞ 14[5,5,4]-1 ＞ 13",
 			dice:     []dice.Die{{2, 4}, {3, 3}, {5, 6}, {5, 6}, {4, 6}},
 		},
+		{
+			input:    "3d{avg}+1",
+			expected: "DiceBot : (3D{avg}+1) ＞ 11[2,4,5]+1 ＞ 12",
+			dice:     []dice.Die{{1, 6}, {4, 6}, {6, 6}},
+		},
+		{
+			input:    "4D{fudge}",
+			expected: "DiceBot : (4D{fudge}) ＞ 1[-1,0,1,1] ＞ 1",
+			dice:     []dice.Die{{1, 6}, {3, 6}, {5, 6}, {6, 6}},
+		},
+		{
+			input:    "[1...3]D{1,2,3}",
+			expected: "DiceBot : (2D{1,2,3}) ＞ 4[1,3] ＞ 4",
+			dice:     []dice.Die{{2, 3}, {1, 3}, {3, 3}},
+		},
 	}
 
 	for _, test := range testcases {
@@ -3,6 +3,7 @@ package command
 import (
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
@@ -15,6 +16,7 @@ func executeRRollComp(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	compareNode := node.Expression.(*ast.BasicInfixExpression)
@@ -55,10 +57,15 @@ func executeRRollComp(
 
 	resultObj := obj.(*object.RRollCompResult)
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+	result.Values = flattenedIntegerValues(resultObj.ValueGroups)
+	result.setNumOfSuccesses(resultObj.NumOfSuccesses.Value)
 
 	// 結果のメッセージを作る
-	result.appendMessagePart(formatRRollValues(resultObj.ValueGroups))
-	result.appendMessagePart("成功数" + resultObj.NumOfSuccesses.Inspect())
+	result.appendMessagePartWithDice(formatRRollValues(result, resultObj.ValueGroups))
+	result.appendMessagePart(result.Locale.Sprintf(
+		locale.MSG_NUM_OF_SUCCESSES, resultObj.NumOfSuccesses.Value))
 
 	return result, nil
 }
@@ -1,8 +1,6 @@
 package command
 
 import (
-	"strings"
-
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
@@ -17,6 +15,7 @@ func executeRRollList(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 可変ノードの引数を評価して整数に変換する
@@ -42,20 +41,39 @@ func executeRRollList(
 
 	valueGroups := obj.(*object.Array)
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+	result.Values = flattenedIntegerValues(valueGroups)
 
 	// 結果のメッセージを作る
-	result.appendMessagePart(formatRRollValues(valueGroups))
+	result.appendMessagePartWithDice(formatRRollValues(result, valueGroups))
 
 	return result, nil
 }
 
 // formatRRollValues は個数振り足しロールの出目を整形する。
-func formatRRollValues(valueGroups *object.Array) string {
-	valueGroupStrs := make([]string, 0, valueGroups.Length())
+// 返り値は整形した出目と、その中のダイスの出目の範囲。
+func formatRRollValues(r *Result, valueGroups *object.Array) (string, []notation.DieSpan) {
+	numOfDice := 0
 	for _, valuesObj := range valueGroups.Elements {
-		valuesArray := valuesObj.(*object.Array)
-		valueGroupStrs = append(valueGroupStrs, valuesArray.JoinedElements(","))
+		numOfDice += valuesObj.(*object.Array).Length()
+	}
+
+	b := newMessagePartBuilder(r, numOfDice)
+
+	for i, valuesObj := range valueGroups.Elements {
+		if i > 0 {
+			b.WriteString(" + ")
+		}
+
+		for j, v := range valuesObj.(*object.Array).Elements {
+			if j > 0 {
+				b.WriteString(",")
+			}
+
+			b.WriteDie(v.Inspect())
+		}
 	}
 
-	return strings.Join(valueGroupStrs, " + ")
+	return b.String(), b.Dice()
 }
@@ -3,6 +3,7 @@ package command
 import (
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
@@ -15,6 +16,7 @@ func executeURollComp(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	compareNode := node.Expression.(*ast.BasicInfixExpression)
@@ -50,12 +52,16 @@ func executeURollComp(
 
 	resultObj := obj.(*object.URollCompResult)
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+	result.setNumOfSuccesses(resultObj.NumOfSuccesses.Value)
 
 	// 結果のメッセージを作る
-	result.appendMessagePart(
-		formatURollExprValueGroupsAndModifier(resultObj.RollResult),
+	result.appendMessagePartWithDice(
+		formatURollExprValueGroupsAndModifier(result, resultObj.RollResult),
 	)
-	result.appendMessagePart("成功数" + resultObj.NumOfSuccesses.Inspect())
+	result.appendMessagePart(result.Locale.Sprintf(
+		locale.MSG_NUM_OF_SUCCESSES, resultObj.NumOfSuccesses.Value))
 
 	return result, nil
 }
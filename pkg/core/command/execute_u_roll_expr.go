@@ -1,12 +1,9 @@
 package command
 
 import (
-	"bytes"
-	"fmt"
-	"strings"
-
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/notation"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
@@ -19,6 +16,7 @@ func executeURollExpr(
 ) (*Result, error) {
 	result := &Result{
 		GameID: gameID,
+		Locale: evaluator.Locale,
 	}
 
 	// 可変ノードの引数を評価して整数に変換する
@@ -44,10 +42,18 @@ func executeURollExpr(
 
 	uRollExprResult := obj.(*object.URollExprResult)
 	result.RolledDice = evaluator.RolledDice()
+	result.DieDefinitions = evaluator.RolledDieDefinitions()
+	result.Nonces = evaluator.RolledDieNonces()
+	result.setMaxAndSum(
+		uRollExprResult.MaxValue().Value,
+		uRollExprResult.SumOfValues().Value,
+	)
 
-	result.appendMessagePart(formatURollExprValueGroupsAndModifier(uRollExprResult))
-	result.appendMessagePart(fmt.Sprintf(
-		"%d/%d (最大/合計)",
+	result.appendMessagePartWithDice(
+		formatURollExprValueGroupsAndModifier(result, uRollExprResult),
+	)
+	result.appendMessagePart(result.Locale.Sprintf(
+		locale.MSG_MAX_AND_SUM,
 		uRollExprResult.MaxValue().Value,
 		uRollExprResult.SumOfValues().Value,
 	))
@@ -56,28 +62,51 @@ func executeURollExpr(
 }
 
 // formatURollExprValueGroupsAndModifier は上方無限ロールの出目および修正値を整形する。
-func formatURollExprValueGroupsAndModifier(result *object.URollExprResult) string {
+// 返り値は整形した出目および修正値と、その中のダイスの出目の範囲。
+//
+// 振り足しがなかったダイスは、合計がそのダイスの出目となるため、合計を出目として扱う。
+func formatURollExprValueGroupsAndModifier(
+	r *Result,
+	result *object.URollExprResult,
+) (string, []notation.DieSpan) {
 	valueGroups := result.ValueGroups()
 	n := valueGroups.Length()
 	sumOfGroups := result.SumOfGroups()
-	formattedValueGroups := make([]string, 0, n)
+
+	numOfDice := 0
+	for i := 0; i < n; i++ {
+		numOfDice += valueGroups.At(i).(*object.Array).Length()
+	}
+
+	b := newMessagePartBuilder(r, numOfDice)
 
 	for i := 0; i < n; i++ {
-		var buf bytes.Buffer
+		if i > 0 {
+			b.WriteString(",")
+		}
+
 		valuesArray := valueGroups.At(i).(*object.Array)
 
-		buf.WriteString(sumOfGroups.At(i).Inspect())
+		if valuesArray.Length() <= 1 {
+			b.WriteDie(sumOfGroups.At(i).Inspect())
+			continue
+		}
+
+		b.WriteString(sumOfGroups.At(i).Inspect())
+		b.WriteString("[")
+
+		for j, v := range valuesArray.Elements {
+			if j > 0 {
+				b.WriteString(",")
+			}
 
-		if valuesArray.Length() > 1 {
-			buf.WriteString(valuesArray.InspectWithoutSpaces())
+			b.WriteDie(v.Inspect())
 		}
 
-		formattedValueGroups = append(formattedValueGroups, buf.String())
+		b.WriteString("]")
 	}
 
-	var buf bytes.Buffer
-	buf.WriteString(strings.Join(formattedValueGroups, ","))
-	buf.WriteString(notation.FormatModifier(result.Modifier().Value))
+	b.WriteString(notation.FormatModifier(result.Modifier().Value))
 
-	return buf.String()
+	return b.String(), b.Dice()
 }
@@ -0,0 +1,267 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/core/notation"
+)
+
+// コマンドの実行結果を整形するインターフェース。
+type Formatter interface {
+	// Format は、コマンドの実行結果を整形した文字列を返す。
+	Format(r *Result) string
+}
+
+// 整形方法の名前と整形器との対応
+var nameToFormatter = map[string]Formatter{
+	"plain":   PlainFormatter{},
+	"discord": DiscordFormatter{},
+	"html":    HTMLFormatter{},
+	"ansi":    ANSIFormatter{},
+}
+
+// NewFormatterByName は指定された名前の整形器を返す。
+// 大文字小文字は区別しない。
+//
+// 利用できる名前は以下の通り。
+//
+// * "plain"  : 装飾なし（Message と同じ）
+//
+// * "discord": Discordのマークダウン
+//
+// * "html"   : HTML
+//
+// * "ansi"   : ANSIエスケープシーケンスによる端末の色付け
+func NewFormatterByName(name string) (Formatter, error) {
+	f, found := nameToFormatter[strings.ToLower(name)]
+	if !found {
+		return nil, fmt.Errorf("unknown formatter: %s", name)
+	}
+
+	return f, nil
+}
+
+// AvailableFormatterNames は利用可能な整形方法の名前のスライスを返す。
+// 名前は辞書順に並べられる。
+func AvailableFormatterNames() []string {
+	names := make([]string, 0, len(nameToFormatter))
+	for name := range nameToFormatter {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ダイスの出目の種類
+type dieKind int
+
+const (
+	// 通常の出目
+	dieKindNormal dieKind = iota
+	// 最大値の出目
+	dieKindMax
+	// 最小値の出目
+	dieKindMin
+)
+
+// 成功判定に関する部分の種類
+type outcomeKind int
+
+const (
+	// 成功
+	outcomeSuccess outcomeKind = iota
+	// 失敗
+	outcomeFailure
+	// クリティカル
+	outcomeCritical
+	// ファンブル
+	outcomeFumble
+	// スペシャル
+	outcomeSpecial
+)
+
+// 結果の各部分の装飾方法を表す構造体。
+// 装飾する関数には、エスケープ済みの文字列が渡される。
+type formatStyle struct {
+	// 文字列をエスケープする
+	escape func(s string) string
+	// ゲーム識別子を装飾する
+	gameID func(s string) string
+	// ダイスの出目を装飾する
+	die func(s string, kind dieKind) string
+	// 最終的な結果を装飾する
+	total func(s string) string
+	// 成功判定に関する部分を装飾する
+	outcome func(s string, kind outcomeKind) string
+	// 整形したメッセージ全体を装飾する
+	message func(s string, r *Result) string
+}
+
+// formatWithStyle は、指定された装飾方法で実行結果を整形する。
+//
+// 最初の部分（コマンドの表記）以外について、以下のように装飾する。
+//
+// * 成功・失敗・クリティカルなどを表す部分は、成功判定に関する装飾をする。
+//
+// * MessagePartDice に記録された範囲の出目は、対応するダイスおよびその定義から
+// 出目の種類を決め、最大値や最小値の出目を装飾する。
+//
+// * 成功判定に関する部分を除く最後の部分は、ダイスの出目を含まなければ、
+// 最終的な結果として装飾する。
+func formatWithStyle(r *Result, style formatStyle) string {
+	outcomes := outcomeLabels(r)
+
+	lastValueIndex := -1
+	for i := len(r.MessageParts) - 1; i >= 1; i-- {
+		if _, isOutcome := outcomes[r.MessageParts[i]]; !isOutcome {
+			lastValueIndex = i
+			break
+		}
+	}
+
+	parts := make([]string, 0, len(r.MessageParts))
+	for i, part := range r.MessageParts {
+		spans := r.messagePartDice(i)
+
+		switch {
+		case i == 0:
+			parts = append(parts, style.escape(part))
+		case hasOutcome(outcomes, part):
+			parts = append(parts, style.outcome(style.escape(part), outcomes[part]))
+		case len(spans) > 0:
+			parts = append(parts, formatDice(part, spans, style))
+		case i == lastValueIndex:
+			parts = append(parts, style.total(style.escape(part)))
+		default:
+			parts = append(parts, style.escape(part))
+		}
+	}
+
+	message := style.gameID(style.escape(r.GameID)) + " : " +
+		strings.Join(parts, " ＞ ")
+
+	return style.message(message, r)
+}
+
+// outcomeLabels は、結果のロケールにおける成功判定に関する部分の文字列と種類との対応を返す。
+func outcomeLabels(r *Result) map[string]outcomeKind {
+	labels := map[string]outcomeKind{}
+
+	switch r.SuccessCheckResult {
+	case SUCCESS_CHECK_SUCCESS:
+		labels[r.Locale.Text(locale.MSG_SUCCESS)] = outcomeSuccess
+	case SUCCESS_CHECK_FAILURE:
+		labels[r.Locale.Text(locale.MSG_FAILURE)] = outcomeFailure
+	}
+
+	if r.IsCritical {
+		labels[r.Locale.Text(locale.MSG_CRITICAL)] = outcomeCritical
+	}
+
+	if r.IsFumble {
+		labels[r.Locale.Text(locale.MSG_FUMBLE)] = outcomeFumble
+	}
+
+	if r.IsSpecial {
+		labels[r.Locale.Text(locale.MSG_SPECIAL)] = outcomeSpecial
+	}
+
+	return labels
+}
+
+// hasOutcome は、部分が成功判定に関するものかどうかを返す。
+func hasOutcome(outcomes map[string]outcomeKind, part string) bool {
+	_, found := outcomes[part]
+	return found
+}
+
+// formatDice は、部分に含まれるダイスの出目を装飾する。
+//
+// 出目の範囲が部分の中に順に収まっていない場合は、エスケープのみ行う。
+func formatDice(part string, spans []notation.DieSpan, style formatStyle) string {
+	last := 0
+	for _, span := range spans {
+		if span.Start < last || span.End < span.Start || span.End > len(part) {
+			return style.escape(part)
+		}
+
+		last = span.End
+	}
+
+	var out strings.Builder
+
+	last = 0
+	for _, span := range spans {
+		out.WriteString(style.escape(part[last:span.Start]))
+		out.WriteString(style.die(
+			style.escape(part[span.Start:span.End]),
+			classifyDie(span.Die, span.Definition),
+		))
+		last = span.End
+	}
+	out.WriteString(style.escape(part[last:]))
+
+	return out.String()
+}
+
+// classifyDie は、ダイスの出目の種類を返す。
+//
+// 通常のダイスでは、面数と等しい出目を最大値、1の出目を最小値とする。
+// 定義したダイスでは、面の数値が定義の中で最大のものを最大値、最小のものを最小値とする。
+// すべての面の数値が等しい場合は、通常の出目とする。
+func classifyDie(d dice.Die, definition *dice.Definition) dieKind {
+	if definition == nil {
+		switch {
+		case d.Sides > 1 && d.Value == d.Sides:
+			return dieKindMax
+		case d.Sides > 1 && d.Value == 1:
+			return dieKindMin
+		default:
+			return dieKindNormal
+		}
+	}
+
+	f, err := definition.Face(d)
+	if err != nil {
+		return dieKindNormal
+	}
+
+	minValue, maxValue := f.Value, f.Value
+	for _, face := range definition.Faces {
+		if face.Value < minValue {
+			minValue = face.Value
+		}
+
+		if face.Value > maxValue {
+			maxValue = face.Value
+		}
+	}
+
+	switch {
+	case minValue == maxValue:
+		return dieKindNormal
+	case f.Value == maxValue:
+		return dieKindMax
+	case f.Value == minValue:
+		return dieKindMin
+	default:
+		return dieKindNormal
+	}
+}
+
+// 装飾なしの整形器。
+// Message と同じ文字列を返す。
+type PlainFormatter struct{}
+
+var _ Formatter = PlainFormatter{}
+
+// Format は、コマンドの実行結果を装飾せずに返す。
+func (PlainFormatter) Format(r *Result) string {
+	return r.Message()
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"strings"
+)
+
+const (
+	// 書式設定をリセットするエスケープシーケンス
+	ANSI_RESET = "\033[0m"
+	// 太字にするエスケープシーケンス
+	ANSI_BOLD = "\033[1m"
+	// 文字色を赤色にするエスケープシーケンス
+	ANSI_RED = "\033[31m"
+	// 文字色を緑色にするエスケープシーケンス
+	ANSI_GREEN = "\033[32m"
+	// 文字色を黄色にするエスケープシーケンス
+	ANSI_YELLOW = "\033[33m"
+	// 文字色をマゼンタにするエスケープシーケンス
+	ANSI_MAGENTA = "\033[35m"
+	// 文字色をシアンにするエスケープシーケンス
+	ANSI_CYAN = "\033[36m"
+
+	// シークレットロールであることを表すヘッダ文字列
+	ANSI_SECRET_HEADER = ANSI_YELLOW + "[secret]" + ANSI_RESET + " "
+)
+
+// メッセージに含まれるエスケープ文字を取り除く
+var ansiEscaper = strings.NewReplacer("\033", "")
+
+// ANSIエスケープシーケンスで端末向けに色付けする整形器。
+//
+// 最終的な結果を太字に、最大値の出目をシアンに、最小値の出目を赤色にする。
+// 成功は緑色、失敗は赤色、クリティカルは黄色の太字、ファンブルは赤色の太字、
+// スペシャルはマゼンタの太字で表示する。
+// シークレットロールの場合は、先頭に "[secret]" を付ける。
+type ANSIFormatter struct{}
+
+var _ Formatter = ANSIFormatter{}
+
+// Format は、コマンドの実行結果をANSIエスケープシーケンスで色付けする。
+func (ANSIFormatter) Format(r *Result) string {
+	return formatWithStyle(r, ansiStyle)
+}
+
+// 成功判定に関する部分の種類とエスケープシーケンスとの対応
+var outcomeKindToANSI = map[outcomeKind]string{
+	outcomeSuccess:  ANSI_GREEN,
+	outcomeFailure:  ANSI_RED,
+	outcomeCritical: ANSI_BOLD + ANSI_YELLOW,
+	outcomeFumble:   ANSI_BOLD + ANSI_RED,
+	outcomeSpecial:  ANSI_BOLD + ANSI_MAGENTA,
+}
+
+// ANSIエスケープシーケンスの装飾方法
+var ansiStyle = formatStyle{
+	escape: ansiEscaper.Replace,
+	gameID: func(s string) string {
+		return s
+	},
+	die: func(s string, kind dieKind) string {
+		switch kind {
+		case dieKindMax:
+			return ANSI_CYAN + s + ANSI_RESET
+		case dieKindMin:
+			return ANSI_RED + s + ANSI_RESET
+		}
+
+		return s
+	},
+	total: func(s string) string {
+		return ANSI_BOLD + s + ANSI_RESET
+	},
+	outcome: func(s string, kind outcomeKind) string {
+		return outcomeKindToANSI[kind] + s + ANSI_RESET
+	},
+	message: func(s string, r *Result) string {
+		if r.IsSecret {
+			return ANSI_SECRET_HEADER + s
+		}
+
+		return s
+	},
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"strings"
+)
+
+// Discordのマークダウンで特別な意味を持つ文字をエスケープする
+var discordEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"|", `\|`,
+	"`", "\\`",
+)
+
+// Discordのマークダウンの整形器。
+//
+// 最終的な結果と最大値の出目を太字に、最小値の出目を斜体にする。
+// クリティカルは太字と下線、ファンブルは斜体と下線で強調する。
+// シークレットロールの場合は、メッセージ全体をスポイラーとして隠す。
+type DiscordFormatter struct{}
+
+var _ Formatter = DiscordFormatter{}
+
+// Format は、コマンドの実行結果をDiscordのマークダウンとして整形する。
+func (DiscordFormatter) Format(r *Result) string {
+	return formatWithStyle(r, discordStyle)
+}
+
+// Discordのマークダウンの装飾方法
+var discordStyle = formatStyle{
+	escape: discordEscaper.Replace,
+	gameID: func(s string) string {
+		return s
+	},
+	die: func(s string, kind dieKind) string {
+		switch kind {
+		case dieKindMax:
+			return "**" + s + "**"
+		case dieKindMin:
+			return "*" + s + "*"
+		}
+
+		return s
+	},
+	total: func(s string) string {
+		return "**" + s + "**"
+	},
+	outcome: func(s string, kind outcomeKind) string {
+		switch kind {
+		case outcomeCritical:
+			return "__**" + s + "**__"
+		case outcomeFumble:
+			return "__*" + s + "*__"
+		case outcomeFailure:
+			return "*" + s + "*"
+		}
+
+		return "**" + s + "**"
+	},
+	message: func(s string, r *Result) string {
+		if r.IsSecret {
+			return "||" + s + "||"
+		}
+
+		return s
+	},
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"html"
+	"strings"
+)
+
+// HTMLの整形器。
+//
+// 文字列はすべてエスケープされるため、そのまま埋め込んでも安全である。
+// 各部分には "bcdice-" で始まるクラスが付けられるので、CSSで見た目を調整できる。
+//
+// * 全体: bcdice-result、成功判定などに応じて bcdice-success、bcdice-failure、
+// bcdice-critical、bcdice-fumble、bcdice-special、bcdice-secret
+//
+// * ゲーム識別子: bcdice-game-id
+//
+// * 出目: bcdice-die、最大値は bcdice-die-max、最小値は bcdice-die-min
+//
+// * 最終的な結果: bcdice-total
+type HTMLFormatter struct{}
+
+var _ Formatter = HTMLFormatter{}
+
+// Format は、コマンドの実行結果をHTMLとして整形する。
+func (HTMLFormatter) Format(r *Result) string {
+	return formatWithStyle(r, htmlStyle)
+}
+
+// 成功判定に関する部分の種類とクラスとの対応
+var outcomeKindToHTMLClass = map[outcomeKind]string{
+	outcomeSuccess:  "bcdice-success",
+	outcomeFailure:  "bcdice-failure",
+	outcomeCritical: "bcdice-critical",
+	outcomeFumble:   "bcdice-fumble",
+	outcomeSpecial:  "bcdice-special",
+}
+
+// HTMLの装飾方法
+var htmlStyle = formatStyle{
+	escape: html.EscapeString,
+	gameID: func(s string) string {
+		return `<span class="bcdice-game-id">` + s + `</span>`
+	},
+	die: func(s string, kind dieKind) string {
+		switch kind {
+		case dieKindMax:
+			return `<span class="bcdice-die bcdice-die-max">` + s + `</span>`
+		case dieKindMin:
+			return `<span class="bcdice-die bcdice-die-min">` + s + `</span>`
+		}
+
+		return `<span class="bcdice-die">` + s + `</span>`
+	},
+	total: func(s string) string {
+		return `<strong class="bcdice-total">` + s + `</strong>`
+	},
+	outcome: func(s string, kind outcomeKind) string {
+		return `<strong class="` + outcomeKindToHTMLClass[kind] + `">` + s + `</strong>`
+	},
+	message: func(s string, r *Result) string {
+		classes := []string{"bcdice-result"}
+
+		switch r.SuccessCheckResult {
+		case SUCCESS_CHECK_SUCCESS:
+			classes = append(classes, outcomeKindToHTMLClass[outcomeSuccess])
+		case SUCCESS_CHECK_FAILURE:
+			classes = append(classes, outcomeKindToHTMLClass[outcomeFailure])
+		}
+
+		if r.IsCritical {
+			classes = append(classes, outcomeKindToHTMLClass[outcomeCritical])
+		}
+
+		if r.IsFumble {
+			classes = append(classes, outcomeKindToHTMLClass[outcomeFumble])
+		}
+
+		if r.IsSpecial {
+			classes = append(classes, outcomeKindToHTMLClass[outcomeSpecial])
+		}
+
+		if r.IsSecret {
+			classes = append(classes, "bcdice-secret")
+		}
+
+		return `<span class="` + strings.Join(classes, " ") + `">` + s + `</span>`
+	},
+}
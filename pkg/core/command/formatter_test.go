@@ -0,0 +1,219 @@
+package command
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/notation"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+)
+
+// Discordのマークダウンの整形の例。
+func ExampleDiscordFormatter() {
+	r := &Result{
+		GameID:       "DiceBot",
+		MessageParts: []string{"(2D6>=7)", "7[6,1]", "7", "成功"},
+		RolledDice:   []dice.Die{{6, 6}, {1, 6}},
+		MessagePartDice: [][]notation.DieSpan{
+			nil,
+			{
+				{Start: 2, End: 3, Die: dice.Die{6, 6}},
+				{Start: 4, End: 5, Die: dice.Die{1, 6}},
+			},
+		},
+		SuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+	}
+
+	fmt.Println(DiscordFormatter{}.Format(r))
+	// Output: DiceBot : (2D6>=7) ＞ 7[**6**,*1*] ＞ **7** ＞ **成功**
+}
+
+func TestFormatter_Format(t *testing.T) {
+	testcases := []struct {
+		formatter Formatter
+		input     string
+		dice      []dice.Die
+		modify    func(r *Result)
+		expected  string
+	}{
+		{
+			formatter: PlainFormatter{},
+			input:     "2D6>=7",
+			dice:      []dice.Die{{6, 6}, {1, 6}},
+			modify:    func(r *Result) { r.IsSecret = true },
+			expected:  "DiceBot : (2D6>=7) ＞ 7[6,1] ＞ 7 ＞ 成功",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "2D6+1",
+			dice:      []dice.Die{{3, 6}, {4, 6}},
+			expected:  "DiceBot : (2D6+1) ＞ 7[3,4]+1 ＞ **8**",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "2D6*2",
+			dice:      []dice.Die{{6, 6}, {1, 6}},
+			expected:  `DiceBot : (2D6\*2) ＞ 7[**6**,*1*]\*2 ＞ **14**`,
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "4D{fudge}",
+			dice:      []dice.Die{{1, 6}, {3, 6}, {5, 6}, {6, 6}},
+			expected:  "DiceBot : (4D{fudge}) ＞ 1[*-1*,0,**1**,**1**] ＞ **1**",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "2D{avg}+1D6",
+			dice:      []dice.Die{{1, 6}, {6, 6}, {6, 6}},
+			expected:  "DiceBot : (2D{avg}+1D6) ＞ 7[*2*,**5**]+6[**6**] ＞ **13**",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "1D100<=50",
+			dice:      []dice.Die{{100, 100}},
+			modify:    func(r *Result) { r.SetFumble() },
+			expected:  "DiceBot : (1D100<=50) ＞ 100[**100**] ＞ **100** ＞ *失敗* ＞ __*ファンブル*__",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "3B6>=4",
+			dice:      []dice.Die{{1, 6}, {4, 6}, {6, 6}},
+			modify:    func(r *Result) { r.IsSecret = true },
+			expected:  "||DiceBot : (3B6>=4) ＞ *1*,4,**6** ＞ **成功数2**||",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "2U6[6]",
+			dice:      []dice.Die{{6, 6}, {2, 6}, {1, 6}},
+			expected:  "DiceBot : (2U6[6]) ＞ 8[**6**,2],*1* ＞ **8/9 (最大/合計)**",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "[2...3]B6",
+			dice:      []dice.Die{{1, 2}, {6, 6}, {1, 6}},
+			expected:  "DiceBot : (2B6) ＞ **6**,*1*",
+		},
+		{
+			formatter: DiscordFormatter{},
+			input:     "[1...2]D6",
+			dice:      []dice.Die{{2, 2}, {1, 6}, {6, 6}},
+			expected:  "DiceBot : (2D6) ＞ 7[*1*,**6**] ＞ **7**",
+		},
+		{
+			formatter: HTMLFormatter{},
+			input:     "1D100<=50",
+			dice:      []dice.Die{{1, 100}},
+			modify:    func(r *Result) { r.SetCritical() },
+			expected: `<span class="bcdice-result bcdice-success bcdice-critical">` +
+				`<span class="bcdice-game-id">DiceBot</span> : (1D100&lt;=50) ＞ ` +
+				`1[<span class="bcdice-die bcdice-die-min">1</span>] ＞ ` +
+				`<strong class="bcdice-total">1</strong> ＞ ` +
+				`<strong class="bcdice-success">成功</strong> ＞ ` +
+				`<strong class="bcdice-critical">クリティカル</strong></span>`,
+		},
+		{
+			formatter: HTMLFormatter{},
+			input:     "CHOICE[<b>,&]",
+			dice:      []dice.Die{{1, 2}},
+			modify:    func(r *Result) { r.IsSecret = true },
+			expected: `<span class="bcdice-result bcdice-secret">` +
+				`<span class="bcdice-game-id">DiceBot</span> : (CHOICE[&lt;b&gt;,&amp;]) ＞ ` +
+				`<strong class="bcdice-total">&lt;b&gt;</strong></span>`,
+		},
+		{
+			formatter: ANSIFormatter{},
+			input:     "2D6>=7",
+			dice:      []dice.Die{{6, 6}, {1, 6}},
+			expected: "DiceBot : (2D6>=7) ＞ 7[" +
+				ANSI_CYAN + "6" + ANSI_RESET + "," + ANSI_RED + "1" + ANSI_RESET + "] ＞ " +
+				ANSI_BOLD + "7" + ANSI_RESET + " ＞ " +
+				ANSI_GREEN + "成功" + ANSI_RESET,
+		},
+		{
+			formatter: ANSIFormatter{},
+			input:     "2R6>=5",
+			dice:      []dice.Die{{6, 6}, {2, 6}, {1, 6}},
+			modify:    func(r *Result) { r.IsSecret = true },
+			expected: ANSI_SECRET_HEADER + "DiceBot : (2R6[5]>=5) ＞ " +
+				ANSI_CYAN + "6" + ANSI_RESET + ",2 + " + ANSI_RED + "1" + ANSI_RESET + " ＞ " +
+				ANSI_BOLD + "成功数1" + ANSI_RESET,
+		},
+	}
+
+	for _, test := range testcases {
+		name := fmt.Sprintf("%T/%q[%s]",
+			test.formatter, test.input, dice.FormatDiceWithoutSpaces(test.dice))
+		t.Run(name, func(t *testing.T) {
+			root, parseErr := parser.Parse("test", []byte(test.input))
+			if parseErr != nil {
+				t.Fatalf("構文エラー: %s", parseErr)
+				return
+			}
+
+			dieFeeder := feeder.NewQueue(test.dice)
+			evaluator := evaluator.NewEvaluator(
+				roller.New(dieFeeder),
+				evaluator.NewEnvironment(),
+			)
+
+			result, execErr := Execute(root.(ast.Node), "DiceBot", evaluator)
+			if execErr != nil {
+				t.Fatalf("実行エラー: %s", execErr)
+				return
+			}
+
+			if test.modify != nil {
+				test.modify(result)
+			}
+
+			actual := test.formatter.Format(result)
+			if actual != test.expected {
+				t.Errorf("got:\n%q\nwant:\n%q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewFormatterByName(t *testing.T) {
+	testcases := []struct {
+		name     string
+		expected Formatter
+	}{
+		{"plain", PlainFormatter{}},
+		{"Discord", DiscordFormatter{}},
+		{"html", HTMLFormatter{}},
+		{"ANSI", ANSIFormatter{}},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := NewFormatterByName(test.name)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+			}
+
+			if reflect.TypeOf(f) != reflect.TypeOf(test.expected) {
+				t.Errorf("wrong type: got %T, want %T", f, test.expected)
+			}
+		})
+	}
+
+	if _, err := NewFormatterByName("unknown"); err == nil {
+		t.Error("未知の整形方法の整形器を取得できてしまった")
+	}
+}
+
+func TestAvailableFormatterNames(t *testing.T) {
+	expected := []string{"ansi", "discord", "html", "plain"}
+	actual := AvailableFormatterNames()
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/notation"
+)
+
+// ダイスの出目の範囲を記録しながらメッセージの部分を組み立てる構造体。
+//
+// 出目は、振られたダイスのうち最後の numOfDice 個と、振られた順に対応付けられる。
+type messagePartBuilder struct {
+	// 組み立て中のメッセージの部分
+	buf strings.Builder
+	// ダイスの出目の範囲
+	spans []notation.DieSpan
+	// 出目に対応付けるダイス
+	dice []dice.Die
+	// 出目に対応付けるダイスの定義
+	definitions []*dice.Definition
+	// 次に対応付けるダイスの位置
+	next int
+}
+
+// newMessagePartBuilder は、r に記録された振られたダイスのうち、最後の numOfDice 個の出目を
+// 並べる組み立て器を返す。
+//
+// 振られたダイスが numOfDice 個に満たない場合は、出目の範囲を記録しない。
+func newMessagePartBuilder(r *Result, numOfDice int) *messagePartBuilder {
+	b := &messagePartBuilder{}
+
+	start := len(r.RolledDice) - numOfDice
+	if start < 0 {
+		return b
+	}
+
+	b.dice = r.RolledDice[start:]
+	if len(r.DieDefinitions) == len(r.RolledDice) {
+		b.definitions = r.DieDefinitions[start:]
+	}
+
+	return b
+}
+
+// WriteString は、ダイスの出目を含まない文字列を追加する。
+func (b *messagePartBuilder) WriteString(s string) {
+	b.buf.WriteString(s)
+}
+
+// WriteDie は、次のダイスの出目を追加する。
+func (b *messagePartBuilder) WriteDie(s string) {
+	start := b.buf.Len()
+	b.buf.WriteString(s)
+
+	if b.next >= len(b.dice) {
+		return
+	}
+
+	var definition *dice.Definition
+	if b.definitions != nil {
+		definition = b.definitions[b.next]
+	}
+
+	b.spans = append(b.spans, notation.DieSpan{
+		Start:      start,
+		End:        b.buf.Len(),
+		Die:        b.dice[b.next],
+		Definition: definition,
+	})
+	b.next++
+}
+
+// String は、組み立てたメッセージの部分を返す。
+func (b *messagePartBuilder) String() string {
+	return b.buf.String()
+}
+
+// Dice は、メッセージの部分に含まれるダイスの出目の範囲を返す。
+func (b *messagePartBuilder) Dice() []notation.DieSpan {
+	return b.spans
+}
@@ -1,7 +1,11 @@
 package command
 
 import (
+	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/core/notation"
 	"strings"
 )
 
@@ -39,12 +43,49 @@ type Result struct {
 	GameID string
 	// メッセージの部分の配列
 	MessageParts []string
+	// メッセージの各部分に含まれるダイスの出目の範囲（MessagePartsと同じ順序）
+	//
+	// ダイスの出目を含まない部分、および範囲が記録されていない部分はnil。
+	// 整形器は、この範囲の出目を装飾する。
+	MessagePartDice [][]notation.DieSpan
 	// 振られたダイス
 	RolledDice []dice.Die
+	// 振られたダイスの定義（RolledDiceと同じ順序。通常のダイスの場合はnil）
+	//
+	// 定義したダイスが振られなかった場合はnil。
+	DieDefinitions []*dice.Definition
+	// 振られた各ダイスの導出に使われたノンス（RolledDiceと同じ順序）
+	//
+	// ダイス供給機がノンスを返さない場合（feeder.ProvablyFair 以外）はnil。
+	Nonces []uint64
 	// 成功判定の結果
 	SuccessCheckResult SuccessCheckResultType
+	// クリティカル（決定的成功）かどうか
+	IsCritical bool
+	// ファンブル（自動失敗）かどうか
+	IsFumble bool
+	// スペシャル（特別な成功）かどうか
+	IsSpecial bool
+	// 最終的な数値（数値が得られないコマンドの場合はnil）
+	Total *int
+	// 加算ロール式の最上位の加減算の各項の値（符号を含む）
+	//
+	// 例えば "2D6-1D4+1" の場合、2D6、-1D4、1 の値が順に格納される。
+	Terms []int
+	// 成功数（成功数カウント以外のコマンドの場合はnil）
+	NumOfSuccesses *int
+	// 上方無限ロールの最大値（上方無限ロール式以外のコマンドの場合はnil）
+	MaxValue *int
+	// 上方無限ロールの合計（上方無限ロール式以外のコマンドの場合はnil）
+	SumOfValues *int
+	// バラバラロールおよび個数振り足しロールの出目の配列
+	Values []int
+	// メッセージのロケール
+	Locale locale.Locale
 	// シークレットロールかどうか
 	IsSecret bool
+	// コマンドに続けて入力されたコメント
+	Comment string
 }
 
 // JoinedMessageParts は、メッセージの部分を結合したものを返す。
@@ -57,7 +98,230 @@ func (r *Result) Message() string {
 	return r.GameID + " : " + r.JoinedMessageParts()
 }
 
+// VerifyProvablyFair は、公開されたサーバシードを使用して、実行結果の振られたダイスを検証する。
+// 各ダイスは、実行結果に記録されたノンスで導出されたものと比較する。
+//
+// ノンスが記録されていない（feeder.ProvablyFair 以外のダイス供給機で振られた）場合はエラーを返す。
+//
+// commitment: ダイスロール前に公開されたコミットメント,
+// serverSeed: 公開されたサーバシード,
+// clientSeed: クライアントシード。
+func (r *Result) VerifyProvablyFair(commitment string, serverSeed []byte, clientSeed string) error {
+	if r.Nonces == nil && len(r.RolledDice) > 0 {
+		return fmt.Errorf("ノンスが記録されていません")
+	}
+
+	return feeder.VerifyProvablyFairDice(commitment, serverSeed, clientSeed, r.RolledDice, r.Nonces)
+}
+
+// SetCritical は、クリティカル（決定的成功）として記録する。
+// 成功判定の結果は成功となり、メッセージの末尾に "クリティカル"（結果のロケールの文字列）が追加される。
+// ファンブルの記録は取り消され、メッセージの "失敗" は "成功" に書き換えられる。
+// すでに記録されている場合は何もしない。
+func (r *Result) SetCritical() {
+	r.setOutcome(outcomeCritical)
+}
+
+// SetFumble は、ファンブル（自動失敗）として記録する。
+// 成功判定の結果は失敗となり、メッセージの末尾に "ファンブル"（結果のロケールの文字列）が追加される。
+// クリティカルおよびスペシャルの記録は取り消され、メッセージの "成功" は "失敗" に書き換えられる。
+// すでに記録されている場合は何もしない。
+func (r *Result) SetFumble() {
+	r.setOutcome(outcomeFumble)
+}
+
+// SetSpecial は、スペシャル（特別な成功）として記録する。
+// 成功判定の結果は成功となり、メッセージの末尾に "スペシャル"（結果のロケールの文字列）が追加される。
+// ファンブルの記録は取り消され、メッセージの "失敗" は "成功" に書き換えられる。
+// すでに記録されている場合は何もしない。
+func (r *Result) SetSpecial() {
+	r.setOutcome(outcomeSpecial)
+}
+
+// setOutcome は、クリティカル・ファンブル・スペシャルのいずれかを記録し、メッセージを書き換える。
+func (r *Result) setOutcome(kind outcomeKind) {
+	f := outcomeFlags{
+		result:   &r.SuccessCheckResult,
+		critical: &r.IsCritical,
+		fumble:   &r.IsFumble,
+		special:  &r.IsSpecial,
+		locale:   r.Locale,
+	}
+
+	label, removed, changed := f.set(kind)
+	if !changed {
+		return
+	}
+
+	partDice := make([][]notation.DieSpan, 0, len(r.MessageParts)+1)
+	for i, p := range r.MessageParts {
+		if containsString(removed, p) {
+			continue
+		}
+
+		partDice = append(partDice, r.messagePartDice(i))
+	}
+
+	r.MessageParts = append(f.rewriteParts(r.MessageParts, removed), label)
+	r.MessagePartDice = append(partDice, nil)
+}
+
+// 成功判定の結果とクリティカルなどのフラグへの参照をまとめた構造体。
+//
+// Result と SuccessCheckOutcome とで、クリティカルなどを記録する処理を共有するために使う。
+type outcomeFlags struct {
+	// 成功判定の結果
+	result *SuccessCheckResultType
+	// クリティカルかどうか
+	critical *bool
+	// ファンブルかどうか
+	fumble *bool
+	// スペシャルかどうか
+	special *bool
+	// メッセージのロケール
+	locale locale.Locale
+}
+
+// set は、kind（クリティカル・ファンブル・スペシャルのいずれか）を記録する。
+//
+// クリティカルとスペシャルは成功、ファンブルは失敗として成功判定の結果を設定し、
+// それと矛盾するフラグを取り消す。
+// kind を表すメッセージと、取り消したフラグを表すメッセージを返す。
+// すでに記録されている場合は、changed として偽を返す。
+func (f outcomeFlags) set(kind outcomeKind) (label string, removed []string, changed bool) {
+	switch kind {
+	case outcomeCritical:
+		if *f.critical {
+			return "", nil, false
+		}
+
+		*f.critical = true
+		*f.result = SUCCESS_CHECK_SUCCESS
+		removed = f.clear(f.fumble, locale.MSG_FUMBLE, removed)
+
+		return f.locale.Text(locale.MSG_CRITICAL), removed, true
+	case outcomeFumble:
+		if *f.fumble {
+			return "", nil, false
+		}
+
+		*f.fumble = true
+		*f.result = SUCCESS_CHECK_FAILURE
+		removed = f.clear(f.critical, locale.MSG_CRITICAL, removed)
+		removed = f.clear(f.special, locale.MSG_SPECIAL, removed)
+
+		return f.locale.Text(locale.MSG_FUMBLE), removed, true
+	case outcomeSpecial:
+		if *f.special {
+			return "", nil, false
+		}
+
+		*f.special = true
+		*f.result = SUCCESS_CHECK_SUCCESS
+		removed = f.clear(f.fumble, locale.MSG_FUMBLE, removed)
+
+		return f.locale.Text(locale.MSG_SPECIAL), removed, true
+	}
+
+	return "", nil, false
+}
+
+// clear は、フラグが真ならば偽にし、そのフラグを表すメッセージを removed に追加して返す。
+func (f outcomeFlags) clear(flag *bool, id locale.MessageID, removed []string) []string {
+	if !*flag {
+		return removed
+	}
+
+	*flag = false
+
+	return append(removed, f.locale.Text(id))
+}
+
+// verdict は、s が成功・失敗を表すメッセージならば、現在の成功判定の結果を表すメッセージを返す。
+// そうでなければ s をそのまま返す。
+func (f outcomeFlags) verdict(s string) string {
+	success := f.locale.Text(locale.MSG_SUCCESS)
+	failure := f.locale.Text(locale.MSG_FAILURE)
+
+	if s != success && s != failure {
+		return s
+	}
+
+	switch *f.result {
+	case SUCCESS_CHECK_SUCCESS:
+		return success
+	case SUCCESS_CHECK_FAILURE:
+		return failure
+	}
+
+	return s
+}
+
+// rewriteParts は、メッセージの部分のうち、成功・失敗を表すものを現在の成功判定の結果に合わせて書き換え、
+// removed に含まれるものを取り除いたスライスを返す。
+func (f outcomeFlags) rewriteParts(parts []string, removed []string) []string {
+	rewritten := make([]string, 0, len(parts)+1)
+
+	for _, p := range parts {
+		if containsString(removed, p) {
+			continue
+		}
+
+		rewritten = append(rewritten, f.verdict(p))
+	}
+
+	return rewritten
+}
+
+// containsString は、ss が s を含むかどうかを返す。
+func containsString(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setTotal は最終的な数値を設定する。
+func (r *Result) setTotal(total int) {
+	r.Total = &total
+}
+
+// setNumOfSuccesses は成功数を設定する。
+func (r *Result) setNumOfSuccesses(n int) {
+	r.NumOfSuccesses = &n
+}
+
+// setMaxAndSum は上方無限ロールの最大値と合計を設定する。
+func (r *Result) setMaxAndSum(maxValue int, sumOfValues int) {
+	r.MaxValue = &maxValue
+	r.SumOfValues = &sumOfValues
+}
+
 // appendMessagePart はメッセージの部分を追加する。
 func (r *Result) appendMessagePart(message string) {
+	r.appendMessagePartWithDice(message, nil)
+}
+
+// appendMessagePartWithDice は、含まれるダイスの出目の範囲とともにメッセージの部分を追加する。
+func (r *Result) appendMessagePartWithDice(message string, spans []notation.DieSpan) {
+	// MessageParts が直接設定されている場合に備えて、長さを揃える
+	for len(r.MessagePartDice) < len(r.MessageParts) {
+		r.MessagePartDice = append(r.MessagePartDice, nil)
+	}
+
 	r.MessageParts = append(r.MessageParts, message)
+	r.MessagePartDice = append(r.MessagePartDice, spans)
+}
+
+// messagePartDice は、i 番目のメッセージの部分に含まれるダイスの出目の範囲を返す。
+// 範囲が記録されていない場合はnilを返す。
+func (r *Result) messagePartDice(i int) []notation.DieSpan {
+	if i >= len(r.MessagePartDice) {
+		return nil
+	}
+
+	return r.MessagePartDice[i]
 }
@@ -0,0 +1,173 @@
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+// RESULT_JSON_SCHEMA_VERSION はコマンドの実行結果のJSONスキーマのバージョン。
+//
+// フィールドの追加ではバージョンを上げない。
+// フィールドの削除や意味の変更を行う場合にバージョンを上げる。
+const RESULT_JSON_SCHEMA_VERSION = 1
+
+// resultJSON はコマンドの実行結果のJSON表現。
+type resultJSON struct {
+	// スキーマのバージョン
+	SchemaVersion int `json:"schemaVersion"`
+	// ゲーム識別子
+	GameID string `json:"gameId"`
+	// 応答メッセージ
+	Text string `json:"text"`
+	// メッセージの部分の配列
+	MessageParts []string `json:"messageParts"`
+	// 振られたダイス
+	Dice []dieJSON `json:"dice"`
+	// 最終的な数値
+	Total *int `json:"total"`
+	// 加算ロール式の各項の値
+	Terms []int `json:"terms"`
+	// 成功数
+	NumOfSuccesses *int `json:"numOfSuccesses"`
+	// 上方無限ロールの最大値
+	MaxValue *int `json:"maxValue"`
+	// 上方無限ロールの合計
+	SumOfValues *int `json:"sumOfValues"`
+	// バラバラロールなどの出目の配列
+	Values []int `json:"values"`
+	// 成功判定の結果
+	SuccessCheckResult SuccessCheckResultType `json:"successCheckResult"`
+	// 成功したか
+	Success bool `json:"success"`
+	// 失敗したか
+	Failure bool `json:"failure"`
+	// クリティカルか
+	Critical bool `json:"critical"`
+	// ファンブルか
+	Fumble bool `json:"fumble"`
+	// スペシャルか
+	Special bool `json:"special"`
+	// シークレットロールかどうか
+	Secret bool `json:"secret"`
+	// メッセージのロケール
+	Locale string `json:"locale"`
+	// コメント
+	Comment string `json:"comment"`
+}
+
+// dieJSON はダイスのJSON表現。
+type dieJSON struct {
+	// 出目
+	Value int `json:"value"`
+	// ダイスの面の数
+	Sides int `json:"sides"`
+	// ダイスの定義の表記（定義したダイスの場合のみ）
+	Definition string `json:"definition,omitempty"`
+	// 出目に対応する面（定義したダイスの場合のみ）
+	Face *faceJSON `json:"face,omitempty"`
+	// 導出に使われたノンス（ノンスが記録されている場合のみ）
+	Nonce *uint64 `json:"nonce,omitempty"`
+}
+
+// faceJSON はダイスの面のJSON表現。
+type faceJSON struct {
+	// 数値
+	Value int `json:"value"`
+	// 記号
+	Symbols []string `json:"symbols"`
+	// 文字列表現
+	Text string `json:"text"`
+}
+
+// newDieJSON は、ダイスとその定義からJSON表現を作る。
+// 定義がnilの場合は、出目と面の数のみを出力する。
+func newDieJSON(d dice.Die, definition *dice.Definition) dieJSON {
+	j := dieJSON{Value: d.Value, Sides: d.Sides}
+
+	if definition == nil {
+		return j
+	}
+
+	j.Definition = definition.Notation()
+
+	if f, err := definition.Face(d); err == nil {
+		symbols := f.Symbols
+		if symbols == nil {
+			symbols = []string{}
+		}
+
+		j.Face = &faceJSON{Value: f.Value, Symbols: symbols, Text: f.String()}
+	}
+
+	return j
+}
+
+// MarshalText は成功判定結果を文字列に変換する。
+// JSONでは "SUCCESS" などの文字列として出力される。
+func (r SuccessCheckResultType) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// MarshalJSON は、コマンドの実行結果をJSONに変換する。
+//
+// スキーマのバージョンは schemaVersion に出力される。
+// 配列のフィールドは、要素がなくても null ではなく空配列として出力される。
+// 定義したダイスには、定義の表記 definition と出目に対応する面 face が付加される。
+// ノンスが記録されている場合は、各ダイスに nonce が付加される。
+// 数値が得られないコマンドの場合、total などの数値のフィールドは null となる。
+func (r *Result) MarshalJSON() ([]byte, error) {
+	messageParts := r.MessageParts
+	if messageParts == nil {
+		messageParts = []string{}
+	}
+
+	terms := r.Terms
+	if terms == nil {
+		terms = []int{}
+	}
+
+	values := r.Values
+	if values == nil {
+		values = []int{}
+	}
+
+	ds := make([]dieJSON, 0, len(r.RolledDice))
+	for i, d := range r.RolledDice {
+		var definition *dice.Definition
+		if i < len(r.DieDefinitions) {
+			definition = r.DieDefinitions[i]
+		}
+
+		j := newDieJSON(d, definition)
+		if i < len(r.Nonces) {
+			nonce := r.Nonces[i]
+			j.Nonce = &nonce
+		}
+
+		ds = append(ds, j)
+	}
+
+	return json.Marshal(resultJSON{
+		SchemaVersion:      RESULT_JSON_SCHEMA_VERSION,
+		GameID:             r.GameID,
+		Text:               r.Message(),
+		MessageParts:       messageParts,
+		Dice:               ds,
+		Total:              r.Total,
+		Terms:              terms,
+		NumOfSuccesses:     r.NumOfSuccesses,
+		MaxValue:           r.MaxValue,
+		SumOfValues:        r.SumOfValues,
+		Values:             values,
+		SuccessCheckResult: r.SuccessCheckResult,
+		Success:            r.SuccessCheckResult == SUCCESS_CHECK_SUCCESS,
+		Failure:            r.SuccessCheckResult == SUCCESS_CHECK_FAILURE,
+		Critical:           r.IsCritical,
+		Fumble:             r.IsFumble,
+		Special:            r.IsSpecial,
+		Secret:             r.IsSecret,
+		Locale:             r.Locale.String(),
+		Comment:            r.Comment,
+	})
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+)
+
+// ゴールデンファイルを更新するかどうか
+var updateGolden = flag.Bool("update", false, "ゴールデンファイルを更新する")
+
+func TestResult_MarshalJSON(t *testing.T) {
+	testcases := []struct {
+		golden   string
+		input    string
+		dice     []dice.Die
+		isSecret bool
+		comment  string
+		// 結果を設定する関数
+		modify func(r *Result)
+	}{
+		{
+			golden: "d_roll_expr",
+			input:  "2D6+1",
+			dice:   []dice.Die{{2, 6}, {6, 6}},
+		},
+		{
+			golden: "d_roll_comp_success",
+			input:  "2D6>=7",
+			dice:   []dice.Die{{3, 6}, {4, 6}},
+		},
+		{
+			golden: "d_roll_comp_failure",
+			input:  "2D6>=7",
+			dice:   []dice.Die{{1, 6}, {2, 6}},
+		},
+		{
+			golden: "calc",
+			input:  "C(1+2*3)",
+		},
+		{
+			golden: "b_roll_comp",
+			input:  "3B6>=4",
+			dice:   []dice.Die{{3, 6}, {4, 6}, {6, 6}},
+		},
+		{
+			golden: "u_roll_expr",
+			input:  "2U6[6]+1",
+			dice:   []dice.Die{{6, 6}, {3, 6}, {2, 6}},
+		},
+		{
+			golden: "choice",
+			input:  "CHOICE[A,B,C]",
+			dice:   []dice.Die{{2, 3}},
+		},
+		{
+			golden: "faced_dice",
+			input:  "4D{fudge}+1",
+			dice:   []dice.Die{{1, 6}, {3, 6}, {5, 6}, {6, 6}},
+		},
+		{
+			golden:   "secret_with_comment",
+			input:    "1D100<=50",
+			dice:     []dice.Die{{42, 100}},
+			isSecret: true,
+			comment:  "目星",
+		},
+		{
+			golden: "critical",
+			input:  "1D100<=50",
+			dice:   []dice.Die{{1, 100}},
+			modify: func(r *Result) {
+				r.SetCritical()
+			},
+		},
+		{
+			golden: "fumble",
+			input:  "1D100<=50",
+			dice:   []dice.Die{{100, 100}},
+			modify: func(r *Result) {
+				r.SetFumble()
+			},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.golden, func(t *testing.T) {
+			root, parseErr := parser.Parse("test", []byte(test.input))
+			if parseErr != nil {
+				t.Fatalf("構文エラー: %s", parseErr)
+				return
+			}
+
+			dieFeeder := feeder.NewQueue(test.dice)
+			evaluator := evaluator.NewEvaluator(
+				roller.New(dieFeeder),
+				evaluator.NewEnvironment(),
+			)
+
+			result, execErr := Execute(root.(ast.Node), "DiceBot", evaluator)
+			if execErr != nil {
+				t.Fatalf("実行エラー: %s", execErr)
+				return
+			}
+
+			result.IsSecret = test.isSecret
+			result.Comment = test.comment
+
+			if test.modify != nil {
+				test.modify(result)
+			}
+
+			j, marshalErr := json.MarshalIndent(result, "", "  ")
+			if marshalErr != nil {
+				t.Fatalf("JSON変換エラー: %s", marshalErr)
+				return
+			}
+			actual := append(j, '\n')
+
+			goldenPath := filepath.Join("testdata", "result_json", test.golden+".json")
+
+			if *updateGolden {
+				if err := ioutil.WriteFile(goldenPath, actual, 0644); err != nil {
+					t.Fatalf("ゴールデンファイルの書き込みエラー: %s", err)
+				}
+
+				return
+			}
+
+			expected, readErr := ioutil.ReadFile(goldenPath)
+			if readErr != nil {
+				t.Fatalf("ゴールデンファイルの読み込みエラー: %s", readErr)
+				return
+			}
+
+			if !bytes.Equal(actual, expected) {
+				t.Errorf("JSONがゴールデンファイルと異なる:\ngot:\n%s\nwant:\n%s",
+					actual, expected)
+			}
+		})
+	}
+}
+
+func TestResult_MarshalJSON_Empty(t *testing.T) {
+	r := &Result{GameID: "DiceBot"}
+
+	j, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("JSON変換エラー: %s", err)
+		return
+	}
+
+	expected := `{"schemaVersion":1,"gameId":"DiceBot","text":"DiceBot : ",` +
+		`"messageParts":[],"dice":[],"total":null,"terms":[],` +
+		`"numOfSuccesses":null,"maxValue":null,"sumOfValues":null,"values":[],` +
+		`"successCheckResult":"UNSPECIFIED","success":false,"failure":false,` +
+		`"critical":false,"fumble":false,"special":false,` +
+		`"secret":false,"locale":"ja","comment":""}`
+	if string(j) != expected {
+		t.Errorf("got %s, want %s", j, expected)
+	}
+}
@@ -0,0 +1,212 @@
+package command
+
+import (
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"testing"
+)
+
+func TestResult_SetOutcome(t *testing.T) {
+	testcases := []struct {
+		name string
+		// 初期状態のメッセージの部分（nilの場合は成功判定の結果を含まない）
+		messageParts []string
+		// 初期状態の成功判定の結果
+		successCheckResult SuccessCheckResultType
+		set                func(r *Result)
+		expectedMessage    string
+		expectedSuccess    SuccessCheckResultType
+		expectedIsCritical bool
+		expectedIsFumble   bool
+		expectedIsSpecial  bool
+	}{
+		{
+			name:               "Critical",
+			set:                func(r *Result) { r.SetCritical() },
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ クリティカル",
+			expectedSuccess:    SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical: true,
+		},
+		{
+			name:             "Fumble",
+			set:              func(r *Result) { r.SetFumble() },
+			expectedMessage:  "DiceBot : (1D100<=50) ＞ 1 ＞ ファンブル",
+			expectedSuccess:  SUCCESS_CHECK_FAILURE,
+			expectedIsFumble: true,
+		},
+		{
+			name:              "Special",
+			set:               func(r *Result) { r.SetSpecial() },
+			expectedMessage:   "DiceBot : (1D100<=50) ＞ 1 ＞ スペシャル",
+			expectedSuccess:   SUCCESS_CHECK_SUCCESS,
+			expectedIsSpecial: true,
+		},
+		{
+			name: "CriticalTwice",
+			set: func(r *Result) {
+				r.SetCritical()
+				r.SetCritical()
+			},
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ クリティカル",
+			expectedSuccess:    SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical: true,
+		},
+		{
+			name: "SpecialAndCritical",
+			set: func(r *Result) {
+				r.SetSpecial()
+				r.SetCritical()
+			},
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ スペシャル ＞ クリティカル",
+			expectedSuccess:    SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical: true,
+			expectedIsSpecial:  true,
+		},
+		{
+			name:               "CriticalAfterFailure",
+			messageParts:       []string{"(1D100<=50)", "1", "失敗"},
+			successCheckResult: SUCCESS_CHECK_FAILURE,
+			set:                func(r *Result) { r.SetCritical() },
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ 成功 ＞ クリティカル",
+			expectedSuccess:    SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical: true,
+		},
+		{
+			name:               "FumbleAfterSuccess",
+			messageParts:       []string{"(1D100<=50)", "1", "成功"},
+			successCheckResult: SUCCESS_CHECK_SUCCESS,
+			set:                func(r *Result) { r.SetFumble() },
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ 失敗 ＞ ファンブル",
+			expectedSuccess:    SUCCESS_CHECK_FAILURE,
+			expectedIsFumble:   true,
+		},
+		{
+			name:               "SpecialAfterFailure",
+			messageParts:       []string{"(1D100<=50)", "1", "失敗"},
+			successCheckResult: SUCCESS_CHECK_FAILURE,
+			set:                func(r *Result) { r.SetSpecial() },
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ 成功 ＞ スペシャル",
+			expectedSuccess:    SUCCESS_CHECK_SUCCESS,
+			expectedIsSpecial:  true,
+		},
+		{
+			name:               "FumbleAfterCritical",
+			messageParts:       []string{"(1D100<=50)", "1", "成功"},
+			successCheckResult: SUCCESS_CHECK_SUCCESS,
+			set: func(r *Result) {
+				r.SetSpecial()
+				r.SetCritical()
+				r.SetFumble()
+			},
+			expectedMessage:  "DiceBot : (1D100<=50) ＞ 1 ＞ 失敗 ＞ ファンブル",
+			expectedSuccess:  SUCCESS_CHECK_FAILURE,
+			expectedIsFumble: true,
+		},
+		{
+			name:               "CriticalAfterFumble",
+			messageParts:       []string{"(1D100<=50)", "1", "成功"},
+			successCheckResult: SUCCESS_CHECK_SUCCESS,
+			set: func(r *Result) {
+				r.SetFumble()
+				r.SetCritical()
+			},
+			expectedMessage:    "DiceBot : (1D100<=50) ＞ 1 ＞ 成功 ＞ クリティカル",
+			expectedSuccess:    SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical: true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			messageParts := test.messageParts
+			if messageParts == nil {
+				messageParts = []string{"(1D100<=50)", "1"}
+			}
+
+			r := &Result{
+				GameID:             "DiceBot",
+				MessageParts:       messageParts,
+				SuccessCheckResult: test.successCheckResult,
+			}
+
+			test.set(r)
+
+			if actual := r.Message(); actual != test.expectedMessage {
+				t.Errorf("異なるメッセージ: got %q, want %q", actual, test.expectedMessage)
+			}
+
+			if r.SuccessCheckResult != test.expectedSuccess {
+				t.Errorf("異なる成功判定の結果: got %s, want %s",
+					r.SuccessCheckResult, test.expectedSuccess)
+			}
+
+			if r.IsCritical != test.expectedIsCritical {
+				t.Errorf("IsCritical: got %t, want %t", r.IsCritical, test.expectedIsCritical)
+			}
+
+			if r.IsFumble != test.expectedIsFumble {
+				t.Errorf("IsFumble: got %t, want %t", r.IsFumble, test.expectedIsFumble)
+			}
+
+			if r.IsSpecial != test.expectedIsSpecial {
+				t.Errorf("IsSpecial: got %t, want %t", r.IsSpecial, test.expectedIsSpecial)
+			}
+		})
+	}
+}
+
+func TestResult_VerifyProvablyFair(t *testing.T) {
+	f := feeder.NewProvablyFair([]byte("server-seed"), "player1")
+	commitment := f.Commitment()
+	serverSeed := f.Reveal()
+
+	testcases := []struct {
+		name   string
+		dice   []dice.Die
+		nonces []uint64
+		err    bool
+	}{
+		{
+			name:   "正しいダイスとノンス",
+			dice:   []dice.Die{{5, 6}, {6, 6}},
+			nonces: []uint64{0, 2},
+		},
+		{
+			name: "ダイスなし",
+			dice: []dice.Die{},
+		},
+		{
+			name: "ノンスが記録されていない",
+			dice: []dice.Die{{4, 6}, {6, 6}},
+			err:  true,
+		},
+		{
+			name:   "ノンスが異なる",
+			dice:   []dice.Die{{5, 6}, {6, 6}},
+			nonces: []uint64{1, 2},
+			err:    true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			r := &Result{
+				RolledDice: test.dice,
+				Nonces:     test.nonces,
+			}
+
+			err := r.VerifyProvablyFair(commitment, serverSeed, "player1")
+			if test.err {
+				if err == nil {
+					t.Fatal("エラーが発生しなかった")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("検証エラー: %s", err)
+			}
+		})
+	}
+}
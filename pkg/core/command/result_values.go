@@ -0,0 +1,95 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/object"
+)
+
+// additiveTerms は、式の最上位の加減算の各項を左から順に返す。
+// 減算の右辺の項は、符号を反転させることを表すフラグとともに返される。
+//
+// 例えば "2D6-1D4+1" の場合、項は 2D6、1D4（符号反転）、1 となる。
+// 最上位が加減算ではない場合は、式全体を1つの項として返す。
+func additiveTerms(node ast.Node) (terms []ast.Node, negated []bool) {
+	switch node.Type() {
+	case ast.ADD_NODE, ast.SUBTRACT_NODE:
+		infixNode := node.(ast.InfixExpression)
+
+		terms, negated = additiveTerms(infixNode.Left())
+
+		terms = append(terms, infixNode.Right())
+		negated = append(negated, node.Type() == ast.SUBTRACT_NODE)
+
+		return terms, negated
+	default:
+		return []ast.Node{node}, []bool{false}
+	}
+}
+
+// evalTermValues は、値が決定された式の最上位の加減算の各項を評価し、
+// 符号を含めた値のスライスを返す。
+//
+// 各項の値の合計は、式全体の値と等しくなる。
+// 可変ノードの値が決定された後に呼び出すこと。
+func evalTermValues(node ast.Node, evaluator *evaluator.Evaluator) ([]int, error) {
+	terms, negated := additiveTerms(node)
+	values := make([]int, 0, len(terms))
+
+	for i, term := range terms {
+		obj, evalErr := evaluator.Eval(term)
+		if evalErr != nil {
+			return nil, evalErr
+		}
+
+		var value int
+		switch o := obj.(type) {
+		case *object.Integer:
+			value = o.Value
+		case *object.Face:
+			value = o.Face.Value
+		default:
+			return nil, fmt.Errorf("term is not an Integer: %s", obj.Type())
+		}
+
+		if negated[i] {
+			value = -value
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// integerValues は、整数またはダイスの面の配列を整数のスライスに変換する。
+// それ以外の要素は無視する。
+func integerValues(a *object.Array) []int {
+	values := make([]int, 0, a.Length())
+
+	for _, e := range a.Elements {
+		switch o := e.(type) {
+		case *object.Integer:
+			values = append(values, o.Value)
+		case *object.Face:
+			values = append(values, o.Face.Value)
+		}
+	}
+
+	return values
+}
+
+// flattenedIntegerValues は、出目のグループの配列を平坦化して整数のスライスに変換する。
+func flattenedIntegerValues(valueGroups *object.Array) []int {
+	values := []int{}
+
+	for _, g := range valueGroups.Elements {
+		if groupArray, ok := g.(*object.Array); ok {
+			values = append(values, integerValues(groupArray)...)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+)
+
+// intPtr は整数へのポインタを返す。
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestResult_Values(t *testing.T) {
+	testcases := []struct {
+		input                  string
+		dice                   []dice.Die
+		expectedTotal          *int
+		expectedTerms          []int
+		expectedNumOfSuccesses *int
+		expectedMaxValue       *int
+		expectedSumOfValues    *int
+		expectedValues         []int
+	}{
+		{
+			input:         "2D6-1D4+1",
+			dice:          []dice.Die{{5, 6}, {6, 6}, {2, 4}},
+			expectedTotal: intPtr(10),
+			expectedTerms: []int{11, -2, 1},
+		},
+		{
+			input:         "-2D6+1",
+			dice:          []dice.Die{{3, 6}, {4, 6}},
+			expectedTotal: intPtr(-6),
+			expectedTerms: []int{-7, 1},
+		},
+		{
+			input:         "(2D6+1)*2",
+			dice:          []dice.Die{{3, 6}, {4, 6}},
+			expectedTotal: intPtr(16),
+			expectedTerms: []int{16},
+		},
+		{
+			input:         "2D6/2+1D4",
+			dice:          []dice.Die{{3, 6}, {4, 6}, {4, 4}},
+			expectedTotal: intPtr(7),
+			expectedTerms: []int{3, 4},
+		},
+		{
+			input:         "2D6+3>=10",
+			dice:          []dice.Die{{3, 6}, {4, 6}},
+			expectedTotal: intPtr(10),
+			expectedTerms: []int{7, 3},
+		},
+		{
+			input:         "C(1+2*3)",
+			expectedTotal: intPtr(7),
+		},
+		{
+			input:          "3B6",
+			dice:           []dice.Die{{3, 6}, {4, 6}, {6, 6}},
+			expectedValues: []int{3, 4, 6},
+		},
+		{
+			input:                  "3B6>=4",
+			dice:                   []dice.Die{{3, 6}, {4, 6}, {6, 6}},
+			expectedNumOfSuccesses: intPtr(2),
+			expectedValues:         []int{3, 4, 6},
+		},
+		{
+			input:          "2R6[5]",
+			dice:           []dice.Die{{5, 6}, {1, 6}, {2, 6}},
+			expectedValues: []int{5, 1, 2},
+		},
+		{
+			input:                  "2R6>=5",
+			dice:                   []dice.Die{{5, 6}, {1, 6}, {6, 6}, {2, 6}},
+			expectedNumOfSuccesses: intPtr(2),
+			expectedValues:         []int{5, 1, 6, 2},
+		},
+		{
+			input:               "2U6[6]+1",
+			dice:                []dice.Die{{6, 6}, {3, 6}, {2, 6}},
+			expectedMaxValue:    intPtr(10),
+			expectedSumOfValues: intPtr(12),
+		},
+		{
+			input:                  "2U6[6]>=5",
+			dice:                   []dice.Die{{6, 6}, {3, 6}, {2, 6}},
+			expectedNumOfSuccesses: intPtr(1),
+		},
+		{
+			input: "CHOICE[A,B]",
+			dice:  []dice.Die{{1, 2}},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.input, func(t *testing.T) {
+			root, parseErr := parser.Parse("test", []byte(test.input))
+			if parseErr != nil {
+				t.Fatalf("構文エラー: %s", parseErr)
+				return
+			}
+
+			dieFeeder := feeder.NewQueue(test.dice)
+			evaluator := evaluator.NewEvaluator(
+				roller.New(dieFeeder),
+				evaluator.NewEnvironment(),
+			)
+
+			r, execErr := Execute(root.(ast.Node), "DiceBot", evaluator)
+			if execErr != nil {
+				t.Fatalf("実行エラー: %s", execErr)
+				return
+			}
+
+			assertIntPtr(t, "Total", r.Total, test.expectedTotal)
+			assertIntPtr(t, "NumOfSuccesses", r.NumOfSuccesses, test.expectedNumOfSuccesses)
+			assertIntPtr(t, "MaxValue", r.MaxValue, test.expectedMaxValue)
+			assertIntPtr(t, "SumOfValues", r.SumOfValues, test.expectedSumOfValues)
+
+			if !reflect.DeepEqual(r.Terms, test.expectedTerms) {
+				t.Errorf("Terms: got %v, want %v", r.Terms, test.expectedTerms)
+			}
+
+			if !reflect.DeepEqual(r.Values, test.expectedValues) {
+				t.Errorf("Values: got %v, want %v", r.Values, test.expectedValues)
+			}
+		})
+	}
+}
+
+// assertIntPtr は整数へのポインタが指す値が等しいことを確認する。
+func assertIntPtr(t *testing.T, name string, actual *int, expected *int) {
+	t.Helper()
+
+	switch {
+	case actual == nil && expected == nil:
+		return
+	case actual == nil:
+		t.Errorf("%s: got nil, want %d", name, *expected)
+	case expected == nil:
+		t.Errorf("%s: got %d, want nil", name, *actual)
+	case *actual != *expected:
+		t.Errorf("%s: got %d, want %d", name, *actual, *expected)
+	}
+}
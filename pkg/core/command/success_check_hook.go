@@ -0,0 +1,237 @@
+package command
+
+import (
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// 加算ロール式の成功判定の情報の構造体
+//
+// ゲームシステム固有の成功判定フックに渡される。
+type SuccessCheck struct {
+	// 左辺の値（修正値を含む）
+	Total int
+	// 出目の合計
+	DiceTotal int
+	// 振られたダイス
+	RolledDice []dice.Die
+	// ダイスの数
+	NumOfDice int
+	// ダイスの面の数（異なる面数のダイスが混在する場合は0）
+	Sides int
+	// 比較演算子
+	Operator string
+	// 目標値（右辺の値）
+	Target int
+}
+
+// DiceValues は出目の配列を返す。
+func (c *SuccessCheck) DiceValues() []int {
+	values := make([]int, 0, len(c.RolledDice))
+	for _, d := range c.RolledDice {
+		values = append(values, d.Value)
+	}
+
+	return values
+}
+
+// 成功判定の結果の構造体
+//
+// フックはこの構造体を書き換えることで、成功判定の結果を変更する。
+type SuccessCheckOutcome struct {
+	// 成功判定の結果
+	Result SuccessCheckResultType
+	// 成功判定の結果のメッセージ（空の場合は出力しない）
+	Message string
+	// 結果のメッセージに続けて追加するメッセージの部分
+	ExtraMessageParts []string
+	// クリティカル（決定的成功）かどうか
+	IsCritical bool
+	// ファンブル（自動失敗）かどうか
+	IsFumble bool
+	// スペシャル（特別な成功）かどうか
+	IsSpecial bool
+	// メッセージのロケール
+	Locale locale.Locale
+}
+
+// newSuccessCheckOutcome は、標準の成功判定の結果を返す。
+func newSuccessCheckOutcome(succeeded bool, l locale.Locale) *SuccessCheckOutcome {
+	o := &SuccessCheckOutcome{
+		Locale: l,
+	}
+
+	if succeeded {
+		o.Result = SUCCESS_CHECK_SUCCESS
+		o.Message = l.Text(locale.MSG_SUCCESS)
+	} else {
+		o.Result = SUCCESS_CHECK_FAILURE
+		o.Message = l.Text(locale.MSG_FAILURE)
+	}
+
+	return o
+}
+
+// AppendMessagePart はメッセージの部分を追加する。
+func (o *SuccessCheckOutcome) AppendMessagePart(message string) {
+	o.ExtraMessageParts = append(o.ExtraMessageParts, message)
+}
+
+// SetCritical は、クリティカル（決定的成功）として記録する。
+// 成功判定の結果は成功となり、メッセージの末尾に "クリティカル"（結果のロケールの文字列）が追加される。
+// ファンブルの記録は取り消され、結果のメッセージの "失敗" は "成功" に書き換えられる。
+// すでに記録されている場合は何もしない。
+func (o *SuccessCheckOutcome) SetCritical() {
+	o.setOutcome(outcomeCritical)
+}
+
+// SetFumble は、ファンブル（自動失敗）として記録する。
+// 成功判定の結果は失敗となり、メッセージの末尾に "ファンブル"（結果のロケールの文字列）が追加される。
+// クリティカルおよびスペシャルの記録は取り消され、結果のメッセージの "成功" は "失敗" に書き換えられる。
+// すでに記録されている場合は何もしない。
+func (o *SuccessCheckOutcome) SetFumble() {
+	o.setOutcome(outcomeFumble)
+}
+
+// SetSpecial は、スペシャル（特別な成功）として記録する。
+// 成功判定の結果は成功となり、メッセージの末尾に "スペシャル"（結果のロケールの文字列）が追加される。
+// ファンブルの記録は取り消され、結果のメッセージの "失敗" は "成功" に書き換えられる。
+// すでに記録されている場合は何もしない。
+func (o *SuccessCheckOutcome) SetSpecial() {
+	o.setOutcome(outcomeSpecial)
+}
+
+// setOutcome は、クリティカル・ファンブル・スペシャルのいずれかを記録し、メッセージを書き換える。
+// Result.setOutcome と同じ規則に従う。
+func (o *SuccessCheckOutcome) setOutcome(kind outcomeKind) {
+	f := outcomeFlags{
+		result:   &o.Result,
+		critical: &o.IsCritical,
+		fumble:   &o.IsFumble,
+		special:  &o.IsSpecial,
+		locale:   o.Locale,
+	}
+
+	label, removed, changed := f.set(kind)
+	if !changed {
+		return
+	}
+
+	o.Message = f.verdict(o.Message)
+	o.ExtraMessageParts = append(f.rewriteParts(o.ExtraMessageParts, removed), label)
+}
+
+// apply は成功判定の結果をコマンドの実行結果に反映する。
+func (o *SuccessCheckOutcome) apply(r *Result) {
+	r.SuccessCheckResult = o.Result
+	r.IsCritical = o.IsCritical
+	r.IsFumble = o.IsFumble
+	r.IsSpecial = o.IsSpecial
+
+	if o.Message != "" {
+		r.appendMessagePart(o.Message)
+	}
+
+	for _, m := range o.ExtraMessageParts {
+		r.appendMessagePart(m)
+	}
+}
+
+// 1D100 の成功判定のフック
+//
+// ダイスボットがこのインターフェースを実装すると、1D100 による成功判定の後に呼び出される。
+type Check1D100Hook interface {
+	// Check1D100 は 1D100 の成功判定の結果を変更する。
+	Check1D100(c *SuccessCheck, o *SuccessCheckOutcome)
+}
+
+// 1D20 の成功判定のフック
+//
+// ダイスボットがこのインターフェースを実装すると、1D20 による成功判定の後に呼び出される。
+type Check1D20Hook interface {
+	// Check1D20 は 1D20 の成功判定の結果を変更する。
+	Check1D20(c *SuccessCheck, o *SuccessCheckOutcome)
+}
+
+// nD10 の成功判定のフック
+//
+// ダイスボットがこのインターフェースを実装すると、10面ダイスのみによる成功判定の後に呼び出される。
+type CheckND10Hook interface {
+	// CheckND10 は nD10 の成功判定の結果を変更する。
+	CheckND10(c *SuccessCheck, o *SuccessCheckOutcome)
+}
+
+// 2D6 の成功判定のフック
+//
+// ダイスボットがこのインターフェースを実装すると、2D6 による成功判定の後に呼び出される。
+type Check2D6Hook interface {
+	// Check2D6 は 2D6 の成功判定の結果を変更する。
+	Check2D6(c *SuccessCheck, o *SuccessCheckOutcome)
+}
+
+// nD6 の成功判定のフック
+//
+// ダイスボットがこのインターフェースを実装すると、6面ダイスのみによる成功判定の後に呼び出される。
+// 2D6 の場合は Check2D6Hook が優先される。
+type CheckND6Hook interface {
+	// CheckND6 は nD6 の成功判定の結果を変更する。
+	CheckND6(c *SuccessCheck, o *SuccessCheckOutcome)
+}
+
+// callSuccessCheckHook は、ダイスの構成に対応する成功判定のフックを呼び出す。
+// 対応するフックが実装されていない場合は何もしない。
+//
+// 呼び出すフックの優先順位は 1D100、1D20、nD10、2D6、nD6 の順。
+func callSuccessCheckHook(hooks interface{}, c *SuccessCheck, o *SuccessCheckOutcome) {
+	if hooks == nil {
+		return
+	}
+
+	if h, ok := hooks.(Check1D100Hook); ok && c.NumOfDice == 1 && c.Sides == 100 {
+		h.Check1D100(c, o)
+		return
+	}
+
+	if h, ok := hooks.(Check1D20Hook); ok && c.NumOfDice == 1 && c.Sides == 20 {
+		h.Check1D20(c, o)
+		return
+	}
+
+	if h, ok := hooks.(CheckND10Hook); ok && c.NumOfDice > 0 && c.Sides == 10 {
+		h.CheckND10(c, o)
+		return
+	}
+
+	if h, ok := hooks.(Check2D6Hook); ok && c.NumOfDice == 2 && c.Sides == 6 {
+		h.Check2D6(c, o)
+		return
+	}
+
+	if h, ok := hooks.(CheckND6Hook); ok && c.NumOfDice > 0 && c.Sides == 6 {
+		h.CheckND6(c, o)
+		return
+	}
+}
+
+// newSuccessCheck は成功判定の情報を作る。
+func newSuccessCheck(total int, rolledDice []dice.Die, operator string, target int) *SuccessCheck {
+	c := &SuccessCheck{
+		Total:      total,
+		RolledDice: rolledDice,
+		NumOfDice:  len(rolledDice),
+		Operator:   operator,
+		Target:     target,
+	}
+
+	for i, d := range rolledDice {
+		c.DiceTotal += d.Value
+
+		if i == 0 {
+			c.Sides = d.Sides
+		} else if c.Sides != d.Sides {
+			c.Sides = 0
+		}
+	}
+
+	return c
+}
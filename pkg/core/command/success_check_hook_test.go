@@ -0,0 +1,339 @@
+package command
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+)
+
+// テスト用の成功判定フック
+type fakeSuccessCheckHooks struct {
+	// 最後に渡された成功判定の情報
+	lastCheck *SuccessCheck
+}
+
+var _ Check1D100Hook = (*fakeSuccessCheckHooks)(nil)
+var _ Check2D6Hook = (*fakeSuccessCheckHooks)(nil)
+var _ CheckND10Hook = (*fakeSuccessCheckHooks)(nil)
+var _ CheckND6Hook = (*fakeSuccessCheckHooks)(nil)
+
+// Check1D100 は、出目が5以下ならば決定的成功、96以上ならば致命的失敗とする。
+func (h *fakeSuccessCheckHooks) Check1D100(c *SuccessCheck, o *SuccessCheckOutcome) {
+	h.lastCheck = c
+
+	switch {
+	case c.DiceTotal <= 5:
+		o.Result = SUCCESS_CHECK_SUCCESS
+		o.IsCritical = true
+		o.Message = "決定的成功"
+	case c.DiceTotal >= 96:
+		o.Result = SUCCESS_CHECK_FAILURE
+		o.IsFumble = true
+		o.Message = "致命的失敗"
+	}
+}
+
+// Check2D6 は、6ゾロならばクリティカル、1ゾロならばファンブルとする。
+func (h *fakeSuccessCheckHooks) Check2D6(c *SuccessCheck, o *SuccessCheckOutcome) {
+	h.lastCheck = c
+
+	switch c.DiceTotal {
+	case 12:
+		o.SetCritical()
+	case 2:
+		o.SetFumble()
+	}
+}
+
+// CheckND10 は、目標値との差を追加する。
+func (h *fakeSuccessCheckHooks) CheckND10(c *SuccessCheck, o *SuccessCheckOutcome) {
+	h.lastCheck = c
+
+	o.AppendMessagePart(fmt.Sprintf("差分%d", c.Total-c.Target))
+}
+
+// CheckND6 は、結果のメッセージを出力しない。
+func (h *fakeSuccessCheckHooks) CheckND6(c *SuccessCheck, o *SuccessCheckOutcome) {
+	h.lastCheck = c
+
+	o.Message = ""
+	o.AppendMessagePart("nD6")
+}
+
+func TestExecuteWithHooks_DRollComp(t *testing.T) {
+	testcases := []struct {
+		input                      string
+		dice                       []dice.Die
+		expectedMessage            string
+		expectedSuccessCheckResult SuccessCheckResultType
+		expectedIsCritical         bool
+		expectedIsFumble           bool
+		expectedCheck              *SuccessCheck
+	}{
+		{
+			input:                      "1D100<=50",
+			dice:                       []dice.Die{{3, 100}},
+			expectedMessage:            "DiceBot : (1D100<=50) ＞ 3[3] ＞ 3 ＞ 決定的成功",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical:         true,
+			expectedCheck: &SuccessCheck{
+				Total:      3,
+				DiceTotal:  3,
+				RolledDice: []dice.Die{{3, 100}},
+				NumOfDice:  1,
+				Sides:      100,
+				Operator:   "<=",
+				Target:     50,
+			},
+		},
+		{
+			input:                      "1D100<=50",
+			dice:                       []dice.Die{{98, 100}},
+			expectedMessage:            "DiceBot : (1D100<=50) ＞ 98[98] ＞ 98 ＞ 致命的失敗",
+			expectedSuccessCheckResult: SUCCESS_CHECK_FAILURE,
+			expectedIsFumble:           true,
+		},
+		{
+			input:                      "1D100<=50",
+			dice:                       []dice.Die{{42, 100}},
+			expectedMessage:            "DiceBot : (1D100<=50) ＞ 42[42] ＞ 42 ＞ 成功",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+		},
+		{
+			input:                      "2D6+1>=10",
+			dice:                       []dice.Die{{6, 6}, {6, 6}},
+			expectedMessage:            "DiceBot : (2D6+1>=10) ＞ 12[6,6]+1 ＞ 13 ＞ 成功 ＞ クリティカル",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical:         true,
+			expectedCheck: &SuccessCheck{
+				Total:      13,
+				DiceTotal:  12,
+				RolledDice: []dice.Die{{6, 6}, {6, 6}},
+				NumOfDice:  2,
+				Sides:      6,
+				Operator:   ">=",
+				Target:     10,
+			},
+		},
+		{
+			input:                      "2D6+10>=10",
+			dice:                       []dice.Die{{1, 6}, {1, 6}},
+			expectedMessage:            "DiceBot : (2D6+10>=10) ＞ 2[1,1]+10 ＞ 12 ＞ 失敗 ＞ ファンブル",
+			expectedSuccessCheckResult: SUCCESS_CHECK_FAILURE,
+			expectedIsFumble:           true,
+		},
+		{
+			// 失敗していてもクリティカルならば成功となる
+			input:                      "2D6>=13",
+			dice:                       []dice.Die{{6, 6}, {6, 6}},
+			expectedMessage:            "DiceBot : (2D6>=13) ＞ 12[6,6] ＞ 12 ＞ 成功 ＞ クリティカル",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical:         true,
+		},
+		{
+			input:                      "3D10>=15",
+			dice:                       []dice.Die{{5, 10}, {6, 10}, {7, 10}},
+			expectedMessage:            "DiceBot : (3D10>=15) ＞ 18[5,6,7] ＞ 18 ＞ 成功 ＞ 差分3",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+		},
+		{
+			input:                      "3D6>=10",
+			dice:                       []dice.Die{{1, 6}, {2, 6}, {3, 6}},
+			expectedMessage:            "DiceBot : (3D6>=10) ＞ 6[1,2,3] ＞ 6 ＞ nD6",
+			expectedSuccessCheckResult: SUCCESS_CHECK_FAILURE,
+		},
+		{
+			// 異なる面数のダイスが混在する場合はフックを呼び出さない
+			input:                      "1D6+1D10>=10",
+			dice:                       []dice.Die{{6, 6}, {6, 10}},
+			expectedMessage:            "DiceBot : (1D6+1D10>=10) ＞ 6[6]+6[6] ＞ 12 ＞ 成功",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+		},
+		{
+			// 対応するフックが実装されていない場合
+			input:                      "1D20>=10",
+			dice:                       []dice.Die{{20, 20}},
+			expectedMessage:            "DiceBot : (1D20>=10) ＞ 20[20] ＞ 20 ＞ 成功",
+			expectedSuccessCheckResult: SUCCESS_CHECK_SUCCESS,
+		},
+	}
+
+	for _, test := range testcases {
+		name := fmt.Sprintf(
+			"%q[%s]",
+			test.input,
+			dice.FormatDiceWithoutSpaces(test.dice),
+		)
+		t.Run(name, func(t *testing.T) {
+			root, parseErr := parser.Parse("test", []byte(test.input))
+			if parseErr != nil {
+				t.Fatalf("構文エラー: %s", parseErr)
+				return
+			}
+
+			dieFeeder := feeder.NewQueue(test.dice)
+			evaluator := evaluator.NewEvaluator(
+				roller.New(dieFeeder),
+				evaluator.NewEnvironment(),
+			)
+
+			hooks := &fakeSuccessCheckHooks{}
+			r, execErr := ExecuteWithHooks(root.(ast.Node), "DiceBot", hooks, evaluator)
+			if execErr != nil {
+				t.Fatalf("コマンド実行エラー: %s", execErr)
+				return
+			}
+
+			actualMessage := r.Message()
+			if actualMessage != test.expectedMessage {
+				t.Errorf("結果のメッセージが異なる: got %q, want %q",
+					actualMessage, test.expectedMessage)
+			}
+
+			if r.SuccessCheckResult != test.expectedSuccessCheckResult {
+				t.Errorf("成功判定結果が異なる: got %q, want %q",
+					r.SuccessCheckResult, test.expectedSuccessCheckResult)
+			}
+
+			if r.IsCritical != test.expectedIsCritical {
+				t.Errorf("IsCritical: got %t, want %t", r.IsCritical, test.expectedIsCritical)
+			}
+
+			if r.IsFumble != test.expectedIsFumble {
+				t.Errorf("IsFumble: got %t, want %t", r.IsFumble, test.expectedIsFumble)
+			}
+
+			if test.expectedCheck != nil && !reflect.DeepEqual(hooks.lastCheck, test.expectedCheck) {
+				t.Errorf("成功判定の情報が異なる: got %+v, want %+v",
+					hooks.lastCheck, test.expectedCheck)
+			}
+		})
+	}
+}
+
+func TestExecuteWithHooks_NilHooks(t *testing.T) {
+	root, parseErr := parser.Parse("test", []byte("2D6>=7"))
+	if parseErr != nil {
+		t.Fatalf("構文エラー: %s", parseErr)
+	}
+
+	dieFeeder := feeder.NewQueue([]dice.Die{{6, 6}, {6, 6}})
+	evaluator := evaluator.NewEvaluator(
+		roller.New(dieFeeder),
+		evaluator.NewEnvironment(),
+	)
+
+	r, execErr := ExecuteWithHooks(root.(ast.Node), "DiceBot", nil, evaluator)
+	if execErr != nil {
+		t.Fatalf("コマンド実行エラー: %s", execErr)
+	}
+
+	expected := "DiceBot : (2D6>=7) ＞ 12[6,6] ＞ 12 ＞ 成功"
+	if r.Message() != expected {
+		t.Errorf("got %q, want %q", r.Message(), expected)
+	}
+
+	if r.IsCritical {
+		t.Error("フックがないのにクリティカルになった")
+	}
+}
+
+func TestSuccessCheckOutcome_SetOutcome(t *testing.T) {
+	testcases := []struct {
+		name               string
+		succeeded          bool
+		set                func(o *SuccessCheckOutcome)
+		expectedMessage    string
+		expectedExtra      []string
+		expectedResult     SuccessCheckResultType
+		expectedIsCritical bool
+		expectedIsFumble   bool
+		expectedIsSpecial  bool
+	}{
+		{
+			name:               "CriticalAfterFailure",
+			succeeded:          false,
+			set:                func(o *SuccessCheckOutcome) { o.SetCritical() },
+			expectedMessage:    "成功",
+			expectedExtra:      []string{"クリティカル"},
+			expectedResult:     SUCCESS_CHECK_SUCCESS,
+			expectedIsCritical: true,
+		},
+		{
+			name:             "FumbleAfterSuccess",
+			succeeded:        true,
+			set:              func(o *SuccessCheckOutcome) { o.SetFumble() },
+			expectedMessage:  "失敗",
+			expectedExtra:    []string{"ファンブル"},
+			expectedResult:   SUCCESS_CHECK_FAILURE,
+			expectedIsFumble: true,
+		},
+		{
+			name:      "FumbleAfterSpecialAndCritical",
+			succeeded: true,
+			set: func(o *SuccessCheckOutcome) {
+				o.SetSpecial()
+				o.AppendMessagePart("追加")
+				o.SetCritical()
+				o.SetFumble()
+			},
+			expectedMessage:  "失敗",
+			expectedExtra:    []string{"追加", "ファンブル"},
+			expectedResult:   SUCCESS_CHECK_FAILURE,
+			expectedIsFumble: true,
+		},
+		{
+			name:      "SpecialAfterFumble",
+			succeeded: false,
+			set: func(o *SuccessCheckOutcome) {
+				o.SetFumble()
+				o.SetSpecial()
+			},
+			expectedMessage:   "成功",
+			expectedExtra:     []string{"スペシャル"},
+			expectedResult:    SUCCESS_CHECK_SUCCESS,
+			expectedIsSpecial: true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			o := newSuccessCheckOutcome(test.succeeded, locale.DEFAULT)
+
+			test.set(o)
+
+			if o.Message != test.expectedMessage {
+				t.Errorf("Message: got %q, want %q", o.Message, test.expectedMessage)
+			}
+
+			if !reflect.DeepEqual(o.ExtraMessageParts, test.expectedExtra) {
+				t.Errorf("ExtraMessageParts: got %q, want %q",
+					o.ExtraMessageParts, test.expectedExtra)
+			}
+
+			if o.Result != test.expectedResult {
+				t.Errorf("Result: got %s, want %s", o.Result, test.expectedResult)
+			}
+
+			if o.IsCritical != test.expectedIsCritical {
+				t.Errorf("IsCritical: got %t, want %t", o.IsCritical, test.expectedIsCritical)
+			}
+
+			if o.IsFumble != test.expectedIsFumble {
+				t.Errorf("IsFumble: got %t, want %t", o.IsFumble, test.expectedIsFumble)
+			}
+
+			if o.IsSpecial != test.expectedIsSpecial {
+				t.Errorf("IsSpecial: got %t, want %t", o.IsSpecial, test.expectedIsSpecial)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package dice
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ダイスの面を表す構造体。
+type Face struct {
+	// 数値
+	Value int
+	// 記号
+	Symbols []string
+}
+
+// String は面の文字列表現を返す。
+//
+// 記号がなければ数値を、数値が0で記号があれば記号を、
+// 数値と記号の両方があれば数値に続けて記号を返す。
+func (f Face) String() string {
+	symbols := strings.Join(f.Symbols, "")
+
+	if symbols == "" {
+		return fmt.Sprintf("%d", f.Value)
+	}
+
+	if f.Value == 0 {
+		return symbols
+	}
+
+	return fmt.Sprintf("%d%s", f.Value, symbols)
+}
+
+// 各面を定義したダイスの構造体。
+//
+// 通常のダイスは1から面数までの数値の面を持つが、定義したダイスは任意の面を持てる。
+// 例えば、平均値ダイスは {2, 3, 3, 4, 4, 5} という面を持つ。
+//
+// 定義したダイスを振るときは、面数がlen(Faces)の通常のダイスを振り、
+// 出目をFacesの添字（1始まり）として面を選ぶ。
+type Definition struct {
+	// 名前
+	Name string
+	// 面
+	Faces []Face
+}
+
+// ダイスの定義の名前を表す正規表現
+var definitionNameRe = regexp.MustCompile(`\A[A-Za-z_][A-Za-z0-9_]*\z`)
+
+// NewDefinition は新しいダイスの定義を返す。
+// 名前が不正な場合や、面がない場合はエラーを返す。
+//
+// name: 名前（英字またはアンダースコアで始まり、英数字またはアンダースコアが続くもの）,
+// faces: 面。
+func NewDefinition(name string, faces []Face) (*Definition, error) {
+	if !definitionNameRe.MatchString(name) {
+		return nil, fmt.Errorf("NewDefinition: %q: ダイスの定義の名前が不正です", name)
+	}
+
+	d, err := newDefinition(faces)
+	if err != nil {
+		return nil, fmt.Errorf("NewDefinition: %s: %s", name, err)
+	}
+
+	d.Name = name
+
+	return d, nil
+}
+
+// NewDefinitionFromValues は、数値のみを持つ面からなるダイスの定義を返す。
+// 名前が空の場合は、無名のダイスの定義を返す。
+//
+// name: 名前,
+// values: 各面の数値。
+func NewDefinitionFromValues(name string, values ...int) (*Definition, error) {
+	faces := make([]Face, 0, len(values))
+	for _, v := range values {
+		faces = append(faces, Face{Value: v})
+	}
+
+	if name == "" {
+		d, err := newDefinition(faces)
+		if err != nil {
+			return nil, fmt.Errorf("NewDefinitionFromValues: %s", err)
+		}
+
+		return d, nil
+	}
+
+	return NewDefinition(name, faces)
+}
+
+// newDefinition は無名のダイスの定義を返す。
+func newDefinition(faces []Face) (*Definition, error) {
+	if len(faces) < 1 {
+		return nil, fmt.Errorf("面がありません")
+	}
+
+	d := &Definition{
+		Faces: make([]Face, len(faces)),
+	}
+	copy(d.Faces, faces)
+
+	return d, nil
+}
+
+// Sides はダイスの面の数を返す。
+func (d *Definition) Sides() int {
+	return len(d.Faces)
+}
+
+// Face は、振られたダイスの出目に対応する面を返す。
+// ダイスの面数が定義と一致しない場合や、出目が範囲外の場合はエラーを返す。
+func (d *Definition) Face(die Die) (Face, error) {
+	if die.Sides != d.Sides() {
+		return Face{}, fmt.Errorf("%s: %d/%d: ダイスの面数が定義と一致しません",
+			d.Notation(), die.Value, die.Sides)
+	}
+
+	if die.Value < 1 || die.Value > die.Sides {
+		return Face{}, fmt.Errorf("%s: %d/%d: 出目が範囲外です",
+			d.Notation(), die.Value, die.Sides)
+	}
+
+	return d.Faces[die.Value-1], nil
+}
+
+// Notation はダイスの定義の表記を返す。
+// 名前があれば "{名前}" を、なければ "{数値,数値,...}" を返す。
+func (d *Definition) Notation() string {
+	if d.Name != "" {
+		return "{" + d.Name + "}"
+	}
+
+	faceStrs := make([]string, 0, len(d.Faces))
+	for _, f := range d.Faces {
+		faceStrs = append(faceStrs, f.String())
+	}
+
+	return "{" + strings.Join(faceStrs, ",") + "}"
+}
+
+// 定義したダイスの出目を表す構造体。
+type FacedDie struct {
+	// 実際に振られたダイス
+	Die Die
+	// ダイスの定義
+	Definition *Definition
+}
+
+// Face は出目に対応する面を返す。
+func (d FacedDie) Face() (Face, error) {
+	return d.Definition.Face(d.Die)
+}
+
+// String は出目の文字列表現を返す。
+func (d FacedDie) String() string {
+	return fmt.Sprintf("<FacedDie %s %s>", d.faceString(), d.Definition.Notation())
+}
+
+// faceString は出目に対応する面の文字列表現を返す。
+// 面を決められない場合は "?" を返す。
+func (d FacedDie) faceString() string {
+	f, err := d.Face()
+	if err != nil {
+		return "?"
+	}
+
+	return f.String()
+}
+
+// ダイスの定義を名前で登録するレジストリの構造体。
+// 名前の大文字と小文字は区別しない。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+type Registry struct {
+	// 定義へのアクセスを保護する
+	mu sync.RWMutex
+	// 小文字にした名前とダイスの定義との対応
+	definitions map[string]*Definition
+}
+
+// NewRegistry は空のレジストリを返す。
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: map[string]*Definition{},
+	}
+}
+
+// Register はダイスの定義を登録する。
+// 名前がない場合や、同じ名前の定義が既に登録されている場合はエラーを返す。
+func (r *Registry) Register(d *Definition) error {
+	if d.Name == "" {
+		return fmt.Errorf("Register: 無名のダイスの定義は登録できません")
+	}
+
+	key := strings.ToLower(d.Name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.definitions[key]; exists {
+		return fmt.Errorf("Register: %s: ダイスの定義が既に登録されています", d.Name)
+	}
+
+	r.definitions[key] = d
+
+	return nil
+}
+
+// Lookup は指定した名前のダイスの定義を返す。
+// 見つからなかった場合、2番目の返り値はfalseとなる。
+func (r *Registry) Lookup(name string) (*Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, found := r.definitions[strings.ToLower(name)]
+
+	return d, found
+}
+
+// Names は登録されているダイスの定義の名前を昇順に並べて返す。
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.definitions))
+	for _, d := range r.definitions {
+		names = append(names, d.Name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// 既定のレジストリ。
+// 以下のダイスの定義が登録されている。
+//
+// * avg  : 平均値ダイス {2, 3, 3, 4, 4, 5}
+// * fudge: Fudgeダイス {-1, -1, 0, 0, 1, 1}
+var DefaultRegistry = newDefaultRegistry()
+
+// newDefaultRegistry は既定のレジストリを作る。
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+
+	builtins := []struct {
+		name   string
+		values []int
+	}{
+		{"avg", []int{2, 3, 3, 4, 4, 5}},
+		{"fudge", []int{-1, -1, 0, 0, 1, 1}},
+	}
+
+	for _, b := range builtins {
+		d, err := NewDefinitionFromValues(b.name, b.values...)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := r.Register(d); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
+
+// RegisterDefinition は、既定のレジストリにダイスの定義を登録する。
+func RegisterDefinition(d *Definition) error {
+	return DefaultRegistry.Register(d)
+}
+
+// LookupDefinition は、既定のレジストリから指定した名前のダイスの定義を返す。
+func LookupDefinition(name string) (*Definition, bool) {
+	return DefaultRegistry.Lookup(name)
+}
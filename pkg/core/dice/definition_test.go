@@ -0,0 +1,211 @@
+package dice
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// 記号を持つダイスの定義の例。
+func ExampleNewDefinition() {
+	d, err := NewDefinition("star", []Face{
+		{Value: 0},
+		{Value: 0},
+		{Value: 1, Symbols: []string{"★"}},
+		{Value: 2, Symbols: []string{"★", "★"}},
+	})
+	if err != nil {
+		return
+	}
+
+	// 4面ダイスを振って3が出た場合
+	f, _ := d.Face(Die{3, 4})
+	fmt.Println(f.String())
+	// Output: 1★
+}
+
+// 定義したダイスの出目の列の整形例。
+func ExampleFormatDice_definitions() {
+	avg, _ := LookupDefinition("avg")
+
+	ds := []Die{{1, 6}, {6, 6}, {3, 6}}
+	fmt.Println(FormatDice(ds, avg, avg, nil))
+	// Output: 1/6(2), 6/6(5), 3/6
+}
+
+func TestFace_String(t *testing.T) {
+	testcases := []struct {
+		face     Face
+		expected string
+	}{
+		{Face{Value: 3}, "3"},
+		{Face{Value: -1}, "-1"},
+		{Face{Value: 0}, "0"},
+		{Face{Value: 0, Symbols: []string{"★"}}, "★"},
+		{Face{Value: 0, Symbols: []string{"★", "☆"}}, "★☆"},
+		{Face{Value: 2, Symbols: []string{"★"}}, "2★"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.expected, func(t *testing.T) {
+			actual := test.face.String()
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewDefinition_Error(t *testing.T) {
+	testcases := []struct {
+		name  string
+		faces []Face
+	}{
+		{"", []Face{{Value: 1}}},
+		{"1d", []Face{{Value: 1}}},
+		{"a-b", []Face{{Value: 1}}},
+		{"empty", []Face{}},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%q", test.name), func(t *testing.T) {
+			if _, err := NewDefinition(test.name, test.faces); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
+
+func TestDefinition_Face(t *testing.T) {
+	d, err := NewDefinitionFromValues("", 1, 1, 2, 2, 3, 4)
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	expected := []int{1, 1, 2, 2, 3, 4}
+	for i, e := range expected {
+		f, err := d.Face(Die{i + 1, 6})
+		if err != nil {
+			t.Fatalf("#%d: got err: %s", i, err)
+		}
+
+		if f.Value != e {
+			t.Errorf("#%d: got %d, want %d", i, f.Value, e)
+		}
+	}
+}
+
+func TestDefinition_Face_Error(t *testing.T) {
+	d, err := NewDefinitionFromValues("", 1, 1, 2, 2, 3, 4)
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	testcases := []Die{{1, 4}, {0, 6}, {7, 6}}
+	for _, die := range testcases {
+		t.Run(die.String(), func(t *testing.T) {
+			if _, err := d.Face(die); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
+
+func TestDefinition_Notation(t *testing.T) {
+	named, _ := NewDefinitionFromValues("abc", 1, 2)
+	listed, _ := NewDefinitionFromValues("", 1, 1, -2)
+
+	testcases := []struct {
+		d        *Definition
+		expected string
+	}{
+		{named, "{abc}"},
+		{listed, "{1,1,-2}"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.expected, func(t *testing.T) {
+			actual := test.d.Notation()
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	d, _ := NewDefinitionFromValues("Custom", 1, 3, 5)
+	if err := r.Register(d); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	for _, name := range []string{"Custom", "custom", "CUSTOM"} {
+		found, ok := r.Lookup(name)
+		if !ok {
+			t.Errorf("%s: 見つからない", name)
+			continue
+		}
+
+		if found != d {
+			t.Errorf("%s: 異なる定義が返された", name)
+		}
+	}
+
+	if _, ok := r.Lookup("unknown"); ok {
+		t.Error("登録していない定義が見つかった")
+	}
+
+	if !reflect.DeepEqual(r.Names(), []string{"Custom"}) {
+		t.Errorf("wrong names: %v", r.Names())
+	}
+}
+
+func TestRegistry_Register_Error(t *testing.T) {
+	r := NewRegistry()
+
+	d1, _ := NewDefinitionFromValues("dup", 1, 2)
+	d2, _ := NewDefinitionFromValues("DUP", 3, 4)
+	anonymous, _ := NewDefinitionFromValues("", 1, 2)
+
+	if err := r.Register(d1); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if err := r.Register(d2); err == nil {
+		t.Error("同じ名前の定義を登録できてしまった")
+	}
+
+	if err := r.Register(anonymous); err == nil {
+		t.Error("無名の定義を登録できてしまった")
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	testcases := []struct {
+		name     string
+		expected []int
+	}{
+		{"avg", []int{2, 3, 3, 4, 4, 5}},
+		{"fudge", []int{-1, -1, 0, 0, 1, 1}},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			d, ok := LookupDefinition(test.name)
+			if !ok {
+				t.Fatal("見つからない")
+			}
+
+			actual := []int{}
+			for _, f := range d.Faces {
+				actual = append(actual, f.Value)
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
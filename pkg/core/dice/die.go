@@ -5,6 +5,8 @@ package dice
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -28,25 +30,89 @@ func (d Die) SExp() string {
 
 // FormatDice はダイス列を文字列として整形して返す。
 // 結果の文字列は "値/面数, 値/面数, ..." という形式。
-func FormatDice(dice []Die) string {
-	dieStrs := []string{}
-	for _, d := range dice {
-		dieStr := fmt.Sprintf("%d/%d", d.Value, d.Sides)
-		dieStrs = append(dieStrs, dieStr)
-	}
-
-	return strings.Join(dieStrs, ", ")
+//
+// definitionsを指定すると、i番目のダイスをi番目の定義で振られたダイスとして扱い、
+// "値/面数(面)" という形式で出目に対応する面を付加する。
+// 定義がnilのダイスや、対応する定義がないダイスには面を付加しない。
+func FormatDice(dice []Die, definitions ...*Definition) string {
+	return strings.Join(formatDieStrs(dice, definitions), ", ")
 }
 
 // FormatDiceWithoutSpaces はダイス列を文字列として整形して返す。
 // 結果の文字列は "値/面数,値/面数,..." という形式。
 // 空白を出力しないので、テストケースなどで使うとよい。
-func FormatDiceWithoutSpaces(dice []Die) string {
+//
+// definitionsの扱いは FormatDice と同じ。
+func FormatDiceWithoutSpaces(dice []Die, definitions ...*Definition) string {
+	return strings.Join(formatDieStrs(dice, definitions), ",")
+}
+
+// formatDieStrs は、ダイス列の各ダイスを整形した文字列のスライスを返す。
+func formatDieStrs(dice []Die, definitions []*Definition) []string {
 	dieStrs := []string{}
-	for _, d := range dice {
+	for i, d := range dice {
 		dieStr := fmt.Sprintf("%d/%d", d.Value, d.Sides)
+
+		if i < len(definitions) && definitions[i] != nil {
+			dieStr += "(" + FacedDie{Die: d, Definition: definitions[i]}.faceString() + ")"
+		}
+
 		dieStrs = append(dieStrs, dieStr)
 	}
 
-	return strings.Join(dieStrs, ",")
+	return dieStrs
+}
+
+// ダイス表記を表す正規表現
+var dieRe = regexp.MustCompile(`\A\s*(\d+)/(\d+)(?:\([^()]*\))?\s*\z`)
+
+// ParseDice は "値/面数,値/面数,..." という形式のダイス表記を解析し、ダイスのスライスを返す。
+// 区切りのカンマの前後に空白があってもよい。
+// FormatDice および FormatDiceWithoutSpaces の結果を解析することができる。
+// 出目に対応する面の表記 "(面)" は無視する。
+func ParseDice(source string) ([]Die, error) {
+	rolledDice := []Die{}
+
+	if source == "" {
+		return rolledDice, nil
+	}
+
+	diceStrs := splitDiceStrs(source)
+	for i, diceStr := range diceStrs {
+		matches := dieRe.FindStringSubmatch(diceStr)
+		if matches == nil {
+			return nil, fmt.Errorf("ParseDice: #%d: %q: ダイス構文エラー", i+1, diceStr)
+		}
+
+		value, _ := strconv.Atoi(matches[1])
+		sides, _ := strconv.Atoi(matches[2])
+		rolledDice = append(rolledDice, Die{Value: value, Sides: sides})
+	}
+
+	return rolledDice, nil
+}
+
+// splitDiceStrs は、ダイス表記を括弧の外にあるカンマで区切る。
+func splitDiceStrs(source string) []string {
+	diceStrs := []string{}
+	depth := 0
+	start := 0
+
+	for i, c := range source {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				diceStrs = append(diceStrs, source[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(diceStrs, source[start:])
 }
@@ -2,6 +2,7 @@ package dice
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -96,3 +97,111 @@ func TestFormatDiceWithoutSpace(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDice(t *testing.T) {
+	testcases := []struct {
+		source   string
+		expected []Die
+		err      bool
+	}{
+		{
+			source:   "",
+			expected: []Die{},
+		},
+		{
+			source:   "2/6",
+			expected: []Die{{2, 6}},
+		},
+		{
+			source:   "2/4,3/6,5/10,10/20",
+			expected: []Die{{2, 4}, {3, 6}, {5, 10}, {10, 20}},
+		},
+		{
+			source:   "2/4, 3/6, 5/10, 10/20",
+			expected: []Die{{2, 4}, {3, 6}, {5, 10}, {10, 20}},
+		},
+		{
+			source:   "1/3(-1),3/3(1), 2/6",
+			expected: []Die{{1, 3}, {3, 3}, {2, 6}},
+		},
+		{
+			source:   "3/4(1★)",
+			expected: []Die{{3, 4}},
+		},
+		{
+			source: "2",
+			err:    true,
+		},
+		{
+			source: "2/6(3",
+			err:    true,
+		},
+		{
+			source: "2/6,",
+			err:    true,
+		},
+		{
+			source: "?/6",
+			err:    true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%q", test.source), func(t *testing.T) {
+			actual, err := ParseDice(test.source)
+			if err != nil {
+				if !test.err {
+					t.Fatalf("got err: %s", err)
+				}
+
+				return
+			}
+
+			if test.err {
+				t.Fatal("should err")
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseDice_FormatDiceWithoutSpaces(t *testing.T) {
+	ds := []Die{{1, 6}, {2, 6}, {3, 6}, {4, 6}, {5, 6}, {6, 6}}
+
+	actual, err := ParseDice(FormatDiceWithoutSpaces(ds))
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, ds) {
+		t.Errorf("got %v, want %v", actual, ds)
+	}
+}
+
+func TestParseDice_FormatDiceWithDefinitions(t *testing.T) {
+	fudge, ok := LookupDefinition("fudge")
+	if !ok {
+		t.Fatal("fudge が登録されていません")
+	}
+
+	ds := []Die{{1, 6}, {3, 6}, {3, 6}}
+	definitions := []*Definition{fudge, fudge, nil}
+
+	formatted := FormatDiceWithoutSpaces(ds, definitions...)
+	expectedStr := "1/6(-1),3/6(0),3/6"
+	if formatted != expectedStr {
+		t.Fatalf("got %q, want %q", formatted, expectedStr)
+	}
+
+	actual, err := ParseDice(formatted)
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, ds) {
+		t.Errorf("got %v, want %v", actual, ds)
+	}
+}
@@ -0,0 +1,104 @@
+package feeder
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// 暗号論的擬似乱数生成器を使用して、ランダムにダイスを取り出すダイス供給機の構造体。
+// 乱数源には crypto/rand を使用するため、出目を予測することは困難である。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+type Crypto struct {
+	// 乱数源へのアクセスを保護する
+	mu sync.Mutex
+	// 乱数源
+	source io.Reader
+}
+
+// CryptoがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*Crypto)(nil)
+
+// NewCrypto は、crypto/rand を乱数源とするダイス供給機を返す。
+func NewCrypto() *Crypto {
+	return NewCryptoWithReader(rand.Reader)
+}
+
+// NewCryptoWithReader は、指定した乱数源を使用するダイス供給機を返す。
+// 乱数源の出力が偏った場合の動作を確認するテストなどで使う。
+//
+// source: 乱数源
+func NewCryptoWithReader(source io.Reader) *Crypto {
+	return &Crypto{
+		source: source,
+	}
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// Cryptoダイス供給機ではfalseを返す。
+func (f *Crypto) CanSpecifyDie() bool {
+	return false
+}
+
+// Next はランダムな値のダイスを1つ供給する。
+//
+// sides: ダイスの面の数
+func (f *Crypto) Next(sides int) (dice.Die, error) {
+	if sides < 1 {
+		return dice.Die{}, locale.NewError(locale.MSG_ERR_TOO_FEW_SIDES, sides)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	v, err := uniformUint64(f.uint64, uint64(sides))
+	if err != nil {
+		return dice.Die{}, err
+	}
+
+	d := dice.Die{
+		Sides: sides,
+		Value: 1 + int(v),
+	}
+	return d, nil
+}
+
+// uint64 は乱数源から64ビットの符号なし整数を読み込む。
+func (f *Crypto) uint64() (uint64, error) {
+	var buf [8]byte
+
+	if _, err := io.ReadFull(f.source, buf[:]); err != nil {
+		return 0, locale.NewError(locale.MSG_ERR_RANDOM_SOURCE, err)
+	}
+
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// uniformUint64 は、0以上n未満の一様分布に従う整数を返す。
+//
+// 剰余による偏りが生じないように、棄却サンプリングを行う。
+// 2^64 を n で割った余りよりも小さい値を棄却することで、
+// 残った値を n で割った余りが一様に分布するようにする。
+//
+// next: 64ビットの一様乱数を返す関数,
+// n: 返す値の上限（n > 0）。
+func uniformUint64(next func() (uint64, error), n uint64) (uint64, error) {
+	// 2^64 mod n
+	threshold := -n % n
+
+	for {
+		v, err := next()
+		if err != nil {
+			return 0, err
+		}
+
+		if v >= threshold {
+			return v % n, nil
+		}
+	}
+}
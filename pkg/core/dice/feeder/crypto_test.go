@@ -0,0 +1,110 @@
+package feeder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"testing"
+)
+
+// ダイスをランダムに供給：暗号論的擬似乱数生成器を使う場合の例。
+func Example_crypto() {
+	// ダイスの値を予測困難なランダムにする
+	dieFeeder := NewCrypto()
+	// 6面ダイスを1個振る
+	d, _ := dieFeeder.Next(6)
+
+	fmt.Println(d.String())
+}
+
+// uint64sToReader は、64ビットの符号なし整数の列を乱数源として返す。
+func uint64sToReader(values ...uint64) *bytes.Reader {
+	buf := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint64(buf[8*i:], v)
+	}
+
+	return bytes.NewReader(buf)
+}
+
+func TestCrypto_CanSpecifyDie(t *testing.T) {
+	f := NewCrypto()
+
+	if f.CanSpecifyDie() {
+		t.Fatalf("Cryptoはダイスを指定できてはならない")
+	}
+}
+
+func TestCrypto_Next(t *testing.T) {
+	f := NewCrypto()
+
+	for _, sides := range []int{1, 2, 4, 6, 10, 20, 100} {
+		t.Run(fmt.Sprintf("%d", sides), func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				d, err := f.Next(sides)
+				if err != nil {
+					t.Fatalf("got err: %s", err)
+				}
+
+				if d.Sides != sides {
+					t.Fatalf("wrong sides: got %d, want %d", d.Sides, sides)
+				}
+
+				if d.Value < 1 || d.Value > sides {
+					t.Fatalf("value out of range: %s", d)
+				}
+			}
+		})
+	}
+}
+
+func TestCrypto_Next_RejectsBiasedValues(t *testing.T) {
+	// 2^64 mod 6 = 4 のため、0〜3は棄却されなければならない
+	f := NewCryptoWithReader(uint64sToReader(0, 3, 4, 11))
+
+	expected := []dice.Die{{5, 6}, {6, 6}}
+	for _, e := range expected {
+		actual, err := f.Next(6)
+		if err != nil {
+			t.Fatalf("got err: %s", err)
+		}
+
+		if !reflect.DeepEqual(actual, e) {
+			t.Errorf("wrong die: got %s, want %s", actual, e)
+		}
+	}
+}
+
+func TestCrypto_Next_Error(t *testing.T) {
+	testcases := []struct {
+		name  string
+		f     *Crypto
+		sides int
+	}{
+		{
+			name:  "面数が0",
+			f:     NewCrypto(),
+			sides: 0,
+		},
+		{
+			name:  "乱数源が空",
+			f:     NewCryptoWithReader(bytes.NewReader(nil)),
+			sides: 6,
+		},
+		{
+			name:  "乱数源の値がすべて棄却される",
+			f:     NewCryptoWithReader(uint64sToReader(0, 1, 2, 3)),
+			sides: 6,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.f.Next(test.sides); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
@@ -3,11 +3,28 @@
 このパッケージに含まれる構造体を利用することで、ダイスの値をランダムにするか、指定したものにするかを切り替えることができる。
 
 ダイスの値をランダムにする場合は、MT19937を使用する。
+Ruby版BCDiceと同じシードから同じダイス列を再現する場合は、Rubyを使用する。
+出目の予測が困難なランダムにする場合は、Cryptoを使用する。
+ダイスが公平に振られたことをプレイヤーが後から検証できるようにする場合は、ProvablyFairを使用する。
 ダイスの値を指定したものにする場合は、Queueを使用する。
+最初のいくつかのダイスの値だけを指定し、残りをランダムにする場合は、Hybridを使用する。
+実際に振ったダイスの出目を入力してもらう場合は、Manualを使用する。
+
+Recorderを使用すると、他のダイス供給機が供給したダイスを記録することができる。
+記録したダイスはReplayで再生できる。
+
+MT19937、Ruby、Queue、ReplayおよびProvablyFairは、MarshalBinaryで状態を保存し、
+UnmarshalBinaryで復元することができる。復元したダイス供給機は、保存時点の続きからダイスを供給する。
+
+NewByNameを使用すると、名前を指定してダイス供給機を構築することができる。
 */
 package feeder
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
 )
 
@@ -21,3 +38,83 @@ type DieFeeder interface {
 	// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
 	CanSpecifyDie() bool
 }
+
+// NoncedDieFeeder は、各ダイスの導出に使ったノンスを返せるダイス供給機のインターフェース。
+// ProvablyFair が実装している。
+// 他のダイス供給機を包む Recorder および Hybrid も実装しており、内側のダイス供給機のノンスを返す。
+type NoncedDieFeeder interface {
+	DieFeeder
+
+	// NextWithNonce は、ダイスを1つ供給し、その導出に使ったノンスとともに返す。
+	// ダイスがノンスから導出されたものでない場合（内側のダイス供給機がノンスを返さない場合や、
+	// 指定されたダイスの場合）、okは偽となる。
+	//
+	// sides: ダイスの面数
+	NextWithNonce(sides int) (d dice.Die, nonce uint64, ok bool, err error)
+}
+
+// NextWithNonce は、fからダイスを1つ取り出し、その導出に使ったノンスとともに返す。
+// fが NoncedDieFeeder を実装していない場合、okは偽となる。
+//
+// f: ダイス供給機,
+// sides: ダイスの面数。
+func NextWithNonce(f DieFeeder, sides int) (d dice.Die, nonce uint64, ok bool, err error) {
+	if noncedFeeder, nonced := f.(NoncedDieFeeder); nonced {
+		return noncedFeeder.NextWithNonce(sides)
+	}
+
+	d, err = f.Next(sides)
+	return d, 0, false, err
+}
+
+// ダイス供給機の名前とコンストラクタとの対応
+var nameToConstructor = map[string]func() DieFeeder{
+	"mt": func() DieFeeder {
+		return NewMT19937WithSeedFromTime()
+	},
+	"crypto": func() DieFeeder {
+		return NewCrypto()
+	},
+	"queue": func() DieFeeder {
+		return NewEmptyQueue()
+	},
+	"ruby": func() DieFeeder {
+		return NewRubyWithSeedFromTime()
+	},
+	"hybrid": func() DieFeeder {
+		return NewHybrid(NewMT19937WithSeedFromTime())
+	},
+}
+
+// NewByName は、指定された名前の種類のダイス供給機を構築して返す。
+// 名前の大文字と小文字は区別しない。
+// 指定された名前の種類が見つからなかった場合はエラーを返す。
+//
+// 利用できる名前は以下のとおり。
+//
+// * "mt"    : MT19937（現在時刻をシードとする）
+// * "ruby"  : Ruby（現在時刻をシードとする）
+// * "crypto": Crypto
+// * "queue" : 空のQueue
+// * "hybrid": 空のキューを持ち、MT19937（現在時刻をシードとする）を代替とするHybrid
+func NewByName(name string) (DieFeeder, error) {
+	constructor, found := nameToConstructor[strings.ToLower(name)]
+	if !found {
+		return nil, fmt.Errorf("unknown die feeder: %s", name)
+	}
+
+	return constructor(), nil
+}
+
+// AvailableNames は、NewByNameで指定できるダイス供給機の名前のスライスを返す。
+// 名前は昇順に並べられる。
+func AvailableNames() []string {
+	names := make([]string, 0, len(nameToConstructor))
+	for name := range nameToConstructor {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,152 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestNewByName(t *testing.T) {
+	testcases := []struct {
+		name     string
+		expected DieFeeder
+	}{
+		{"mt", &MT19937{}},
+		{"MT", &MT19937{}},
+		{"crypto", &Crypto{}},
+		{"queue", &Queue{}},
+		{"hybrid", &Hybrid{}},
+		{"ruby", &Ruby{}},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := NewByName(test.name)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+			}
+
+			actualType := reflect.TypeOf(f)
+			expectedType := reflect.TypeOf(test.expected)
+			if actualType != expectedType {
+				t.Errorf("wrong type: got %s, want %s", actualType, expectedType)
+			}
+		})
+	}
+}
+
+func TestNewByName_Unknown(t *testing.T) {
+	if _, err := NewByName("unknown"); err == nil {
+		t.Fatal("未知のダイス供給機を構築できてしまった")
+	}
+}
+
+func TestAvailableNames(t *testing.T) {
+	expected := []string{"crypto", "hybrid", "mt", "queue", "ruby"}
+	actual := AvailableNames()
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+// 複数のゴルーチンから同時にダイスを取り出す場合のテスト。
+// go test -race で実行すると、データ競合を検出できる。
+func TestDieFeeder_Concurrent(t *testing.T) {
+	const (
+		numOfGoroutines = 16
+		numOfDice       = 100
+	)
+
+	dieFeeders := map[string]func() DieFeeder{
+		"MT19937": func() DieFeeder {
+			return NewMT19937(1)
+		},
+		"Ruby": func() DieFeeder {
+			return NewRuby(1)
+		},
+		"Crypto": func() DieFeeder {
+			return NewCrypto()
+		},
+		"ProvablyFair": func() DieFeeder {
+			return NewProvablyFair([]byte("server-seed"), "player1")
+		},
+		"Queue": func() DieFeeder {
+			q := NewEmptyQueue()
+			for i := 0; i < numOfGoroutines*numOfDice; i++ {
+				q.Push(dice.Die{i%6 + 1, 6})
+			}
+
+			return q
+		},
+		"Hybrid": func() DieFeeder {
+			h := NewHybrid(NewMT19937(1))
+			for i := 0; i < numOfGoroutines*numOfDice/2; i++ {
+				h.Push(WildcardDie(6))
+			}
+
+			return h
+		},
+		"Manual": func() DieFeeder {
+			return NewManual(func(sides int) ([]int, error) {
+				return []int{1, sides}, nil
+			})
+		},
+		"Recorder": func() DieFeeder {
+			return NewRecorder(NewMT19937(1))
+		},
+		"Replay": func() DieFeeder {
+			ds := make([]dice.Die, 0, numOfGoroutines*numOfDice)
+			for i := 0; i < numOfGoroutines*numOfDice; i++ {
+				ds = append(ds, dice.Die{i%6 + 1, 6})
+			}
+
+			return NewReplay(ds)
+		},
+	}
+
+	for name, newDieFeeder := range dieFeeders {
+		t.Run(name, func(t *testing.T) {
+			f := newDieFeeder()
+
+			var wg sync.WaitGroup
+			errs := make(chan error, numOfGoroutines*numOfDice)
+
+			for i := 0; i < numOfGoroutines; i++ {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					for j := 0; j < numOfDice; j++ {
+						d, err := f.Next(6)
+						if err != nil {
+							errs <- err
+							continue
+						}
+
+						if d.Value < 1 || d.Value > 6 {
+							errs <- fmt.Errorf("value out of range: %s", d)
+						}
+					}
+				}()
+			}
+
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Error(err)
+			}
+
+			if r, ok := f.(*Recorder); ok {
+				if n := len(r.RecordedDice()); n != numOfGoroutines*numOfDice {
+					t.Errorf("wrong number of recorded dice: got %d, want %d",
+						n, numOfGoroutines*numOfDice)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package feeder
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// 指定したダイスを優先して取り出し、指定したダイスがなくなったら
+// ランダムにダイスを取り出す、ハイブリッド型ダイス供給機の構造体。
+//
+// キューには、値を指定したダイスの他に、面数だけを指定したワイルドカード
+// （"?/6" など）を入れることができる。ワイルドカードを取り出すときは、
+// 代替のダイス供給機からダイスを取り出す。
+//
+// キューの先頭のダイスの面数が要求された面数と一致しない場合は、エラーを返す。
+// その場合、ダイスはキューから取り出されない。
+//
+// 代替のダイス供給機が状態の保存に対応している場合、
+// MarshalBinary でキューと代替のダイス供給機の状態をまとめて保存し、UnmarshalBinary で復元できる。
+//
+// 代替のダイス供給機が複数のゴルーチンから同時に使用しても安全な場合、
+// Hybridも複数のゴルーチンから同時に使用しても安全である。
+// ただし、その場合にどのゴルーチンにどのダイスが供給されるかは不定となる。
+type Hybrid struct {
+	// キューへのアクセスを保護する
+	mu    sync.Mutex
+	queue []dice.Die
+	// キューが空の場合やワイルドカードの場合にダイスを供給するダイス供給機
+	fallback DieFeeder
+}
+
+// HybridがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*Hybrid)(nil)
+
+// HybridがNoncedDieFeederインターフェースを実装しているかの確認
+var _ NoncedDieFeeder = (*Hybrid)(nil)
+
+// NewHybrid は、空のキューを持つハイブリッド型ダイス供給機を返す。
+//
+// fallback: キューが空の場合やワイルドカードの場合にダイスを供給するダイス供給機
+func NewHybrid(fallback DieFeeder) *Hybrid {
+	return &Hybrid{
+		queue:    []dice.Die{},
+		fallback: fallback,
+	}
+}
+
+// WildcardDie は、キューに入れると代替のダイス供給機から取り出されるダイスを返す。
+// ワイルドカードの値は0とする。
+//
+// sides: ダイスの面の数
+func WildcardDie(sides int) dice.Die {
+	return dice.Die{Value: 0, Sides: sides}
+}
+
+// IsWildcardDie は、dがワイルドカードならばtrueを、そうでなければfalseを返す。
+func IsWildcardDie(d dice.Die) bool {
+	return d.Value == 0
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// ハイブリッド型ダイス供給機ではtrueを返す。
+func (f *Hybrid) CanSpecifyDie() bool {
+	return true
+}
+
+// Fallback は、キューが空の場合やワイルドカードの場合にダイスを供給するダイス供給機を返す。
+func (f *Hybrid) Fallback() DieFeeder {
+	return f.fallback
+}
+
+// Dice は、現在のキューの内容をコピーして返す。
+func (f *Hybrid) Dice() []dice.Die {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	copiedDice := make([]dice.Die, len(f.queue))
+	copy(copiedDice, f.queue)
+
+	return copiedDice
+}
+
+// Next はダイスを1つ供給する。
+//
+// キューが空の場合は、代替のダイス供給機からダイスを取り出す。
+// そうでない場合は、キューからダイスを1つ取り出す。
+// 取り出したダイスがワイルドカードならば、代替のダイス供給機からダイスを取り出す。
+//
+// キューの先頭のダイスの面数がsidesと一致しない場合はエラーを返す。
+//
+// sides: ダイスの面の数
+func (f *Hybrid) Next(sides int) (dice.Die, error) {
+	d, _, _, err := f.NextWithNonce(sides)
+	return d, err
+}
+
+// NextWithNonce は、Next と同様にダイスを1つ供給し、その導出に使ったノンスとともに返す。
+//
+// 代替のダイス供給機から取り出したダイスでは、代替のダイス供給機が返したノンスを返す。
+// キューで値を指定したダイスはノンスから導出されたものではないため、okは偽となる。
+//
+// sides: ダイスの面の数
+func (f *Hybrid) NextWithNonce(sides int) (dice.Die, uint64, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.queue) == 0 {
+		return NextWithNonce(f.fallback, sides)
+	}
+
+	d := f.queue[0]
+	if d.Sides != sides {
+		return dice.Die{}, 0, false, locale.NewError(
+			locale.MSG_ERR_SIDES_MISMATCH, sides, formatHybridDie(d))
+	}
+
+	var nonce uint64
+	ok := false
+
+	if IsWildcardDie(d) {
+		fallbackDie, fallbackNonce, fallbackOk, err := NextWithNonce(f.fallback, sides)
+		if err != nil {
+			return dice.Die{}, 0, false, err
+		}
+
+		d = fallbackDie
+		nonce = fallbackNonce
+		ok = fallbackOk
+	}
+
+	// キューからダイスを取り出す
+	f.queue = f.queue[1:]
+
+	return d, nonce, ok, nil
+}
+
+// Push はダイスをキューに追加する。
+func (f *Hybrid) Push(d dice.Die) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = append(f.queue, d)
+}
+
+// Append は複数のダイスをキューの末尾に追加する。
+func (f *Hybrid) Append(dice []dice.Die) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = append(f.queue, dice...)
+}
+
+// Clear はキューを空にする。
+func (f *Hybrid) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = []dice.Die{}
+}
+
+// Set は指定されたダイスをキューに配置する。
+func (f *Hybrid) Set(d []dice.Die) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = make([]dice.Die, len(d))
+	copy(f.queue, d)
+}
+
+// Remaining は、キューに残っているダイスの数を返す。
+func (f *Hybrid) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.queue)
+}
+
+// IsEmpty は、ダイスのキューが空ならばtrueを、空でなければfalseを返す。
+func (f *Hybrid) IsEmpty() bool {
+	return f.Remaining() == 0
+}
+
+// MarshalBinary は、キューの内容と代替のダイス供給機の状態を直列化して返す。
+// 代替のダイス供給機が状態の保存に対応していない場合はエラーを返す。
+func (f *Hybrid) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fallback, err := marshalFeeder(f.fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newSnapshotWriter(snapshotKindHybrid)
+	w.putDice(f.queue)
+	w.putBytes(fallback)
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化された状態を復元する。
+//
+// 代替のダイス供給機には、保存時と同じ種類のものを設定しておくこと。
+// 状態の復元に失敗した場合、キューは変更しない。
+func (f *Hybrid) UnmarshalBinary(data []byte) error {
+	r := newSnapshotReader(snapshotKindHybrid, data)
+	queue := r.readDice()
+	fallback := r.readBytes()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := unmarshalFeeder(f.fallback, fallback); err != nil {
+		return err
+	}
+
+	f.queue = queue
+
+	return nil
+}
+
+// ワイルドカードを含むダイス表記を表す正規表現
+var hybridDieRe = regexp.MustCompile(`\A\s*(\d+|\?)/(\d+)\s*\z`)
+
+// ParseHybridDice は "値/面数,?/面数,..." という形式のダイス表記を解析し、ダイスのスライスを返す。
+// 値の代わりに "?" を書くと、その位置のダイスはワイルドカードになる。
+// 区切りのカンマの前後に空白があってもよい。
+func ParseHybridDice(source string) ([]dice.Die, error) {
+	ds := []dice.Die{}
+
+	if source == "" {
+		return ds, nil
+	}
+
+	dieStrs := strings.Split(source, ",")
+	for i, dieStr := range dieStrs {
+		matches := hybridDieRe.FindStringSubmatch(dieStr)
+		if matches == nil {
+			return nil, fmt.Errorf("ParseHybridDice: #%d: %q: ダイス構文エラー", i+1, dieStr)
+		}
+
+		sides, _ := strconv.Atoi(matches[2])
+		if matches[1] == "?" {
+			ds = append(ds, WildcardDie(sides))
+			continue
+		}
+
+		value, _ := strconv.Atoi(matches[1])
+		if value < 1 || value > sides {
+			return nil, fmt.Errorf("ParseHybridDice: #%d: %q: ダイスの値が範囲外です", i+1, dieStr)
+		}
+
+		ds = append(ds, dice.Die{Value: value, Sides: sides})
+	}
+
+	return ds, nil
+}
+
+// FormatHybridDice は、ワイルドカードを含むダイス列を "値/面数, ?/面数, ..." という形式で返す。
+// 結果は ParseHybridDice で解析できる。
+func FormatHybridDice(ds []dice.Die) string {
+	dieStrs := make([]string, 0, len(ds))
+	for _, d := range ds {
+		dieStrs = append(dieStrs, formatHybridDie(d))
+	}
+
+	return strings.Join(dieStrs, ", ")
+}
+
+// formatHybridDie は、ワイルドカードかもしれないダイスを "値/面数" または "?/面数" という形式で返す。
+func formatHybridDie(d dice.Die) string {
+	if IsWildcardDie(d) {
+		return fmt.Sprintf("?/%d", d.Sides)
+	}
+
+	return fmt.Sprintf("%d/%d", d.Value, d.Sides)
+}
@@ -0,0 +1,205 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"testing"
+)
+
+// 最初のダイスだけを指定し、残りをランダムにする場合の例。
+func Example_hybrid() {
+	dieFeeder := NewHybrid(NewMT19937(1))
+
+	// 1個目は6、2個目はランダム
+	ds, _ := ParseHybridDice("6/6, ?/6")
+	dieFeeder.Append(ds)
+
+	// 6面ダイスを3個振る：3個目はキューが空なのでランダム
+	for i := 0; i < 3; i++ {
+		d, _ := dieFeeder.Next(6)
+		fmt.Println(d.Sides, 1 <= d.Value && d.Value <= 6)
+	}
+
+	fmt.Println(dieFeeder.IsEmpty())
+	// Output:
+	// 6 true
+	// 6 true
+	// 6 true
+	// true
+}
+
+func TestHybrid_CanSpecifyDie(t *testing.T) {
+	f := NewHybrid(NewCrypto())
+
+	if !f.CanSpecifyDie() {
+		t.Fatalf("Hybridはダイスを指定できてなければならない")
+	}
+}
+
+func TestHybrid_Next(t *testing.T) {
+	fallback := NewQueue([]dice.Die{{2, 6}, {7, 10}, {19, 20}})
+	f := NewHybrid(fallback)
+	f.Append([]dice.Die{{1, 6}, WildcardDie(6), {3, 10}, WildcardDie(10)})
+
+	sides := []int{6, 6, 10, 10, 20}
+	expected := []dice.Die{{1, 6}, {2, 6}, {3, 10}, {7, 10}, {19, 20}}
+
+	actual := nextDice(t, f, sides)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("wrong dice: got %v, want %v", actual, expected)
+	}
+
+	if !f.IsEmpty() {
+		t.Errorf("キューが空になっていない: %v", f.Dice())
+	}
+
+	if !fallback.IsEmpty() {
+		t.Errorf("代替のダイス供給機が使われていない: %v", fallback.Dice())
+	}
+}
+
+func TestHybrid_NextWithNonce(t *testing.T) {
+	f := NewHybrid(NewProvablyFair([]byte("server-seed"), "player1"))
+	f.Append([]dice.Die{{1, 6}, WildcardDie(6)})
+
+	testcases := []struct {
+		name          string
+		expectedNonce uint64
+		expectedOk    bool
+	}{
+		// 値を指定したダイスはノンスを持たない
+		{"指定したダイス", 0, false},
+		{"ワイルドカード", 0, true},
+		{"キューが空", 1, true},
+	}
+
+	for _, test := range testcases {
+		_, nonce, ok, err := f.NextWithNonce(6)
+		if err != nil {
+			t.Fatalf("%s: got err: %s", test.name, err)
+		}
+
+		if ok != test.expectedOk {
+			t.Errorf("%s: ok: got %t, want %t", test.name, ok, test.expectedOk)
+		}
+
+		if nonce != test.expectedNonce {
+			t.Errorf("%s: wrong nonce: got %d, want %d", test.name, nonce, test.expectedNonce)
+		}
+	}
+}
+
+func TestHybrid_Next_SidesMismatch(t *testing.T) {
+	testcases := []dice.Die{{1, 6}, WildcardDie(6)}
+
+	for _, d := range testcases {
+		t.Run(formatHybridDie(d), func(t *testing.T) {
+			fallback := NewQueue([]dice.Die{{5, 10}})
+			f := NewHybrid(fallback)
+			f.Push(d)
+
+			if _, err := f.Next(10); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+
+			// ダイスは取り出されない
+			if f.Remaining() != 1 {
+				t.Errorf("wrong remaining: got %d, want %d", f.Remaining(), 1)
+			}
+
+			if fallback.Remaining() != 1 {
+				t.Errorf("代替のダイス供給機からダイスが取り出された")
+			}
+		})
+	}
+}
+
+func TestHybrid_Next_FallbackError(t *testing.T) {
+	f := NewHybrid(NewEmptyQueue())
+	f.Push(WildcardDie(6))
+
+	if _, err := f.Next(6); err == nil {
+		t.Fatal("エラーが発生しませんでした")
+	}
+
+	if f.Remaining() != 1 {
+		t.Errorf("wrong remaining: got %d, want %d", f.Remaining(), 1)
+	}
+}
+
+func TestParseHybridDice(t *testing.T) {
+	testcases := []struct {
+		source   string
+		expected []dice.Die
+	}{
+		{
+			source:   "",
+			expected: []dice.Die{},
+		},
+		{
+			source:   "1/6",
+			expected: []dice.Die{{1, 6}},
+		},
+		{
+			source:   "?/6",
+			expected: []dice.Die{WildcardDie(6)},
+		},
+		{
+			source:   "1/6, ?/6,3/10 , ?/100",
+			expected: []dice.Die{{1, 6}, WildcardDie(6), {3, 10}, WildcardDie(100)},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%q", test.source), func(t *testing.T) {
+			actual, err := ParseHybridDice(test.source)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("wrong dice: got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseHybridDice_Error(t *testing.T) {
+	testcases := []string{
+		"1",
+		"?",
+		"?/",
+		"1/6,",
+		"*/6",
+		"0/6",
+		"7/6",
+	}
+
+	for _, source := range testcases {
+		t.Run(fmt.Sprintf("%q", source), func(t *testing.T) {
+			if _, err := ParseHybridDice(source); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
+
+func TestFormatHybridDice(t *testing.T) {
+	ds := []dice.Die{{1, 6}, WildcardDie(6), {3, 10}}
+
+	expected := "1/6, ?/6, 3/10"
+	actual := FormatHybridDice(ds)
+	if actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+
+	parsed, err := ParseHybridDice(actual)
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if !reflect.DeepEqual(parsed, ds) {
+		t.Errorf("wrong dice: got %v, want %v", parsed, ds)
+	}
+}
@@ -0,0 +1,159 @@
+package feeder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// ManualInputFunc は、手動入力型ダイス供給機がダイスの出目を尋ねるときに呼び出す関数の型。
+//
+// sides面ダイスの出目を1個以上返す。
+// 複数の出目を返した場合、2個目以降の出目は、続けて要求されたsides面ダイスとして供給される。
+//
+// sides: ダイスの面の数
+type ManualInputFunc func(sides int) ([]int, error)
+
+// 実際に振ったダイスの出目を入力してもらう、手動入力型ダイス供給機の構造体。
+//
+// ダイスが要求されるたびに、入力用の関数を呼び出して出目を尋ねる。
+// 入力用の関数が一度に複数の出目を返した場合は、それらを保留しておき、
+// 続けて要求されたダイスとして供給する。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+// ただし、入力用の関数の呼び出し中は、他のゴルーチンからのダイスの要求は待たされる。
+type Manual struct {
+	// 保留中の出目へのアクセスを保護する
+	mu sync.Mutex
+	// 出目を尋ねる関数
+	input ManualInputFunc
+	// 入力済みで、まだ供給していないダイス
+	pending []dice.Die
+}
+
+// ManualがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*Manual)(nil)
+
+// NewManual は手動入力型ダイス供給機を返す。
+//
+// input: ダイスの出目を尋ねる関数
+func NewManual(input ManualInputFunc) *Manual {
+	return &Manual{
+		input:   input,
+		pending: []dice.Die{},
+	}
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// 手動入力型ダイス供給機ではtrueを返す。
+func (f *Manual) CanSpecifyDie() bool {
+	return true
+}
+
+// Next はダイスを1つ供給する。
+//
+// 保留中の出目があれば、それを供給する。
+// 保留中の出目がなければ、入力用の関数を呼び出して出目を尋ねる。
+//
+// 保留中の出目の面数が要求された面数と一致しない場合は、保留中の出目を破棄してエラーを返す。
+// 入力された出目が範囲外だった場合もエラーを返す。
+//
+// sides: ダイスの面の数
+func (f *Manual) Next(sides int) (dice.Die, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.pending) > 0 {
+		d := f.pending[0]
+		if d.Sides != sides {
+			f.pending = []dice.Die{}
+			return dice.Die{}, locale.NewError(
+				locale.MSG_ERR_SIDES_MISMATCH, sides, fmt.Sprintf("%d/%d", d.Value, d.Sides))
+		}
+
+		f.pending = f.pending[1:]
+		return d, nil
+	}
+
+	values, err := f.input(sides)
+	if err != nil {
+		return dice.Die{}, err
+	}
+
+	if len(values) < 1 {
+		return dice.Die{}, locale.NewError(locale.MSG_ERR_NO_DIE_INPUT)
+	}
+
+	ds := make([]dice.Die, 0, len(values))
+	for _, v := range values {
+		if v < 1 || v > sides {
+			return dice.Die{}, locale.NewError(locale.MSG_ERR_DIE_OUT_OF_RANGE, v, sides)
+		}
+
+		ds = append(ds, dice.Die{Value: v, Sides: sides})
+	}
+
+	f.pending = ds[1:]
+
+	return ds[0], nil
+}
+
+// Pending は、入力済みで、まだ供給していないダイスをコピーして返す。
+func (f *Manual) Pending() []dice.Die {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	copiedDice := make([]dice.Die, len(f.pending))
+	copy(copiedDice, f.pending)
+
+	return copiedDice
+}
+
+// Discard は、入力済みで、まだ供給していないダイスを破棄する。
+// 破棄したダイスを返す。
+//
+// コマンドの実行後に呼び出すと、余分に入力された出目が次のコマンドで使われることを防げる。
+func (f *Manual) Discard() []dice.Die {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	discarded := f.pending
+	f.pending = []dice.Die{}
+
+	return discarded
+}
+
+// ParseManualInput は、"3 5 6" のように空白またはカンマで区切られた出目の入力を解析する。
+// 出目が1以上sides以下でない場合はエラーを返す。
+//
+// input: 入力された文字列,
+// sides: ダイスの面の数。
+func ParseManualInput(input string, sides int) ([]int, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '　'
+	})
+
+	if len(fields) < 1 {
+		return nil, locale.NewError(locale.MSG_ERR_NO_DIE_INPUT)
+	}
+
+	values := make([]int, 0, len(fields))
+	for _, field := range fields {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, locale.NewError(locale.MSG_ERR_DIE_NOT_INTEGER, field)
+		}
+
+		if v < 1 || v > sides {
+			return nil, locale.NewError(locale.MSG_ERR_DIE_OUT_OF_RANGE, v, sides)
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,212 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"testing"
+)
+
+// 実際に振ったダイスの出目を入力する場合の例。
+func Example_manual() {
+	// 本来は端末などから入力してもらう
+	inputs := []string{"3 5 6", "10"}
+	dieFeeder := NewManual(func(sides int) ([]int, error) {
+		input := inputs[0]
+		inputs = inputs[1:]
+
+		fmt.Printf("D%dの出目> %s\n", sides, input)
+
+		return ParseManualInput(input, sides)
+	})
+
+	// 3D6：一度に3個の出目を入力する
+	for i := 0; i < 3; i++ {
+		d, _ := dieFeeder.Next(6)
+		fmt.Println(d.String())
+	}
+
+	// 1D10
+	d, _ := dieFeeder.Next(10)
+	fmt.Println(d.String())
+	// Output:
+	// D6の出目> 3 5 6
+	// <Die 3/6>
+	// <Die 5/6>
+	// <Die 6/6>
+	// D10の出目> 10
+	// <Die 10/10>
+}
+
+// manualInputs は、呼び出されるたびに指定した出目を順に返す入力用の関数を返す。
+// 要求された面数を requestedSides に記録する。
+func manualInputs(requestedSides *[]int, inputs ...[]int) ManualInputFunc {
+	return func(sides int) ([]int, error) {
+		*requestedSides = append(*requestedSides, sides)
+
+		if len(inputs) < 1 {
+			return nil, fmt.Errorf("入力がありません")
+		}
+
+		values := inputs[0]
+		inputs = inputs[1:]
+
+		return values, nil
+	}
+}
+
+func TestManual_CanSpecifyDie(t *testing.T) {
+	f := NewManual(nil)
+
+	if !f.CanSpecifyDie() {
+		t.Fatalf("Manualはダイスを指定できてなければならない")
+	}
+}
+
+func TestManual_Next(t *testing.T) {
+	requestedSides := []int{}
+	f := NewManual(manualInputs(&requestedSides, []int{3, 5}, []int{6}, []int{7, 2}))
+
+	sides := []int{6, 6, 6, 10, 10}
+	expected := []dice.Die{{3, 6}, {5, 6}, {6, 6}, {7, 10}, {2, 10}}
+
+	actual := nextDice(t, f, sides)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("wrong dice: got %v, want %v", actual, expected)
+	}
+
+	expectedRequestedSides := []int{6, 6, 10}
+	if !reflect.DeepEqual(requestedSides, expectedRequestedSides) {
+		t.Errorf("wrong requested sides: got %v, want %v",
+			requestedSides, expectedRequestedSides)
+	}
+}
+
+func TestManual_Next_Error(t *testing.T) {
+	testcases := []struct {
+		name   string
+		inputs [][]int
+	}{
+		{
+			name:   "入力エラー",
+			inputs: [][]int{},
+		},
+		{
+			name:   "出目なし",
+			inputs: [][]int{{}},
+		},
+		{
+			name:   "出目が0",
+			inputs: [][]int{{0}},
+		},
+		{
+			name:   "出目が面数より大きい",
+			inputs: [][]int{{7}},
+		},
+		{
+			name:   "2個目の出目が範囲外",
+			inputs: [][]int{{1, 7}},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			requestedSides := []int{}
+			f := NewManual(manualInputs(&requestedSides, test.inputs...))
+
+			if _, err := f.Next(6); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+
+			if len(f.Pending()) > 0 {
+				t.Errorf("出目が保留されている: %v", f.Pending())
+			}
+		})
+	}
+}
+
+func TestManual_Next_PendingSidesMismatch(t *testing.T) {
+	requestedSides := []int{}
+	f := NewManual(manualInputs(&requestedSides, []int{3, 5}, []int{8}))
+
+	nextDice(t, f, []int{6})
+
+	if _, err := f.Next(10); err == nil {
+		t.Fatal("エラーが発生しませんでした")
+	}
+
+	if len(f.Pending()) > 0 {
+		t.Errorf("保留中の出目が破棄されていない: %v", f.Pending())
+	}
+
+	// 破棄された後は改めて入力を求める
+	actual := nextDice(t, f, []int{10})
+	expected := []dice.Die{{8, 10}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("wrong dice: got %v, want %v", actual, expected)
+	}
+}
+
+func TestManual_Discard(t *testing.T) {
+	requestedSides := []int{}
+	f := NewManual(manualInputs(&requestedSides, []int{1, 2, 3}))
+
+	nextDice(t, f, []int{6})
+
+	discarded := f.Discard()
+	expected := []dice.Die{{2, 6}, {3, 6}}
+	if !reflect.DeepEqual(discarded, expected) {
+		t.Errorf("wrong discarded dice: got %v, want %v", discarded, expected)
+	}
+
+	if len(f.Pending()) > 0 {
+		t.Errorf("保留中の出目が破棄されていない: %v", f.Pending())
+	}
+}
+
+func TestParseManualInput(t *testing.T) {
+	testcases := []struct {
+		input    string
+		sides    int
+		expected []int
+	}{
+		{"3", 6, []int{3}},
+		{"3 5 6", 6, []int{3, 5, 6}},
+		{"  1,2 , 3  ", 6, []int{1, 2, 3}},
+		{"10　1", 10, []int{10, 1}},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%q", test.input), func(t *testing.T) {
+			actual, err := ParseManualInput(test.input, test.sides)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseManualInput_Error(t *testing.T) {
+	testcases := []string{
+		"",
+		"   ",
+		"a",
+		"3 x",
+		"0",
+		"7",
+		"-1",
+		"3.5",
+	}
+
+	for _, input := range testcases {
+		t.Run(fmt.Sprintf("%q", input), func(t *testing.T) {
+			if _, err := ParseManualInput(input, 6); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
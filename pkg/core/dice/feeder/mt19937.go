@@ -1,30 +1,109 @@
 package feeder
 
 import (
+	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
-	"github.com/seehuhn/mt19937"
 	"math/rand"
+	"sync"
 	"time"
 )
 
 // ランダムにダイスを取り出すダイス供給機の構造体。
 // Ruby版BCDiceと同様にメルセンヌ・ツイスタを使用する。
+// ただし、出目の決め方がRubyとは異なるため、同じシードでもRuby版BCDiceとは異なるダイス列となる。
+// Ruby版BCDiceと同じダイス列が必要な場合は Ruby を使用する。
+//
+// MarshalBinary で乱数生成器の状態を保存し、UnmarshalBinary で復元できる。
+// 復元したダイス供給機は、保存時点の続きから同じ順番でダイスを供給する。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
 type MT19937 struct {
-	seed int64
-	rng  *rand.Rand
+	// 乱数生成器へのアクセスを保護する
+	mu     sync.Mutex
+	seed   int64
+	source *mt64
+	rng    *rand.Rand
 }
 
 // MT19937がFeederインターフェースを実装しているかの確認
 var _ DieFeeder = (*MT19937)(nil)
 
-// NewMT19937 は、シードを指定したMT19937ダイス供給機を返す。
-func NewMT19937(seed int64) *MT19937 {
-	f := &MT19937{
-		seed: seed,
-		rng:  rand.New(mt19937.New()),
+const (
+	// 状態ベクトルの長さ
+	mt64N = 312
+	// 状態ベクトルの更新で使う要素の間隔
+	mt64M = 156
+	// 行列Aの最下行
+	mt64MatrixA = 0xB5026F5AA96619E9
+	// 上位33ビットのマスク
+	mt64UpperMask = 0xFFFFFFFF80000000
+	// 下位31ビットのマスク
+	mt64LowerMask = 0x7FFFFFFF
+)
+
+// 64ビット版のメルセンヌ・ツイスタ（MT19937-64）の乱数源。
+//
+// github.com/seehuhn/mt19937 と同じ値を生成する。
+// 状態ベクトルを直接保存・復元できるように、このパッケージで実装している。
+type mt64 struct {
+	// 状態ベクトル
+	state [mt64N]uint64
+	// 次に出力する状態ベクトルの位置
+	index int
+}
+
+// mt64がrand.Source64インターフェースを実装しているかの確認
+var _ rand.Source64 = (*mt64)(nil)
+
+// Seed は、64ビットのシードで状態を初期化する。
+func (mt *mt64) Seed(seed int64) {
+	mt.state[0] = uint64(seed)
+	for i := 1; i < mt64N; i++ {
+		prev := mt.state[i-1]
+		mt.state[i] = 6364136223846793005*(prev^(prev>>62)) + uint64(i)
 	}
 
-	f.rng.Seed(seed)
+	mt.index = mt64N
+}
+
+// Uint64 は64ビットの乱数を返す。
+func (mt *mt64) Uint64() uint64 {
+	if mt.index >= mt64N {
+		mt.generate()
+	}
+
+	y := mt.state[mt.index]
+	mt.index++
+
+	y ^= (y >> 29) & 0x5555555555555555
+	y ^= (y << 17) & 0x71D67FFFEDA60000
+	y ^= (y << 37) & 0xFFF7EEE000000000
+	y ^= y >> 43
+
+	return y
+}
+
+// Int63 は63ビットの非負の乱数を返す。
+func (mt *mt64) Int63() int64 {
+	return int64(mt.Uint64() & 0x7FFFFFFFFFFFFFFF)
+}
+
+// generate は状態ベクトルを更新する。
+func (mt *mt64) generate() {
+	x := &mt.state
+
+	for i := 0; i < mt64N; i++ {
+		y := (x[i] & mt64UpperMask) | (x[(i+1)%mt64N] & mt64LowerMask)
+		x[i] = x[(i+mt64M)%mt64N] ^ (y >> 1) ^ ((y & 1) * mt64MatrixA)
+	}
+
+	mt.index = 0
+}
+
+// NewMT19937 は、シードを指定したMT19937ダイス供給機を返す。
+func NewMT19937(seed int64) *MT19937 {
+	f := &MT19937{}
+	f.init(seed)
 
 	return f
 }
@@ -34,6 +113,15 @@ func NewMT19937WithSeedFromTime() *MT19937 {
 	return NewMT19937(time.Now().UnixNano())
 }
 
+// init は、乱数生成器を指定したシードで初期化する。
+func (f *MT19937) init(seed int64) {
+	f.seed = seed
+	f.source = &mt64{}
+	f.rng = rand.New(f.source)
+
+	f.rng.Seed(seed)
+}
+
 // CanSpecifyDie は、供給されるダイスを指定できるかを返す。
 // MT19937ダイス供給機ではfalseを返す。
 func (f *MT19937) CanSpecifyDie() bool {
@@ -49,9 +137,60 @@ func (f *MT19937) Seed() int64 {
 //
 // sides: ダイスの面の数
 func (f *MT19937) Next(sides int) (dice.Die, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	d := dice.Die{
 		Sides: sides,
 		Value: 1 + f.rng.Intn(sides),
 	}
 	return d, nil
 }
+
+// MarshalBinary は乱数生成器の状態を直列化して返す。
+//
+// 状態はシード、状態ベクトル、および次に出力する状態ベクトルの位置で表す。
+func (f *MT19937) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := newSnapshotWriter(snapshotKindMT19937)
+	w.putVarint(f.seed)
+	for _, v := range f.source.state {
+		w.putUvarint(v)
+	}
+	w.putUvarint(uint64(f.source.index))
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化された状態を復元する。
+func (f *MT19937) UnmarshalBinary(data []byte) error {
+	source := &mt64{}
+
+	r := newSnapshotReader(snapshotKindMT19937, data)
+	seed := r.readVarint()
+	for i := range source.state {
+		source.state[i] = r.readUvarint()
+	}
+	index := r.readUvarint()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	if index > mt64N {
+		return fmt.Errorf("乱数生成器の状態の位置が不正です: %d", index)
+	}
+
+	source.index = int(index)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seed = seed
+	f.source = source
+	f.rng = rand.New(source)
+
+	return nil
+}
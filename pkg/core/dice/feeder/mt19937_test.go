@@ -3,28 +3,30 @@ package feeder
 import (
 	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/seehuhn/mt19937"
 	"reflect"
 	"testing"
 )
 
 // ダイスをランダムに供給：現在時刻をシードとする場合の例。
-func Example_mT19937WithSeedFromTime() dice.Die {
+func Example_mT19937WithSeedFromTime() {
 	// ダイスの値をランダムにする
 	dieFeeder := NewMT19937WithSeedFromTime()
 	// 6面ダイスを1個振る
 	d, _ := dieFeeder.Next(6)
 
-	return d
+	fmt.Println(d.String())
 }
 
 // ダイスをランダムに供給：シードを指定する場合の例。
-func Example_mT19937WithSpecifiedSeed() dice.Die {
+func Example_mT19937WithSpecifiedSeed() {
 	// ダイスの値をランダムにする
 	dieFeeder := NewMT19937(1)
 	// 6面ダイスを1個振る
 	d, _ := dieFeeder.Next(6)
 
-	return d
+	fmt.Println(d.String())
+	// Output: <Die 4/6>
 }
 
 func TestMT19937_CanSpecifyDie(t *testing.T) {
@@ -83,3 +85,27 @@ func TestMT19937_Next(t *testing.T) {
 		})
 	}
 }
+
+func TestMT64_SameAsSeehuhnMT19937(t *testing.T) {
+	testcases := []int64{1, 42, -12345, 20190401}
+
+	for _, seed := range testcases {
+		t.Run(fmt.Sprintf("%d", seed), func(t *testing.T) {
+			expected := mt19937.New()
+			expected.Seed(seed)
+
+			actual := &mt64{}
+			actual.Seed(seed)
+
+			// 状態ベクトルの更新をまたぐように、長さの3倍以上の値を比較する
+			for i := 0; i < 1000; i++ {
+				e := expected.Uint64()
+				a := actual.Uint64()
+
+				if a != e {
+					t.Fatalf("#%d: got %d, want %d", i, a, e)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+package feeder
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// 公平性を検証可能な方法でダイスを導出するダイス供給機の構造体。
+//
+// 各ダイスの値は HMAC-SHA256(サーバシード, "クライアントシードのバイト数:クライアントシード:ノンス") から導出する。
+// ノンスは0から始まり、ダイスを1つ供給するたびに1増える。
+//
+// 使用方法は以下のとおり。
+//
+// 1. ダイスロールの前に、サーバシードのSHA-256ハッシュ値（コミットメント）を公開する。
+//
+// 2. プレイヤーがクライアントシードを指定し、SetClientSeed で設定する。
+// クライアントシードはコミットメントの公開後に決まるため、サーバ側は出目を操作できない。
+//
+// 3. ダイスロールを行う。
+//
+// 4. セッション終了後などに Reveal でサーバシードを公開する。
+// プレイヤーは command.Result の VerifyProvablyFair（または VerifyProvablyFairDice）を使用して、
+// サーバシードがコミットメントと一致すること、
+// および振られたダイスがサーバシードから導出されたものであることを確認できる。
+//
+// 各ダイスの導出に使ったノンスは NextWithNonce で得られ、コマンドの実行結果の Nonces に記録される。
+// 複数のゴルーチンから同時にダイスを振った場合、1つのコマンドで使われるノンスは連続するとは限らない。
+//
+// サーバシードを公開した後は、ダイスを供給できなくなる。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+type ProvablyFair struct {
+	// 状態へのアクセスを保護する
+	mu sync.Mutex
+	// サーバシード
+	serverSeed []byte
+	// クライアントシード
+	clientSeed string
+	// 次に使用するノンス
+	nonce uint64
+	// サーバシードが公開されたか
+	revealed bool
+}
+
+// ProvablyFairがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*ProvablyFair)(nil)
+
+// ProvablyFairがNoncedDieFeederインターフェースを実装しているかの確認
+var _ NoncedDieFeeder = (*ProvablyFair)(nil)
+
+// サーバシードを生成する際のバイト数
+const provablyFairServerSeedSize = 32
+
+// NewProvablyFair は、指定したシードを使用する、公平性を検証可能なダイス供給機を返す。
+//
+// クライアントシードは、コミットメントの公開後に SetClientSeed で設定し直せる。
+//
+// serverSeed: サーバシード（ダイスロールが終わるまで秘密にする）,
+// clientSeed: クライアントシードの初期値。
+func NewProvablyFair(serverSeed []byte, clientSeed string) *ProvablyFair {
+	f := &ProvablyFair{
+		serverSeed: make([]byte, len(serverSeed)),
+		clientSeed: clientSeed,
+	}
+	copy(f.serverSeed, serverSeed)
+
+	return f
+}
+
+// NewProvablyFairWithRandomServerSeed は、crypto/rand で生成したサーバシードを使用する、
+// 公平性を検証可能なダイス供給機を返す。
+//
+// clientSeed: クライアントシードの初期値
+func NewProvablyFairWithRandomServerSeed(clientSeed string) (*ProvablyFair, error) {
+	serverSeed := make([]byte, provablyFairServerSeedSize)
+	if _, err := rand.Read(serverSeed); err != nil {
+		return nil, fmt.Errorf("サーバシードの生成に失敗しました: %s", err)
+	}
+
+	return NewProvablyFair(serverSeed, clientSeed), nil
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// ProvablyFairダイス供給機ではfalseを返す。
+func (f *ProvablyFair) CanSpecifyDie() bool {
+	return false
+}
+
+// Commitment は、サーバシードのコミットメント（SHA-256ハッシュ値の16進表記）を返す。
+// ダイスロールの前に公開する。
+func (f *ProvablyFair) Commitment() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return ProvablyFairCommitment(f.serverSeed)
+}
+
+// ClientSeed はクライアントシードを返す。
+func (f *ProvablyFair) ClientSeed() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.clientSeed
+}
+
+// SetClientSeed は、クライアントシードを設定する。
+// コミットメントを公開した後、プレイヤーが指定したクライアントシードを設定するために使う。
+// セッションの途中でクライアントシードを変更することもできる。
+//
+// ノンスはリセットしない。同じクライアントシードを設定し直しても、同じダイスが繰り返されることはない。
+// 変更後のダイスは、変更後のクライアントシードで検証する。
+// 1つのコマンドの結果が2つのクライアントシードにまたがらないように、コマンドの実行中には変更しないこと。
+//
+// サーバシードが公開済みの場合はエラーを返す。
+func (f *ProvablyFair) SetClientSeed(clientSeed string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.revealed {
+		return locale.NewError(locale.MSG_ERR_SERVER_SEED_REVEALED)
+	}
+
+	f.clientSeed = clientSeed
+
+	return nil
+}
+
+// Nonce は、次のダイスの導出に使用するノンスを返す。
+//
+// 他のゴルーチンがダイスを振っている場合、この値はコマンドの実行時には変わっている可能性がある。
+// コマンドの実行結果を検証するには、結果に記録されたノンスを使うこと。
+func (f *ProvablyFair) Nonce() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.nonce
+}
+
+// Reveal はサーバシードを公開する。
+// これ以降、このダイス供給機はダイスを供給できなくなる。
+func (f *ProvablyFair) Reveal() []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.revealed = true
+
+	serverSeed := make([]byte, len(f.serverSeed))
+	copy(serverSeed, f.serverSeed)
+
+	return serverSeed
+}
+
+// IsRevealed は、サーバシードが公開済みならばtrueを、そうでなければfalseを返す。
+func (f *ProvablyFair) IsRevealed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.revealed
+}
+
+// Next は、サーバシード、クライアントシード、ノンスからダイスを1つ導出して供給する。
+// サーバシードが公開済みの場合はエラーを返す。
+//
+// sides: ダイスの面の数
+func (f *ProvablyFair) Next(sides int) (dice.Die, error) {
+	d, _, _, err := f.NextWithNonce(sides)
+	return d, err
+}
+
+// NextWithNonce は、Next と同様にダイスを1つ導出し、その導出に使ったノンスとともに返す。
+// ダイスは常にノンスから導出されるため、エラーがなければokは真となる。
+//
+// sides: ダイスの面の数
+func (f *ProvablyFair) NextWithNonce(sides int) (dice.Die, uint64, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.revealed {
+		return dice.Die{}, 0, false, locale.NewError(locale.MSG_ERR_SERVER_SEED_REVEALED)
+	}
+
+	nonce := f.nonce
+
+	d, err := DeriveProvablyFairDie(f.serverSeed, f.clientSeed, nonce, sides)
+	if err != nil {
+		return dice.Die{}, 0, false, err
+	}
+
+	f.nonce++
+
+	return d, nonce, true, nil
+}
+
+// MarshalBinary は、シード、ノンスおよびサーバシードが公開済みかを直列化して返す。
+//
+// 結果にはサーバシードがそのまま含まれるため、公開前は秘密にしておかなければならない。
+func (f *ProvablyFair) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := newSnapshotWriter(snapshotKindProvablyFair)
+	w.putBytes(f.serverSeed)
+	w.putBytes([]byte(f.clientSeed))
+	w.putUvarint(f.nonce)
+	w.putBool(f.revealed)
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化された状態を復元する。
+func (f *ProvablyFair) UnmarshalBinary(data []byte) error {
+	r := newSnapshotReader(snapshotKindProvablyFair, data)
+	serverSeed := r.readBytes()
+	clientSeed := r.readBytes()
+	nonce := r.readUvarint()
+	revealed := r.readBool()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.serverSeed = serverSeed
+	f.clientSeed = string(clientSeed)
+	f.nonce = nonce
+	f.revealed = revealed
+
+	return nil
+}
+
+// ProvablyFairCommitment は、サーバシードのコミットメント（SHA-256ハッシュ値の16進表記）を返す。
+func ProvablyFairCommitment(serverSeed []byte) string {
+	sum := sha256.Sum256(serverSeed)
+	return hex.EncodeToString(sum[:])
+}
+
+// DeriveProvablyFairDie は、サーバシード、クライアントシード、ノンスからダイスを導出する。
+//
+// HMAC-SHA256(serverSeed, "len:clientSeed:nonce") の出力を8バイトずつ区切った値を
+// 64ビットの一様乱数として使用し、棄却サンプリングで出目を決める。
+// len は clientSeed のバイト数で、clientSeed が ":" を含んでもメッセージが一意に定まるようにする。
+// 出力をすべて棄却した場合は、HMAC-SHA256(serverSeed, "len:clientSeed:nonce:1")、
+// HMAC-SHA256(serverSeed, "len:clientSeed:nonce:2")、... の出力を順に使用する。
+//
+// serverSeed: サーバシード,
+// clientSeed: クライアントシード,
+// nonce: ノンス,
+// sides: ダイスの面の数。
+func DeriveProvablyFairDie(
+	serverSeed []byte,
+	clientSeed string,
+	nonce uint64,
+	sides int,
+) (dice.Die, error) {
+	if sides < 1 {
+		return dice.Die{}, locale.NewError(locale.MSG_ERR_TOO_FEW_SIDES, sides)
+	}
+
+	var digest []byte
+	round := 0
+
+	next := func() (uint64, error) {
+		if len(digest) == 0 {
+			mac := hmac.New(sha256.New, serverSeed)
+			mac.Write([]byte(provablyFairMessage(clientSeed, nonce, round)))
+			digest = mac.Sum(nil)
+
+			round++
+		}
+
+		v := binary.BigEndian.Uint64(digest[:8])
+		digest = digest[8:]
+
+		return v, nil
+	}
+
+	v, err := uniformUint64(next, uint64(sides))
+	if err != nil {
+		return dice.Die{}, err
+	}
+
+	d := dice.Die{
+		Sides: sides,
+		Value: 1 + int(v),
+	}
+	return d, nil
+}
+
+// provablyFairMessage は、ダイスの導出でHMACに与えるメッセージを返す。
+//
+// クライアントシードの前にそのバイト数を付けるため、クライアントシードが ":" を含んでも、
+// 異なるクライアントシード、ノンス、ラウンドの組が同じメッセージになることはない。
+// ラウンドが0の場合は、ラウンドを含めない。
+func provablyFairMessage(clientSeed string, nonce uint64, round int) string {
+	message := fmt.Sprintf("%d:%s:%d", len(clientSeed), clientSeed, nonce)
+	if round > 0 {
+		message = fmt.Sprintf("%s:%d", message, round)
+	}
+
+	return message
+}
+
+// VerifyProvablyFairDice は、公開されたサーバシードを使用して、振られたダイスを検証する。
+//
+// サーバシードがコミットメントと一致し、かつすべてのダイスがシードと対応するノンスから
+// 導出されたものと一致すればnilを返す。そうでなければ、どこで一致しなかったかを表すエラーを返す。
+//
+// commitment: ダイスロール前に公開されたコミットメント,
+// serverSeed: 公開されたサーバシード,
+// clientSeed: クライアントシード,
+// rolledDice: 振られたダイス（command.Result の RolledDice など）,
+// nonces: 各ダイスの導出に使用されたノンス（command.Result の Nonces など）。
+func VerifyProvablyFairDice(
+	commitment string,
+	serverSeed []byte,
+	clientSeed string,
+	rolledDice []dice.Die,
+	nonces []uint64,
+) error {
+	if ProvablyFairCommitment(serverSeed) != commitment {
+		return fmt.Errorf("サーバシードがコミットメントと一致しません")
+	}
+
+	if len(nonces) != len(rolledDice) {
+		return fmt.Errorf("ノンスの数（%d）がダイスの数（%d）と一致しません",
+			len(nonces), len(rolledDice))
+	}
+
+	for i, actual := range rolledDice {
+		nonce := nonces[i]
+
+		expected, err := DeriveProvablyFairDie(serverSeed, clientSeed, nonce, actual.Sides)
+		if err != nil {
+			return fmt.Errorf("#%d (nonce: %d): %s", i+1, nonce, err)
+		}
+
+		if actual != expected {
+			return fmt.Errorf(
+				"#%d (nonce: %d): %d/%d: ダイスが一致しません（導出された値: %d/%d）",
+				i+1, nonce, actual.Value, actual.Sides, expected.Value, expected.Sides)
+		}
+	}
+
+	return nil
+}
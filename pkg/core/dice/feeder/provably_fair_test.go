@@ -0,0 +1,350 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"testing"
+)
+
+// 公平性を検証可能なダイスロールの例。
+func Example_provablyFair() {
+	dieFeeder := NewProvablyFair([]byte("server-seed"), "")
+
+	// ダイスロールの前にコミットメントを公開する
+	commitment := dieFeeder.Commitment()
+	fmt.Println("コミットメント: " + commitment)
+
+	// プレイヤーが指定したクライアントシードを設定する
+	dieFeeder.SetClientSeed("player1")
+
+	// 6面ダイスを2個振る（検証のため、導出に使われたノンスも記録する）
+	d1, n1, _, _ := dieFeeder.NextWithNonce(6)
+	d2, n2, _, _ := dieFeeder.NextWithNonce(6)
+	rolledDice := []dice.Die{d1, d2}
+	fmt.Println("ダイス: " + dice.FormatDice(rolledDice))
+
+	// ダイスロールの後にサーバシードを公開する
+	serverSeed := dieFeeder.Reveal()
+
+	// プレイヤーはダイスを検証できる
+	err := VerifyProvablyFairDice(commitment, serverSeed, "player1", rolledDice, []uint64{n1, n2})
+	fmt.Println("検証結果:", err)
+	// Output:
+	// コミットメント: 91024ec49c5bec0b689e42892526320fce08337205c91de94c7a588c20d08eeb
+	// ダイス: 5/6, 6/6
+	// 検証結果: <nil>
+}
+
+func TestProvablyFair_CanSpecifyDie(t *testing.T) {
+	f := NewProvablyFair([]byte("server-seed"), "player1")
+
+	if f.CanSpecifyDie() {
+		t.Fatalf("ProvablyFairはダイスを指定できてはならない")
+	}
+}
+
+func TestProvablyFair_Next(t *testing.T) {
+	// Pythonのhmacモジュールで計算した値
+	expected := []dice.Die{
+		{5, 6}, {6, 6}, {6, 6}, {1, 6}, {1, 6}, {3, 6},
+		{3, 10}, {6, 10}, {56, 100}, {5, 20}, {1, 4},
+	}
+
+	f := NewProvablyFair([]byte("server-seed"), "player1")
+
+	for i, e := range expected {
+		actual, err := f.Next(e.Sides)
+		if err != nil {
+			t.Fatalf("#%d: got err: %s", i, err)
+		}
+
+		if actual != e {
+			t.Errorf("#%d: wrong die: got %s, want %s", i, actual, e)
+		}
+	}
+
+	if f.Nonce() != uint64(len(expected)) {
+		t.Errorf("wrong nonce: got %d, want %d", f.Nonce(), len(expected))
+	}
+}
+
+func TestProvablyFair_NextWithNonce(t *testing.T) {
+	expected := []dice.Die{{5, 6}, {6, 6}, {6, 6}}
+
+	f := NewProvablyFair([]byte("server-seed"), "player1")
+
+	for i, e := range expected {
+		actual, nonce, ok, err := f.NextWithNonce(e.Sides)
+		if err != nil {
+			t.Fatalf("#%d: got err: %s", i, err)
+		}
+
+		if !ok {
+			t.Errorf("#%d: ノンスが返されなかった", i)
+		}
+
+		if actual != e {
+			t.Errorf("#%d: wrong die: got %s, want %s", i, actual, e)
+		}
+
+		if nonce != uint64(i) {
+			t.Errorf("#%d: wrong nonce: got %d, want %d", i, nonce, i)
+		}
+	}
+}
+
+func TestProvablyFair_Next_ShouldDependOnClientSeed(t *testing.T) {
+	f1 := NewProvablyFair([]byte("server-seed"), "player1")
+	f2 := NewProvablyFair([]byte("server-seed"), "player2")
+
+	ds1 := make([]dice.Die, 0, 20)
+	ds2 := make([]dice.Die, 0, 20)
+	for i := 0; i < 20; i++ {
+		d1, _ := f1.Next(100)
+		d2, _ := f2.Next(100)
+		ds1 = append(ds1, d1)
+		ds2 = append(ds2, d2)
+	}
+
+	if reflect.DeepEqual(ds1, ds2) {
+		t.Errorf("クライアントシードが異なるのに同じダイス列が導出された: %v", ds1)
+	}
+}
+
+func TestProvablyFair_SetClientSeed(t *testing.T) {
+	serverSeed := []byte("server-seed")
+
+	// コミットメントを公開してから、プレイヤーがクライアントシードを指定する
+	f := NewProvablyFair(serverSeed, "")
+	commitment := f.Commitment()
+
+	if err := f.SetClientSeed("player1"); err != nil {
+		t.Fatalf("設定エラー: %s", err)
+	}
+
+	if f.ClientSeed() != "player1" {
+		t.Fatalf("異なるクライアントシード: got %q, want %q", f.ClientSeed(), "player1")
+	}
+
+	d1, n1, _, err := f.NextWithNonce(6)
+	if err != nil {
+		t.Fatalf("#1: got err: %s", err)
+	}
+
+	// 途中でクライアントシードを変更しても、ノンスは続きから使われる
+	if err := f.SetClientSeed("player2"); err != nil {
+		t.Fatalf("変更エラー: %s", err)
+	}
+
+	d2, n2, _, err := f.NextWithNonce(6)
+	if err != nil {
+		t.Fatalf("#2: got err: %s", err)
+	}
+
+	if n2 != n1+1 {
+		t.Errorf("ノンスがリセットされた: got %d, want %d", n2, n1+1)
+	}
+
+	f.Reveal()
+
+	if err := VerifyProvablyFairDice(commitment, serverSeed, "player1", []dice.Die{d1}, []uint64{n1}); err != nil {
+		t.Errorf("変更前のダイスの検証エラー: %s", err)
+	}
+
+	if err := VerifyProvablyFairDice(commitment, serverSeed, "player2", []dice.Die{d2}, []uint64{n2}); err != nil {
+		t.Errorf("変更後のダイスの検証エラー: %s", err)
+	}
+
+	if err := f.SetClientSeed("player3"); err == nil {
+		t.Error("サーバシードの公開後に設定できた")
+	}
+}
+
+func TestProvablyFair_Reveal(t *testing.T) {
+	serverSeed := []byte("server-seed")
+	f := NewProvablyFair(serverSeed, "player1")
+
+	if f.IsRevealed() {
+		t.Fatal("公開前なのに公開済みになっている")
+	}
+
+	revealed := f.Reveal()
+	if !reflect.DeepEqual(revealed, serverSeed) {
+		t.Errorf("wrong server seed: got %q, want %q", revealed, serverSeed)
+	}
+
+	if !f.IsRevealed() {
+		t.Error("公開後なのに公開済みになっていない")
+	}
+
+	if _, err := f.Next(6); err == nil {
+		t.Error("公開後にダイスを供給できてしまった")
+	}
+}
+
+func TestNewProvablyFairWithRandomServerSeed(t *testing.T) {
+	f1, err := NewProvablyFairWithRandomServerSeed("player1")
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	f2, err := NewProvablyFairWithRandomServerSeed("player1")
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if f1.Commitment() == f2.Commitment() {
+		t.Error("同じサーバシードが生成された")
+	}
+
+	if len(f1.Reveal()) != provablyFairServerSeedSize {
+		t.Errorf("wrong server seed size: got %d, want %d",
+			len(f1.Reveal()), provablyFairServerSeedSize)
+	}
+}
+
+func TestVerifyProvablyFairDice(t *testing.T) {
+	serverSeed := []byte("server-seed")
+	commitment := ProvablyFairCommitment(serverSeed)
+
+	testcases := []struct {
+		name       string
+		commitment string
+		serverSeed []byte
+		clientSeed string
+		dice       []dice.Die
+		nonces     []uint64
+		err        bool
+	}{
+		{
+			name:       "正しいダイス",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{{5, 6}, {6, 6}, {6, 6}},
+			nonces:     []uint64{0, 1, 2},
+		},
+		{
+			name:       "途中のノンスから",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{{56, 100}, {5, 20}},
+			nonces:     []uint64{8, 9},
+		},
+		{
+			// 他のゴルーチンがノンス1を使った場合
+			name:       "連続しないノンス",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{{5, 6}, {6, 6}},
+			nonces:     []uint64{0, 2},
+		},
+		{
+			name:       "ダイスなし",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{},
+			nonces:     []uint64{},
+		},
+		{
+			name:       "コミットメントと一致しない",
+			commitment: commitment,
+			serverSeed: []byte("another-seed"),
+			clientSeed: "player1",
+			dice:       []dice.Die{},
+			err:        true,
+		},
+		{
+			name:       "クライアントシードが異なる",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player2",
+			dice:       []dice.Die{{5, 6}, {6, 6}, {6, 6}},
+			nonces:     []uint64{0, 1, 2},
+			err:        true,
+		},
+		{
+			name:       "ダイスが改ざんされている",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{{5, 6}, {5, 6}, {6, 6}},
+			nonces:     []uint64{0, 1, 2},
+			err:        true,
+		},
+		{
+			name:       "ノンスが異なる",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{{5, 6}, {6, 6}, {6, 6}},
+			nonces:     []uint64{1, 2, 3},
+			err:        true,
+		},
+		{
+			name:       "ノンスの数がダイスの数と一致しない",
+			commitment: commitment,
+			serverSeed: serverSeed,
+			clientSeed: "player1",
+			dice:       []dice.Die{{5, 6}, {6, 6}, {6, 6}},
+			nonces:     []uint64{0, 1},
+			err:        true,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			err := VerifyProvablyFairDice(
+				test.commitment,
+				test.serverSeed,
+				test.clientSeed,
+				test.dice,
+				test.nonces,
+			)
+
+			if err != nil {
+				if !test.err {
+					t.Fatalf("got err: %s", err)
+				}
+
+				return
+			}
+
+			if test.err {
+				t.Fatal("should err")
+			}
+		})
+	}
+}
+
+func TestProvablyFairMessage(t *testing.T) {
+	testcases := []struct {
+		clientSeed string
+		nonce      uint64
+		round      int
+		expected   string
+	}{
+		{"player1", 0, 0, "7:player1:0"},
+		{"player1", 12, 3, "7:player1:12:3"},
+		{"", 1, 0, "0::1"},
+		// ":" を含むクライアントシードでも、他の組と同じメッセージにならない
+		{"a:1", 2, 0, "3:a:1:2"},
+		{"a", 1, 2, "1:a:1:2"},
+		{"クライアント", 0, 0, "18:クライアント:0"},
+	}
+
+	for _, test := range testcases {
+		name := fmt.Sprintf("%q,%d,%d", test.clientSeed, test.nonce, test.round)
+
+		t.Run(name, func(t *testing.T) {
+			actual := provablyFairMessage(test.clientSeed, test.nonce, test.round)
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
@@ -1,12 +1,18 @@
 package feeder
 
 import (
-	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"sync"
 )
 
 // 指定したダイスを取り出せる、キュー型ダイス供給機の構造体。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+// ただし、その場合にどのゴルーチンにどのダイスが供給されるかは不定となる。
 type Queue struct {
+	// キューへのアクセスを保護する
+	mu    sync.Mutex
 	queue []dice.Die
 }
 
@@ -36,6 +42,9 @@ func (f *Queue) CanSpecifyDie() bool {
 
 // Dice は、現在のキューの内容をコピーして返す。
 func (f *Queue) Dice() []dice.Die {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	copiedDice := make([]dice.Die, len(f.queue))
 	copy(copiedDice, f.queue)
 
@@ -45,8 +54,11 @@ func (f *Queue) Dice() []dice.Die {
 // Next はキューからダイスを1つ取り出して供給する。
 // キューが空だった場合はエラーを返す。
 func (f *Queue) Next(_ int) (dice.Die, error) {
-	if f.IsEmpty() {
-		return dice.Die{}, fmt.Errorf("取り出せるダイスがありません")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.queue) == 0 {
+		return dice.Die{}, locale.NewError(locale.MSG_ERR_NO_DICE_LEFT)
 	}
 
 	// キューからダイスを取り出す
@@ -58,29 +70,42 @@ func (f *Queue) Next(_ int) (dice.Die, error) {
 
 // Push はダイスをキューに追加する。
 func (f *Queue) Push(d dice.Die) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.queue = append(f.queue, d)
 }
 
 // Append は複数のダイスをキューの末尾に追加する。
 func (f *Queue) Append(dice []dice.Die) {
-	for _, d := range dice {
-		f.Push(d)
-	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queue = append(f.queue, dice...)
 }
 
 // Clear はキューを空にする。
 func (f *Queue) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.queue = []dice.Die{}
 }
 
 // Set は指定されたダイスをキューに配置する。
 func (f *Queue) Set(d []dice.Die) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.queue = make([]dice.Die, len(d))
 	copy(f.queue, d)
 }
 
 // Remaining は残りのダイスの数を返す。
 func (f *Queue) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	return len(f.queue)
 }
 
@@ -88,3 +113,28 @@ func (f *Queue) Remaining() int {
 func (f *Queue) IsEmpty() bool {
 	return f.Remaining() == 0
 }
+
+// MarshalBinary はキューの内容を直列化して返す。
+func (f *Queue) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := newSnapshotWriter(snapshotKindQueue)
+	w.putDice(f.queue)
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化されたキューの内容を復元する。
+func (f *Queue) UnmarshalBinary(data []byte) error {
+	r := newSnapshotReader(snapshotKindQueue, data)
+	ds := r.readDice()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	f.Set(ds)
+
+	return nil
+}
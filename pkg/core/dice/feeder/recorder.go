@@ -0,0 +1,150 @@
+package feeder
+
+import (
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+// 他のダイス供給機が供給したダイスを記録する、記録型ダイス供給機の構造体。
+//
+// 記録したダイスは、テストデータの "rand:" 行と同じ "値/面数,値/面数,..." という
+// 形式で出力できる。出力したダイス列は Replay で再生できる。
+//
+// 実際にダイスを供給するダイス供給機が状態の保存に対応している場合、
+// MarshalBinary で記録とその状態をまとめて保存し、UnmarshalBinary で復元できる。
+//
+// 実際にダイスを供給するダイス供給機が複数のゴルーチンから同時に使用しても安全な場合、
+// Recorderも複数のゴルーチンから同時に使用しても安全である。
+type Recorder struct {
+	// 記録へのアクセスを保護する
+	mu sync.Mutex
+	// 実際にダイスを供給するダイス供給機
+	feeder DieFeeder
+	// 記録されたダイス
+	recordedDice []dice.Die
+}
+
+// RecorderがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*Recorder)(nil)
+
+// RecorderがNoncedDieFeederインターフェースを実装しているかの確認
+var _ NoncedDieFeeder = (*Recorder)(nil)
+
+// NewRecorder は、指定したダイス供給機が供給したダイスを記録するダイス供給機を返す。
+//
+// f: 実際にダイスを供給するダイス供給機
+func NewRecorder(f DieFeeder) *Recorder {
+	return &Recorder{
+		feeder:       f,
+		recordedDice: []dice.Die{},
+	}
+}
+
+// Feeder は、実際にダイスを供給するダイス供給機を返す。
+func (f *Recorder) Feeder() DieFeeder {
+	return f.feeder
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// 実際にダイスを供給するダイス供給機の結果をそのまま返す。
+func (f *Recorder) CanSpecifyDie() bool {
+	return f.feeder.CanSpecifyDie()
+}
+
+// Next は、実際にダイスを供給するダイス供給機からダイスを1つ取り出して記録し、供給する。
+// ダイスの取り出しに失敗した場合は、何も記録せずにエラーを返す。
+//
+// sides: ダイスの面の数
+func (f *Recorder) Next(sides int) (dice.Die, error) {
+	d, _, _, err := f.NextWithNonce(sides)
+	return d, err
+}
+
+// NextWithNonce は、Next と同様にダイスを取り出して記録し、その導出に使ったノンスとともに供給する。
+// 実際にダイスを供給するダイス供給機がノンスを返さない場合、okは偽となる。
+//
+// sides: ダイスの面の数
+func (f *Recorder) NextWithNonce(sides int) (dice.Die, uint64, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// 状態の保存時に記録と内側のダイス供給機の状態が食い違わないように、
+	// ロックしたままダイスを取り出す
+	d, nonce, ok, err := NextWithNonce(f.feeder, sides)
+	if err != nil {
+		return dice.Die{}, 0, false, err
+	}
+
+	f.recordedDice = append(f.recordedDice, d)
+
+	return d, nonce, ok, nil
+}
+
+// RecordedDice は、記録されたダイスをコピーして返す。
+func (f *Recorder) RecordedDice() []dice.Die {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	copiedDice := make([]dice.Die, len(f.recordedDice))
+	copy(copiedDice, f.recordedDice)
+
+	return copiedDice
+}
+
+// Log は、記録されたダイスを "値/面数,値/面数,..." という形式の文字列として返す。
+// 結果は dice.ParseDice および NewReplayFromLog で読み込むことができる。
+func (f *Recorder) Log() string {
+	return dice.FormatDiceWithoutSpaces(f.RecordedDice())
+}
+
+// Clear は記録を消去する。
+func (f *Recorder) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.recordedDice = []dice.Die{}
+}
+
+// MarshalBinary は、記録されたダイスと、実際にダイスを供給するダイス供給機の状態を直列化して返す。
+// 実際にダイスを供給するダイス供給機が状態の保存に対応していない場合はエラーを返す。
+func (f *Recorder) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	inner, err := marshalFeeder(f.feeder)
+	if err != nil {
+		return nil, err
+	}
+
+	w := newSnapshotWriter(snapshotKindRecorder)
+	w.putDice(f.recordedDice)
+	w.putBytes(inner)
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化された状態を復元する。
+//
+// 実際にダイスを供給するダイス供給機には、保存時と同じ種類のものを設定しておくこと。
+// 状態の復元に失敗した場合、記録は変更しない。
+func (f *Recorder) UnmarshalBinary(data []byte) error {
+	r := newSnapshotReader(snapshotKindRecorder, data)
+	recordedDice := r.readDice()
+	inner := r.readBytes()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := unmarshalFeeder(f.feeder, inner); err != nil {
+		return err
+	}
+
+	f.recordedDice = recordedDice
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"testing"
+)
+
+// 供給されたダイスを記録する場合の例。
+func Example_recorder() {
+	dieFeeder := NewRecorder(NewQueue([]dice.Die{{1, 6}, {3, 6}, {5, 10}}))
+
+	dieFeeder.Next(6)
+	dieFeeder.Next(6)
+	dieFeeder.Next(10)
+
+	// テストデータの "rand:" 行と同じ形式で出力する
+	fmt.Println(dieFeeder.Log())
+	// Output: 1/6,3/6,5/10
+}
+
+func TestRecorder_CanSpecifyDie(t *testing.T) {
+	if !NewRecorder(NewEmptyQueue()).CanSpecifyDie() {
+		t.Error("Queueを記録する場合はダイスを指定できなければならない")
+	}
+
+	if NewRecorder(NewMT19937(1)).CanSpecifyDie() {
+		t.Error("MT19937を記録する場合はダイスを指定できてはならない")
+	}
+}
+
+func TestRecorder_Next(t *testing.T) {
+	testcases := [][]dice.Die{
+		{{4, 6}, {1, 6}, {2, 6}, {6, 6}, {5, 6}, {3, 6}},
+		{{2, 2}, {1, 4}, {2, 6}, {4, 10}, {3, 20}},
+	}
+
+	for _, ds := range testcases {
+		t.Run("["+dice.FormatDiceWithoutSpaces(ds)+"]", func(t *testing.T) {
+			f := NewRecorder(NewMT19937(1))
+
+			for _, d := range ds {
+				if _, err := f.Next(d.Sides); err != nil {
+					t.Fatalf("got err: %s", err)
+				}
+			}
+
+			if actual := f.RecordedDice(); !reflect.DeepEqual(actual, ds) {
+				t.Errorf("wrong recorded dice: got %v, want %v", actual, ds)
+			}
+
+			expectedLog := dice.FormatDiceWithoutSpaces(ds)
+			if actual := f.Log(); actual != expectedLog {
+				t.Errorf("wrong log: got %q, want %q", actual, expectedLog)
+			}
+		})
+	}
+}
+
+func TestRecorder_NextWithNonce(t *testing.T) {
+	testcases := []struct {
+		name           string
+		feeder         DieFeeder
+		expectedNonces []uint64
+		expectedOk     bool
+	}{
+		{
+			name:           "ProvablyFair",
+			feeder:         NewProvablyFair([]byte("server-seed"), "player1"),
+			expectedNonces: []uint64{0, 1, 2},
+			expectedOk:     true,
+		},
+		{
+			name:           "MT19937",
+			feeder:         NewMT19937(1),
+			expectedNonces: []uint64{0, 0, 0},
+			expectedOk:     false,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewRecorder(test.feeder)
+
+			for i, expectedNonce := range test.expectedNonces {
+				_, nonce, ok, err := f.NextWithNonce(6)
+				if err != nil {
+					t.Fatalf("#%d: got err: %s", i, err)
+				}
+
+				if ok != test.expectedOk {
+					t.Errorf("#%d: ok: got %t, want %t", i, ok, test.expectedOk)
+				}
+
+				if nonce != expectedNonce {
+					t.Errorf("#%d: wrong nonce: got %d, want %d", i, nonce, expectedNonce)
+				}
+			}
+
+			if actual := len(f.RecordedDice()); actual != len(test.expectedNonces) {
+				t.Errorf("wrong number of recorded dice: got %d, want %d",
+					actual, len(test.expectedNonces))
+			}
+		})
+	}
+}
+
+func TestRecorder_Next_ShouldNotRecordOnError(t *testing.T) {
+	f := NewRecorder(NewQueue([]dice.Die{{3, 6}}))
+
+	if _, err := f.Next(6); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if _, err := f.Next(6); err == nil {
+		t.Fatal("エラーが発生しませんでした")
+	}
+
+	if actual := f.Log(); actual != "3/6" {
+		t.Errorf("wrong log: got %q, want %q", actual, "3/6")
+	}
+}
+
+func TestRecorder_Clear(t *testing.T) {
+	f := NewRecorder(NewMT19937(1))
+	f.Next(6)
+	f.Next(6)
+
+	f.Clear()
+
+	if actual := f.Log(); actual != "" {
+		t.Errorf("記録が消去されていない: %q", actual)
+	}
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	recorder := NewRecorder(NewMT19937(20190401))
+	sides := []int{6, 6, 10, 100, 4, 20}
+
+	for _, s := range sides {
+		recorder.Next(s)
+	}
+
+	replay, err := NewReplayFromLog(recorder.Log())
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	for i, expected := range recorder.RecordedDice() {
+		actual, err := replay.Next(sides[i])
+		if err != nil {
+			t.Fatalf("#%d: got err: %s", i, err)
+		}
+
+		if actual != expected {
+			t.Errorf("#%d: wrong die: got %s, want %s", i, actual, expected)
+		}
+	}
+
+	if !replay.IsFinished() {
+		t.Errorf("%d dice remain", replay.Remaining())
+	}
+}
@@ -0,0 +1,147 @@
+package feeder
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// 記録されたダイスを順に供給する、再生型ダイス供給機の構造体。
+//
+// Queue と異なり、要求された面数と記録されたダイスの面数が一致するかを確認する。
+// 一致しなかった場合は、記録時とは異なるダイスロールが行われたとみなしてエラーを返す。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+type Replay struct {
+	// 再生位置へのアクセスを保護する
+	mu sync.Mutex
+	// 再生するダイス
+	dice []dice.Die
+	// 次に供給するダイスの位置
+	position int
+}
+
+// ReplayがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*Replay)(nil)
+
+// NewReplay は、指定したダイスを順に供給する再生型ダイス供給機を返す。
+//
+// ds: 再生するダイスのスライス
+func NewReplay(ds []dice.Die) *Replay {
+	f := &Replay{
+		dice: make([]dice.Die, len(ds)),
+	}
+	copy(f.dice, ds)
+
+	return f
+}
+
+// NewReplayFromLog は、"値/面数,値/面数,..." という形式のダイスの記録を読み込み、
+// 再生型ダイス供給機を返す。記録の形式が正しくなかった場合はエラーを返す。
+//
+// log: ダイスの記録（Recorder.Log の結果やテストデータの "rand:" 行の内容）
+func NewReplayFromLog(log string) (*Replay, error) {
+	ds, err := dice.ParseDice(log)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplay(ds), nil
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// 再生型ダイス供給機ではtrueを返す。
+func (f *Replay) CanSpecifyDie() bool {
+	return true
+}
+
+// Next は記録されたダイスを1つ供給する。
+//
+// 再生するダイスが残っていなかった場合、および記録されたダイスの面数が
+// sides と一致しなかった場合はエラーを返す。エラーの場合、再生位置は進まない。
+//
+// sides: ダイスの面の数
+func (f *Replay) Next(sides int) (dice.Die, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.position >= len(f.dice) {
+		return dice.Die{}, locale.NewError(locale.MSG_ERR_NO_DICE_LEFT)
+	}
+
+	d := f.dice[f.position]
+	if d.Sides != sides {
+		return dice.Die{}, locale.NewError(
+			locale.MSG_ERR_SIDES_MISMATCH, sides, fmt.Sprintf("%d/%d", d.Value, d.Sides))
+	}
+
+	f.position++
+
+	return d, nil
+}
+
+// Position は、これまでに供給したダイスの数を返す。
+func (f *Replay) Position() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.position
+}
+
+// Remaining は残りのダイスの数を返す。
+func (f *Replay) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.dice) - f.position
+}
+
+// IsFinished は、記録されたダイスをすべて供給したならばtrueを、そうでなければfalseを返す。
+func (f *Replay) IsFinished() bool {
+	return f.Remaining() == 0
+}
+
+// Rewind は再生位置を最初に戻す。
+func (f *Replay) Rewind() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.position = 0
+}
+
+// MarshalBinary は、再生するダイスおよび再生位置を直列化して返す。
+func (f *Replay) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := newSnapshotWriter(snapshotKindReplay)
+	w.putDice(f.dice)
+	w.putUvarint(uint64(f.position))
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化された再生するダイスおよび再生位置を復元する。
+func (f *Replay) UnmarshalBinary(data []byte) error {
+	r := newSnapshotReader(snapshotKindReplay, data)
+	ds := r.readDice()
+	position := r.readUvarint()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	if position > uint64(len(ds)) {
+		return fmt.Errorf("再生位置がダイスの数を超えています: %d > %d", position, len(ds))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.dice = ds
+	f.position = int(position)
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"testing"
+)
+
+// 記録されたダイスを再生する場合の例。
+func Example_replay() {
+	dieFeeder, err := NewReplayFromLog("1/6,3/6,5/10")
+	if err != nil {
+		return
+	}
+
+	d, _ := dieFeeder.Next(6)
+	fmt.Println(d.String())
+
+	// 面数が記録と一致しない場合はエラーとなる
+	_, err = dieFeeder.Next(10)
+	fmt.Println(err)
+	// Output:
+	// <Die 1/6>
+	// ダイスの面数が指定と一致しません: 10面に対して 3/6
+}
+
+func TestReplay_CanSpecifyDie(t *testing.T) {
+	f := NewReplay([]dice.Die{})
+
+	if !f.CanSpecifyDie() {
+		t.Fatalf("Replayはダイスを指定できてなければならない")
+	}
+}
+
+func TestNewReplayFromLog_Error(t *testing.T) {
+	testcases := []string{"1", "1/6,", "a/6", "1/6 2/6"}
+
+	for _, log := range testcases {
+		t.Run(fmt.Sprintf("%q", log), func(t *testing.T) {
+			if _, err := NewReplayFromLog(log); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
+
+func TestReplay_Next(t *testing.T) {
+	ds := []dice.Die{{2, 4}, {3, 6}, {5, 10}, {10, 20}}
+	f := NewReplay(ds)
+
+	for i, expected := range ds {
+		actual, err := f.Next(expected.Sides)
+		if err != nil {
+			t.Fatalf("#%d: got err: %s", i, err)
+		}
+
+		if actual != expected {
+			t.Errorf("#%d: wrong die: got %s, want %s", i, actual, expected)
+		}
+
+		if f.Position() != i+1 {
+			t.Errorf("#%d: wrong position: got %d, want %d", i, f.Position(), i+1)
+		}
+	}
+
+	if _, err := f.Next(6); err == nil {
+		t.Error("再生するダイスがないのにエラーが発生しなかった")
+	}
+}
+
+func TestReplay_Next_SidesMismatch(t *testing.T) {
+	f := NewReplay([]dice.Die{{3, 6}, {4, 8}})
+
+	if _, err := f.Next(6); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if _, err := f.Next(6); err == nil {
+		t.Fatal("面数が一致しないのにエラーが発生しなかった")
+	}
+
+	// エラーの場合は再生位置が進まない
+	if f.Position() != 1 {
+		t.Errorf("wrong position: got %d, want 1", f.Position())
+	}
+
+	if d, err := f.Next(8); err != nil || d != (dice.Die{4, 8}) {
+		t.Errorf("got %s, %v; want <Die 4/8>, nil", d, err)
+	}
+}
+
+func TestReplay_Rewind(t *testing.T) {
+	f := NewReplay([]dice.Die{{3, 6}, {4, 6}})
+	f.Next(6)
+	f.Next(6)
+
+	f.Rewind()
+
+	if f.Remaining() != 2 {
+		t.Fatalf("wrong remaining: got %d, want 2", f.Remaining())
+	}
+
+	if d, _ := f.Next(6); d != (dice.Die{3, 6}) {
+		t.Errorf("wrong die: got %s, want <Die 3/6>", d)
+	}
+}
@@ -0,0 +1,305 @@
+package feeder
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+)
+
+// Ruby版BCDiceと同じシードから同じ出目を生成するダイス供給機の構造体。
+//
+// Rubyの Random.new(seed) と同じ方法でメルセンヌ・ツイスタを初期化し、
+// Random#rand(n) と同じ方法で出目を決める。そのため、Ruby版BCDiceで
+// 記録したシードを使用すると、同じダイス列を再現できる。
+//
+// MarshalBinary で乱数生成器の状態を保存し、UnmarshalBinary で復元できる。
+//
+// 複数のゴルーチンから同時に使用しても安全である。
+type Ruby struct {
+	// 乱数生成器へのアクセスを保護する
+	mu sync.Mutex
+	mt rubyMT
+}
+
+// RubyがFeederインターフェースを実装しているかの確認
+var _ DieFeeder = (*Ruby)(nil)
+
+// NewRuby は、Rubyの Random.new(seed) と同じ出目を生成するダイス供給機を返す。
+func NewRuby(seed int64) *Ruby {
+	return NewRubyWithBigSeed(big.NewInt(seed))
+}
+
+// NewRubyWithBigSeed は、Rubyの Random.new(seed) と同じ出目を生成するダイス供給機を返す。
+// Random.new_seed で生成されるような、64ビットに収まらないシードを指定する場合に使う。
+func NewRubyWithBigSeed(seed *big.Int) *Ruby {
+	f := &Ruby{}
+	f.mt.initBySeed(seed)
+
+	return f
+}
+
+// NewRubyWithSeedFromTime は、現在の時刻をシードとしたRuby互換ダイス供給機を返す。
+func NewRubyWithSeedFromTime() *Ruby {
+	return NewRuby(time.Now().UnixNano())
+}
+
+// CanSpecifyDie は、供給されるダイスを指定できるかを返す。
+// Ruby互換ダイス供給機ではfalseを返す。
+func (f *Ruby) CanSpecifyDie() bool {
+	return false
+}
+
+// Next は、Rubyの Random#rand(sides) + 1 と同じ値のダイスを1つ供給する。
+//
+// sides: ダイスの面の数
+func (f *Ruby) Next(sides int) (dice.Die, error) {
+	if sides < 1 {
+		return dice.Die{}, locale.NewError(locale.MSG_ERR_TOO_FEW_SIDES, sides)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d := dice.Die{
+		Sides: sides,
+		Value: 1 + int(f.mt.limitedRand(uint64(sides-1))),
+	}
+	return d, nil
+}
+
+// Float64 は、Rubyの Random#rand（引数なし）と同じ、0以上1未満の浮動小数点数を返す。
+func (f *Ruby) Float64() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.mt.genrandRes53()
+}
+
+// MarshalBinary は乱数生成器の状態を直列化して返す。
+func (f *Ruby) MarshalBinary() ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := newSnapshotWriter(snapshotKindRuby)
+	for _, v := range f.mt.state {
+		w.putUvarint(uint64(v))
+	}
+	w.putUvarint(uint64(f.mt.index))
+
+	return w.Bytes(), nil
+}
+
+// UnmarshalBinary は、MarshalBinary で直列化された乱数生成器の状態を復元する。
+func (f *Ruby) UnmarshalBinary(data []byte) error {
+	var mt rubyMT
+
+	r := newSnapshotReader(snapshotKindRuby, data)
+	for i := range mt.state {
+		v := r.readUvarint()
+		if v > 0xffffffff {
+			r.fail()
+		}
+
+		mt.state[i] = uint32(v)
+	}
+	index := r.readUvarint()
+
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	if index > rubyMTN {
+		return fmt.Errorf("乱数生成器の状態の位置が不正です: %d", index)
+	}
+
+	mt.index = int(index)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.mt = mt
+
+	return nil
+}
+
+const (
+	// 状態ベクトルの長さ
+	rubyMTN = 624
+	// 状態ベクトルの更新で使う要素の間隔
+	rubyMTM = 397
+)
+
+// Rubyのrandom.cと同じ動作をするメルセンヌ・ツイスタ（MT19937）。
+type rubyMT struct {
+	// 状態ベクトル
+	state [rubyMTN]uint32
+	// 次に出力する状態ベクトルの位置
+	index int
+}
+
+// initBySeed は、Rubyの rand_init と同じ方法で、整数のシードから状態を初期化する。
+//
+// シードの絶対値を32ビットずつ下位から区切り、1語ならば init_genrand を、
+// 2語以上ならば init_by_array を使用する。
+// 2語以上で最上位の語が1の場合は、Rubyと同様にそれを取り除く（leading-zero-guard）。
+// 取り除いた結果1語になっても init_by_array を使用する。
+func (mt *rubyMT) initBySeed(seed *big.Int) {
+	abs := new(big.Int).Abs(seed)
+	mask := big.NewInt(0xffffffff)
+
+	key := []uint32{}
+	for abs.Sign() > 0 {
+		key = append(key, uint32(new(big.Int).And(abs, mask).Uint64()))
+		abs.Rsh(abs, 32)
+	}
+
+	if len(key) == 0 {
+		key = append(key, 0)
+	}
+
+	if len(key) <= 1 {
+		mt.initGenrand(key[0])
+		return
+	}
+
+	if key[len(key)-1] == 1 {
+		key = key[:len(key)-1]
+	}
+
+	mt.initByArray(key)
+}
+
+// initGenrand は、32ビットのシードで状態を初期化する。
+func (mt *rubyMT) initGenrand(s uint32) {
+	mt.state[0] = s
+	for i := 1; i < rubyMTN; i++ {
+		prev := mt.state[i-1]
+		mt.state[i] = 1812433253*(prev^(prev>>30)) + uint32(i)
+	}
+
+	mt.index = rubyMTN
+}
+
+// initByArray は、32ビットの整数の列で状態を初期化する。
+func (mt *rubyMT) initByArray(key []uint32) {
+	mt.initGenrand(19650218)
+
+	i := 1
+	j := 0
+
+	k := rubyMTN
+	if len(key) > k {
+		k = len(key)
+	}
+
+	for ; k > 0; k-- {
+		prev := mt.state[i-1]
+		mt.state[i] = (mt.state[i] ^ ((prev ^ (prev >> 30)) * 1664525)) + key[j] + uint32(j)
+
+		i++
+		j++
+
+		if i >= rubyMTN {
+			mt.state[0] = mt.state[rubyMTN-1]
+			i = 1
+		}
+
+		if j >= len(key) {
+			j = 0
+		}
+	}
+
+	for k = rubyMTN - 1; k > 0; k-- {
+		prev := mt.state[i-1]
+		mt.state[i] = (mt.state[i] ^ ((prev ^ (prev >> 30)) * 1566083941)) - uint32(i)
+
+		i++
+
+		if i >= rubyMTN {
+			mt.state[0] = mt.state[rubyMTN-1]
+			i = 1
+		}
+	}
+
+	// 状態ベクトルが0にならないようにする
+	mt.state[0] = 0x80000000
+}
+
+// nextState は状態ベクトルを更新する。
+func (mt *rubyMT) nextState() {
+	for k := 0; k < rubyMTN; k++ {
+		y := (mt.state[k] & 0x80000000) | (mt.state[(k+1)%rubyMTN] & 0x7fffffff)
+
+		v := mt.state[(k+rubyMTM)%rubyMTN] ^ (y >> 1)
+		if y&1 != 0 {
+			v ^= 0x9908b0df
+		}
+
+		mt.state[k] = v
+	}
+
+	mt.index = 0
+}
+
+// genrandInt32 は32ビットの乱数を返す。
+func (mt *rubyMT) genrandInt32() uint32 {
+	if mt.index >= rubyMTN {
+		mt.nextState()
+	}
+
+	y := mt.state[mt.index]
+	mt.index++
+
+	// 調律
+	y ^= y >> 11
+	y ^= (y << 7) & 0x9d2c5680
+	y ^= (y << 15) & 0xefc60000
+	y ^= y >> 18
+
+	return y
+}
+
+// genrandRes53 は、53ビットの精度を持つ0以上1未満の浮動小数点数を返す。
+func (mt *rubyMT) genrandRes53() float64 {
+	a := mt.genrandInt32() >> 5
+	b := mt.genrandInt32() >> 6
+
+	return (float64(a)*67108864.0 + float64(b)) * (1.0 / 9007199254740992.0)
+}
+
+// limitedRand は、Rubyの limited_rand と同じ方法で、0以上limit以下の整数を返す。
+//
+// limit以上の最小の「2のべき乗 - 1」をマスクとし、上位の32ビットから順に
+// 乱数でマスクを埋める。途中でlimitを超えた場合は最初からやり直す。
+// そのため、limitが32ビットに収まる場合は、乱数を1個ずつ使用する。
+func (mt *rubyMT) limitedRand(limit uint64) uint64 {
+	if limit == 0 {
+		return 0
+	}
+
+	mask := limit
+	for shift := uint(1); shift <= 32; shift <<= 1 {
+		mask |= mask >> shift
+	}
+
+retry:
+	val := uint64(0)
+	for i := 1; i >= 0; i-- {
+		shift := uint(i * 32)
+
+		if (mask>>shift)&0xffffffff != 0 {
+			val |= uint64(mt.genrandInt32()) << shift
+			val &= mask
+
+			if limit < val {
+				goto retry
+			}
+		}
+	}
+
+	return val
+}
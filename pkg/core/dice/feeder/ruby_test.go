@@ -0,0 +1,281 @@
+package feeder
+
+import (
+	"fmt"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// Ruby版BCDiceと同じシードで同じダイス列を再現する例。
+func Example_ruby() {
+	// Rubyの Random.new(42) に相当する
+	dieFeeder := NewRuby(42)
+
+	// Random#rand(6) + 1 と同じ値になる
+	ds := make([]dice.Die, 0, 5)
+	for i := 0; i < 5; i++ {
+		d, _ := dieFeeder.Next(6)
+		ds = append(ds, d)
+	}
+
+	fmt.Println(dice.FormatDice(ds))
+	// Output: 4/6, 5/6, 3/6, 5/6, 5/6
+}
+
+// rubyDice は、Ruby互換ダイス供給機から指定した面数のダイスを順に取り出し、値のスライスを返す。
+func rubyDice(t *testing.T, f *Ruby, sides ...int) []int {
+	values := make([]int, 0, len(sides))
+	for _, d := range nextDice(t, f, sides) {
+		values = append(values, d.Value)
+	}
+
+	return values
+}
+
+// repeatSides は、面数nをcount個並べたスライスを返す。
+func repeatSides(n int, count int) []int {
+	sides := make([]int, count)
+	for i := range sides {
+		sides[i] = n
+	}
+
+	return sides
+}
+
+func TestRuby_CanSpecifyDie(t *testing.T) {
+	f := NewRuby(42)
+
+	if f.CanSpecifyDie() {
+		t.Fatalf("Rubyはダイスを指定できてはならない")
+	}
+}
+
+func TestRuby_Next_KnownRubyValues(t *testing.T) {
+	testcases := []struct {
+		name     string
+		seed     int64
+		n        int
+		expected int
+	}{
+		// Random.new(42).rand(100) #=> 51
+		{"Random.new(42).rand(100)", 42, 100, 51},
+		// Random.new(1234).rand(100) #=> 47
+		{"Random.new(1234).rand(100)", 1234, 100, 47},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewRuby(test.seed)
+
+			// Next は rand(n) + 1 を返す
+			actual := rubyDice(t, f, test.n)[0] - 1
+			if actual != test.expected {
+				t.Errorf("got %d, want %d", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRuby_Next_KnownRubySequences(t *testing.T) {
+	// Ruby 2.x で実行して得た値
+	testcases := []struct {
+		name     string
+		seed     *big.Int
+		sides    []int
+		expected []int
+	}{
+		{
+			// r = Random.new(2**32 + 5); Array.new(5) { r.rand(6) + 1 } #=> [6, 2, 2, 5, 3]
+			name:     "最上位の語が1のシードは取り除かれ、init_by_array([5]) となる",
+			seed:     big.NewInt(1<<32 + 5),
+			sides:    repeatSides(6, 5),
+			expected: []int{6, 2, 2, 5, 3},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewRubyWithBigSeed(test.seed)
+
+			actual := rubyDice(t, f, test.sides...)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRubyMT_InitByArray(t *testing.T) {
+	// Ruby 2.x の init_by_array([5, 1]) の後に rand(6) + 1 を5回実行して得た値
+	var mt rubyMT
+	mt.initByArray([]uint32{5, 1})
+
+	f := &Ruby{mt: mt}
+
+	expected := []int{4, 4, 6, 3, 6}
+	if actual := rubyDice(t, f, repeatSides(6, 5)...); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestRuby_Float64(t *testing.T) {
+	// Random.new(42).rand #=> 0.3745401188473625
+	f := NewRuby(42)
+
+	expected := 0.3745401188473625
+	actual := f.Float64()
+	if actual != expected {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestRuby_Next(t *testing.T) {
+	// 意図しない変更を検出するための回帰テスト。
+	// 値はこの実装で計算したものであり、Rubyで実行して得たものではない。
+	// Rubyで得た値は TestRuby_Next_KnownRubyValues および
+	// TestRuby_Next_KnownRubySequences に記述する。
+	testcases := []struct {
+		name     string
+		seed     *big.Int
+		sides    []int
+		expected []int
+	}{
+		{
+			name:     "Random.new(42)：6面",
+			seed:     big.NewInt(42),
+			sides:    repeatSides(6, 10),
+			expected: []int{4, 5, 3, 5, 5, 2, 3, 3, 3, 5},
+		},
+		{
+			name:     "Random.new(42)：面数が混在",
+			seed:     big.NewInt(42),
+			sides:    []int{6, 10, 100, 20, 4, 2, 12, 8},
+			expected: []int{4, 8, 61, 7, 2, 1, 7, 3},
+		},
+		{
+			name:     "Random.new(0)",
+			seed:     big.NewInt(0),
+			sides:    repeatSides(6, 5),
+			expected: []int{5, 6, 1, 4, 4},
+		},
+		{
+			name:     "負のシードは絶対値と同じ",
+			seed:     big.NewInt(-42),
+			sides:    repeatSides(6, 5),
+			expected: []int{4, 5, 3, 5, 5},
+		},
+		{
+			name:     "2語のシード（init_by_array）",
+			seed:     big.NewInt(3<<32 + 7),
+			sides:    repeatSides(6, 10),
+			expected: []int{1, 3, 5, 1, 3, 6, 4, 1, 4, 2},
+		},
+		{
+			name:     "128ビットのシード",
+			seed:     mustParseBigInt(t, "1234567890abcdef1234567890abcdef"),
+			sides:    repeatSides(6, 10),
+			expected: []int{4, 2, 4, 2, 4, 3, 2, 4, 2, 4},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewRubyWithBigSeed(test.seed)
+
+			actual := rubyDice(t, f, test.sides...)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRuby_Next_AfterStateUpdate(t *testing.T) {
+	// 状態ベクトルの更新（624個ごと）をまたいでも一致する
+	f := NewRuby(42)
+
+	values := rubyDice(t, f, repeatSides(6, 705)...)
+
+	expected := []int{1, 1, 1, 6, 5, 6, 2, 4, 5, 5}
+	actual := values[695:]
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestRubyMT_LimitedRand_64Bit(t *testing.T) {
+	// 上限が32ビットに収まらない場合は、2個の乱数を組み合わせる
+	testcases := []struct {
+		name     string
+		limit    uint64
+		expected []uint64
+	}{
+		{
+			name:     "2^40",
+			limit:    1 << 40,
+			expected: []uint64{441507790259, 458615280711, 810017303572},
+		},
+		{
+			name:     "2^40 + 2^39",
+			limit:    1<<40 + 1<<39,
+			expected: []uint64{441507790259, 1495436465422, 458615280711},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			f := NewRuby(42)
+
+			actual := make([]uint64, 0, len(test.expected))
+			for range test.expected {
+				actual = append(actual, f.mt.limitedRand(test.limit))
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRuby_Next_Error(t *testing.T) {
+	f := NewRuby(42)
+
+	if _, err := f.Next(0); err == nil {
+		t.Fatal("エラーが発生しませんでした")
+	}
+}
+
+func TestRuby_MarshalBinary(t *testing.T) {
+	original := NewRuby(42)
+	rubyDice(t, original, repeatSides(6, 700)...)
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: got err: %s", err)
+	}
+
+	restored := &Ruby{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: got err: %s", err)
+	}
+
+	sides := []int{6, 10, 100, 20, 6, 6}
+	expected := rubyDice(t, original, sides...)
+	actual := rubyDice(t, restored, sides...)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+// mustParseBigInt は、16進表記の整数を解析して返す。
+func mustParseBigInt(t *testing.T, s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		t.Fatalf("%q: 整数を解析できません", s)
+	}
+
+	return n
+}
@@ -0,0 +1,257 @@
+package feeder
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+// ダイス供給機の状態の直列化形式のバージョン
+const snapshotVersion = 1
+
+// ダイス供給機の状態の種類を表す型。
+// 直列化した状態の先頭に記録し、異なる種類のダイス供給機に復元することを防ぐ。
+type snapshotKind byte
+
+const (
+	// MT19937の状態
+	snapshotKindMT19937 snapshotKind = 'M'
+	// Queueの状態
+	snapshotKindQueue snapshotKind = 'Q'
+	// Replayの状態
+	snapshotKindReplay snapshotKind = 'R'
+	// ProvablyFairの状態
+	snapshotKindProvablyFair snapshotKind = 'P'
+	// Rubyの状態
+	snapshotKindRuby snapshotKind = 'Y'
+	// Recorderの状態
+	snapshotKindRecorder snapshotKind = 'L'
+	// Hybridの状態
+	snapshotKindHybrid snapshotKind = 'H'
+)
+
+// marshalFeeder は、他のダイス供給機を包むダイス供給機のために、内側のダイス供給機の状態を直列化する。
+// 内側のダイス供給機が状態の保存に対応していない場合はエラーを返す。
+func marshalFeeder(f DieFeeder) ([]byte, error) {
+	m, ok := f.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T: ダイス供給機の状態を保存できません", f)
+	}
+
+	return m.MarshalBinary()
+}
+
+// unmarshalFeeder は、内側のダイス供給機の状態を復元する。
+// 内側のダイス供給機が状態の復元に対応していない場合はエラーを返す。
+func unmarshalFeeder(f DieFeeder, data []byte) error {
+	u, ok := f.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%T: ダイス供給機の状態を復元できません", f)
+	}
+
+	return u.UnmarshalBinary(data)
+}
+
+// ダイス供給機の状態を直列化する構造体。
+type snapshotWriter struct {
+	buf []byte
+}
+
+// newSnapshotWriter は、指定した種類の状態を直列化するsnapshotWriterを返す。
+func newSnapshotWriter(kind snapshotKind) *snapshotWriter {
+	return &snapshotWriter{
+		buf: []byte{byte(kind), snapshotVersion},
+	}
+}
+
+// Bytes は直列化された状態を返す。
+func (w *snapshotWriter) Bytes() []byte {
+	return w.buf
+}
+
+// putUvarint は符号なし整数を書き込む。
+func (w *snapshotWriter) putUvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.buf = append(w.buf, b[:n]...)
+}
+
+// putVarint は符号付き整数を書き込む。
+func (w *snapshotWriter) putVarint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v)
+	w.buf = append(w.buf, b[:n]...)
+}
+
+// putBool は真偽値を書き込む。
+func (w *snapshotWriter) putBool(v bool) {
+	if v {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+// putBytes は長さとともにバイト列を書き込む。
+func (w *snapshotWriter) putBytes(b []byte) {
+	w.putUvarint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+// putDice は長さとともにダイス列を書き込む。
+func (w *snapshotWriter) putDice(ds []dice.Die) {
+	w.putUvarint(uint64(len(ds)))
+	for _, d := range ds {
+		w.putVarint(int64(d.Value))
+		w.putVarint(int64(d.Sides))
+	}
+}
+
+// 直列化されたダイス供給機の状態を読み込む構造体。
+//
+// 読み込みに失敗した場合、以降の読み込みはすべて失敗し、Err がエラーを返す。
+type snapshotReader struct {
+	buf []byte
+	err error
+}
+
+// newSnapshotReader は、指定した種類の状態を読み込むsnapshotReaderを返す。
+// 状態の種類またはバージョンが一致しない場合は、Err がエラーを返すようになる。
+func newSnapshotReader(kind snapshotKind, data []byte) *snapshotReader {
+	r := &snapshotReader{}
+
+	if len(data) < 2 || snapshotKind(data[0]) != kind {
+		r.err = fmt.Errorf("ダイス供給機の状態の種類が一致しません")
+		return r
+	}
+
+	if data[1] != snapshotVersion {
+		r.err = fmt.Errorf("未対応のダイス供給機の状態のバージョンです: %d", data[1])
+		return r
+	}
+
+	r.buf = data[2:]
+
+	return r
+}
+
+// Err は読み込み中に発生したエラーを返す。
+// 読み込んでいない部分が残っている場合もエラーを返す。
+func (r *snapshotReader) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+
+	if len(r.buf) > 0 {
+		return fmt.Errorf("ダイス供給機の状態の末尾に余分なデータがあります")
+	}
+
+	return nil
+}
+
+// fail は読み込みを失敗させる。
+func (r *snapshotReader) fail() {
+	if r.err == nil {
+		r.err = fmt.Errorf("ダイス供給機の状態が壊れています")
+	}
+}
+
+// readUvarint は符号なし整数を読み込む。
+func (r *snapshotReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+
+	r.buf = r.buf[n:]
+
+	return v
+}
+
+// readVarint は符号付き整数を読み込む。
+func (r *snapshotReader) readVarint() int64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail()
+		return 0
+	}
+
+	r.buf = r.buf[n:]
+
+	return v
+}
+
+// readBool は真偽値を読み込む。
+func (r *snapshotReader) readBool() bool {
+	if r.err != nil {
+		return false
+	}
+
+	if len(r.buf) < 1 || r.buf[0] > 1 {
+		r.fail()
+		return false
+	}
+
+	v := r.buf[0] == 1
+	r.buf = r.buf[1:]
+
+	return v
+}
+
+// readBytes は長さとともに書き込まれたバイト列を読み込む。
+func (r *snapshotReader) readBytes() []byte {
+	n := r.readUvarint()
+	if r.err != nil {
+		return nil
+	}
+
+	if uint64(len(r.buf)) < n {
+		r.fail()
+		return nil
+	}
+
+	b := make([]byte, n)
+	copy(b, r.buf)
+	r.buf = r.buf[n:]
+
+	return b
+}
+
+// readDice は長さとともに書き込まれたダイス列を読み込む。
+func (r *snapshotReader) readDice() []dice.Die {
+	n := r.readUvarint()
+	if r.err != nil {
+		return nil
+	}
+
+	// 1個のダイスは少なくとも2バイトなので、それより長い値は壊れている
+	if uint64(len(r.buf)) < 2*n {
+		r.fail()
+		return nil
+	}
+
+	ds := make([]dice.Die, 0, n)
+	for i := uint64(0); i < n; i++ {
+		value := r.readVarint()
+		sides := r.readVarint()
+
+		ds = append(ds, dice.Die{Value: int(value), Sides: int(sides)})
+	}
+
+	if r.err != nil {
+		return nil
+	}
+
+	return ds
+}
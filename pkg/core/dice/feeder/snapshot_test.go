@@ -0,0 +1,317 @@
+package feeder
+
+import (
+	"encoding"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"reflect"
+	"testing"
+)
+
+// 状態を保存・復元できるダイス供給機
+type snapshotFeeder interface {
+	DieFeeder
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// 各ダイス供給機がsnapshotFeederインターフェースを実装しているかの確認
+var (
+	_ snapshotFeeder = (*MT19937)(nil)
+	_ snapshotFeeder = (*Ruby)(nil)
+	_ snapshotFeeder = (*Queue)(nil)
+	_ snapshotFeeder = (*Replay)(nil)
+	_ snapshotFeeder = (*ProvablyFair)(nil)
+	_ snapshotFeeder = (*Recorder)(nil)
+	_ snapshotFeeder = (*Hybrid)(nil)
+)
+
+// nextDice は、ダイス供給機から指定した面数のダイスを順に取り出す。
+func nextDice(t *testing.T, f DieFeeder, sides []int) []dice.Die {
+	ds := make([]dice.Die, 0, len(sides))
+	for i, s := range sides {
+		d, err := f.Next(s)
+		if err != nil {
+			t.Fatalf("#%d: got err: %s", i, err)
+		}
+
+		ds = append(ds, d)
+	}
+
+	return ds
+}
+
+func TestSnapshot_RestoredFeederContinuesSequence(t *testing.T) {
+	before := []int{6, 6, 10, 100, 20}
+	after := []int{6, 10, 6, 100, 4, 8, 12}
+
+	testcases := []struct {
+		name     string
+		original snapshotFeeder
+		restored snapshotFeeder
+	}{
+		{
+			name:     "MT19937",
+			original: NewMT19937(42),
+			restored: &MT19937{},
+		},
+		{
+			name:     "MT19937（別のシードで初期化済み）",
+			original: NewMT19937(1),
+			restored: NewMT19937(2),
+		},
+		{
+			name:     "Ruby",
+			original: NewRuby(42),
+			restored: &Ruby{},
+		},
+		{
+			name: "Queue",
+			original: NewQueue([]dice.Die{
+				{1, 6}, {2, 6}, {3, 10}, {4, 100}, {5, 20},
+				{6, 6}, {7, 10}, {1, 6}, {99, 100}, {2, 4}, {3, 8}, {12, 12},
+			}),
+			restored: NewEmptyQueue(),
+		},
+		{
+			name: "Replay",
+			original: NewReplay([]dice.Die{
+				{1, 6}, {2, 6}, {3, 10}, {4, 100}, {5, 20},
+				{6, 6}, {7, 10}, {1, 6}, {99, 100}, {2, 4}, {3, 8}, {12, 12},
+			}),
+			restored: NewReplay(nil),
+		},
+		{
+			name:     "ProvablyFair",
+			original: NewProvablyFair([]byte("server-seed"), "player1"),
+			restored: &ProvablyFair{},
+		},
+		{
+			name:     "Recorder",
+			original: NewRecorder(NewMT19937(42)),
+			restored: NewRecorder(&MT19937{}),
+		},
+		{
+			name: "Hybrid",
+			original: func() snapshotFeeder {
+				h := NewHybrid(NewRuby(42))
+				h.Set([]dice.Die{
+					{1, 6}, {2, 6}, WildcardDie(10), {4, 100}, {5, 20},
+					{6, 6}, WildcardDie(10), {1, 6},
+				})
+				return h
+			}(),
+			restored: NewHybrid(&Ruby{}),
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			nextDice(t, test.original, before)
+
+			data, err := test.original.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: got err: %s", err)
+			}
+
+			if err := test.restored.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: got err: %s", err)
+			}
+
+			expected := nextDice(t, test.original, after)
+			actual := nextDice(t, test.restored, after)
+
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("wrong dice: got %v, want %v", actual, expected)
+			}
+		})
+	}
+}
+
+func TestMT19937_MarshalBinary_Seed(t *testing.T) {
+	original := NewMT19937(-12345)
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	restored := &MT19937{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if restored.Seed() != original.Seed() {
+		t.Errorf("wrong seed: got %d, want %d", restored.Seed(), original.Seed())
+	}
+}
+
+func TestRecorder_UnmarshalBinary_RecordedDice(t *testing.T) {
+	original := NewRecorder(NewMT19937(1))
+	nextDice(t, original, []int{6, 10, 100})
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	restored := NewRecorder(&MT19937{})
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if restored.Log() != original.Log() {
+		t.Errorf("wrong log: got %q, want %q", restored.Log(), original.Log())
+	}
+}
+
+func TestSnapshot_MarshalBinary_UnsupportedInnerFeeder(t *testing.T) {
+	testcases := []struct {
+		name string
+		f    snapshotFeeder
+	}{
+		{"Recorder", NewRecorder(NewManual(nil))},
+		{"Hybrid", NewHybrid(NewManual(nil))},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.f.MarshalBinary(); err == nil {
+				t.Error("エラーが発生しませんでした")
+			}
+		})
+	}
+}
+
+func TestReplay_UnmarshalBinary_Position(t *testing.T) {
+	original := NewReplay([]dice.Die{{1, 6}, {2, 6}, {3, 6}})
+	nextDice(t, original, []int{6, 6})
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	restored := NewReplay(nil)
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if restored.Position() != 2 {
+		t.Errorf("wrong position: got %d, want %d", restored.Position(), 2)
+	}
+
+	// 巻き戻すと最初から再生できる
+	restored.Rewind()
+	actual := nextDice(t, restored, []int{6, 6, 6})
+	expected := []dice.Die{{1, 6}, {2, 6}, {3, 6}}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("wrong dice: got %v, want %v", actual, expected)
+	}
+}
+
+func TestProvablyFair_UnmarshalBinary_Revealed(t *testing.T) {
+	original := NewProvablyFair([]byte("server-seed"), "player1")
+	original.Reveal()
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	restored := &ProvablyFair{}
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	if !restored.IsRevealed() {
+		t.Error("公開済みの状態が復元されていない")
+	}
+
+	if restored.Commitment() != original.Commitment() {
+		t.Errorf("wrong commitment: got %s, want %s",
+			restored.Commitment(), original.Commitment())
+	}
+}
+
+func TestSnapshot_UnmarshalBinary_Error(t *testing.T) {
+	queueData, err := NewQueue([]dice.Die{{1, 6}, {2, 6}}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	mtData, err := NewMT19937(1).MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	replayData, err := NewReplay([]dice.Die{{1, 6}}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	hybridData, err := NewHybrid(NewMT19937(1)).MarshalBinary()
+	if err != nil {
+		t.Fatalf("got err: %s", err)
+	}
+
+	testcases := []struct {
+		name string
+		f    snapshotFeeder
+		data []byte
+	}{
+		{
+			name: "空",
+			f:    NewEmptyQueue(),
+			data: nil,
+		},
+		{
+			name: "種類が異なる",
+			f:    NewEmptyQueue(),
+			data: mtData,
+		},
+		{
+			name: "バージョンが異なる",
+			f:    NewEmptyQueue(),
+			data: append([]byte{queueData[0], snapshotVersion + 1}, queueData[2:]...),
+		},
+		{
+			name: "途中で切れている",
+			f:    NewEmptyQueue(),
+			data: queueData[:len(queueData)-1],
+		},
+		{
+			name: "末尾に余分なデータがある",
+			f:    &MT19937{},
+			data: append(append([]byte{}, mtData...), 0),
+		},
+		{
+			// 末尾の2バイトは状態ベクトルの位置（312）
+			name: "状態ベクトルの位置が範囲外",
+			f:    &MT19937{},
+			data: append(append([]byte{}, mtData[:len(mtData)-2]...), 0xb9, 0x02),
+		},
+		{
+			name: "内側のダイス供給機の種類が異なる",
+			f:    NewHybrid(&Ruby{}),
+			data: hybridData,
+		},
+		{
+			name: "再生位置がダイスの数を超えている",
+			f:    NewReplay(nil),
+			data: append(append([]byte{}, replayData[:len(replayData)-1]...), 2),
+		},
+		{
+			name: "真偽値が不正",
+			f:    &ProvablyFair{},
+			data: []byte{byte(snapshotKindProvablyFair), snapshotVersion, 0, 0, 0, 2},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.f.UnmarshalBinary(test.data); err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+		})
+	}
+}
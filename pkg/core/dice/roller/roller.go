@@ -5,9 +5,9 @@ DieFeederを指定できるため、ダイスの供給方法に依らずに複
 package roller
 
 import (
-	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 )
 
 // ダイスローラーを表す構造体。
@@ -35,43 +35,54 @@ func (dr *DiceRoller) DieFeeder() feeder.DieFeeder {
 // num、sidesともに正の整数でなければならない。
 // この条件が満たされていなかった場合は、エラーを返す。
 func (dr *DiceRoller) RollDice(num int, sides int) ([]dice.Die, error) {
+	rolledDice, _, err := dr.RollDiceWithNonces(num, sides)
+	return rolledDice, err
+}
+
+// RollDiceWithNonces は、RollDice と同様にダイスを振り、その結果と各ダイスの導出に使ったノンスを返す。
+//
+// ダイス供給機が feeder.NoncedDieFeeder を実装していない場合や、
+// ノンスから導出されていないダイスが含まれる場合（Hybrid で値を指定したダイスなど）、ノンスはnilとなる。
+func (dr *DiceRoller) RollDiceWithNonces(num int, sides int) ([]dice.Die, []uint64, error) {
 	if sides < 1 {
-		return nil, fmt.Errorf(
-			"RollDice(num: %d, sides: %d): ダイスの面数が少なすぎます",
-			num,
-			sides,
-		)
+		return nil, nil, locale.NewError(locale.MSG_ERR_TOO_FEW_SIDES, sides)
 	}
 
 	if num < 1 {
-		return nil, fmt.Errorf(
-			"RollDice(num: %d, sides: %d): 振るダイス数が少なすぎます",
-			num,
-			sides,
-		)
+		return nil, nil, locale.NewError(locale.MSG_ERR_TOO_FEW_DICE, num)
 	}
 
 	// 結果のスライスの領域をnum個分確保する
 	rolledDice := make([]dice.Die, 0, num)
+	nonces := make([]uint64, 0, num)
+	// すべてのダイスにノンスがあるか
+	allNonced := true
 
 	for i := 0; i < num; i++ {
-		d, err := dr.feeder.Next(sides)
+		d, nonce, ok, err := feeder.NextWithNonce(dr.feeder, sides)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		nonces = append(nonces, nonce)
+		if !ok {
+			allNonced = false
 		}
 
 		if d.Sides != sides {
-			return nil, fmt.Errorf(
-				"RollDice(num: %d, sides: %d) -> %d/%d: ダイスの面数が指定と一致しません",
-				num,
+			return nil, nil, locale.NewError(
+				locale.MSG_ERR_SIDES_MISMATCH,
 				sides,
-				d.Value,
-				d.Sides,
+				dice.FormatDice([]dice.Die{d}),
 			)
 		}
 
 		rolledDice = append(rolledDice, d)
 	}
 
-	return rolledDice, nil
+	if !allNonced {
+		return rolledDice, nil, nil
+	}
+
+	return rolledDice, nonces, nil
 }
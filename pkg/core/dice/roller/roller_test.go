@@ -1,36 +1,38 @@
 package roller
 
 import (
+	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
 	"reflect"
 	"testing"
 )
 
-func ExampleDiceRoller_RollDice_mT19937() ([]dice.Die, error) {
+func ExampleDiceRoller_RollDice_mT19937() {
 	dieFeeder := feeder.NewMT19937WithSeedFromTime()
 	dieRoller := New(dieFeeder)
 
 	// 6面ダイスを2個振る
 	rolledDice, err := dieRoller.RollDice(2, 6)
 	if err != nil {
-		return nil, err
+		return
 	}
 
-	return rolledDice, nil
+	fmt.Println(dice.FormatDice(rolledDice))
 }
 
-func ExampleDiceRoller_RollDice_queue() ([]dice.Die, error) {
+func ExampleDiceRoller_RollDice_queue() {
 	dieFeeder := feeder.NewQueue([]dice.Die{{1, 6}, {3, 6}, {5, 6}})
 	dieRoller := New(dieFeeder)
 
 	// 6面ダイスを3個振る
 	rolledDice, err := dieRoller.RollDice(3, 6)
 	if err != nil {
-		return nil, err
+		return
 	}
 
-	return rolledDice, nil
+	fmt.Println(dice.FormatDice(rolledDice))
+	// Output: 1/6, 3/6, 5/6
 }
 
 func TestDiceRoller_RollDice_Queue(t *testing.T) {
@@ -119,6 +121,62 @@ func TestDiceRoller_RollDice_Queue(t *testing.T) {
 	}
 }
 
+func TestDiceRoller_RollDiceWithNonces(t *testing.T) {
+	newProvablyFair := func() *feeder.ProvablyFair {
+		return feeder.NewProvablyFair([]byte("server-seed"), "player1")
+	}
+
+	newHybrid := func(ds []dice.Die) feeder.DieFeeder {
+		f := feeder.NewHybrid(newProvablyFair())
+		f.Append(ds)
+
+		return f
+	}
+
+	testcases := []struct {
+		name     string
+		feeder   feeder.DieFeeder
+		expected []uint64
+	}{
+		{"ProvablyFair", newProvablyFair(), []uint64{0, 1, 2}},
+		{"Recorder(ProvablyFair)", feeder.NewRecorder(newProvablyFair()), []uint64{0, 1, 2}},
+		{"Hybrid(ProvablyFair)", newHybrid(nil), []uint64{0, 1, 2}},
+		{
+			"Hybrid(ProvablyFair)のワイルドカード",
+			newHybrid([]dice.Die{feeder.WildcardDie(6), feeder.WildcardDie(6)}),
+			[]uint64{0, 1, 2},
+		},
+		{
+			"Recorder(Hybrid(ProvablyFair))",
+			feeder.NewRecorder(newHybrid(nil)),
+			[]uint64{0, 1, 2},
+		},
+		// 値を指定したダイスを含む場合は検証できない
+		{"Hybrid(ProvablyFair)の指定したダイス", newHybrid([]dice.Die{{3, 6}}), nil},
+		{"MT19937", feeder.NewMT19937(1), nil},
+		{"Recorder(MT19937)", feeder.NewRecorder(feeder.NewMT19937(1)), nil},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			dr := New(test.feeder)
+
+			rolledDice, nonces, err := dr.RollDiceWithNonces(3, 6)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+			}
+
+			if len(rolledDice) != 3 {
+				t.Errorf("wrong number of dice: got %d, want 3", len(rolledDice))
+			}
+
+			if !reflect.DeepEqual(nonces, test.expected) {
+				t.Errorf("wrong nonces: got %v, want %v", nonces, test.expected)
+			}
+		})
+	}
+}
+
 func TestDiceRoller_RollDice_MT19937(t *testing.T) {
 	nums := []int{1, 1, 2, 3, 5, 8, 13, 100, 89, 55, 34}
 
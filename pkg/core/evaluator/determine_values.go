@@ -35,6 +35,10 @@ func (e *Evaluator) determineValueOfDRoll(
 		return nil, fmt.Errorf("num is not Int: %s", node.Left().Type())
 	}
 
+	if faces, ok := node.Right().(*ast.DieFaces); ok {
+		return e.determineValueOfFacedDRoll(num.Value, faces)
+	}
+
 	sides, sidesIsInt := node.Right().(*ast.Int)
 	if !sidesIsInt {
 		return nil, fmt.Errorf("sides is not Int: %s", node.Right().Type())
@@ -51,6 +55,24 @@ func (e *Evaluator) determineValueOfDRoll(
 	return ast.NewSumRollResult(rolledDice), nil
 }
 
+// determineValueOfFacedDRoll は、面を定義したダイスの加算ロールの値を決定する。
+func (e *Evaluator) determineValueOfFacedDRoll(
+	num int,
+	faces *ast.DieFaces,
+) (*ast.SumRollResult, error) {
+	definition, err := e.resolveDieFaces(faces)
+	if err != nil {
+		return nil, err
+	}
+
+	rolledDice, rollDiceErr := e.RollFacedDice(num, definition)
+	if rollDiceErr != nil {
+		return nil, rollDiceErr
+	}
+
+	return ast.NewFacedSumRollResult(rolledDice, definition), nil
+}
+
 type nodeSetter func(ast.Node)
 
 func (e *Evaluator) replaceVariablePrimaryExpr(node ast.Node, setter nodeSetter) error {
@@ -144,6 +144,16 @@ func TestDetermineValues(t *testing.T) {
 			expected: "(DRollExpr (/R (SumRollResult (Die 54 100)) 10))",
 			dice:     []dice.Die{{54, 100}},
 		},
+		{
+			input:    "3D{avg}+1",
+			expected: "(DRollExpr (+ (SumRollResult {avg} (Die 1 6) (Die 4 6) (Die 6 6)) 1))",
+			dice:     []dice.Die{{1, 6}, {4, 6}, {6, 6}},
+		},
+		{
+			input:    "2D{-1,0,1}",
+			expected: "(DRollExpr (SumRollResult {-1,0,1} (Die 1 3) (Die 3 3)))",
+			dice:     []dice.Die{{1, 3}, {3, 3}},
+		},
 	}
 
 	for _, test := range testcases {
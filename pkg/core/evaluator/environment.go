@@ -7,6 +7,12 @@ import (
 // コマンド評価の環境を表す構造体。
 type Environment struct {
 	rolledDice []dice.Die
+	// 振られた各ダイスの定義（rolledDice と同じ順。通常のダイスはnil）
+	rolledDieDefinitions []*dice.Definition
+	// 振られた各ダイスの導出に使われたノンス（rolledDice と同じ順）
+	rolledDieNonces []uint64
+	// ノンスが得られなかったダイスがあるか
+	missingNonce bool
 }
 
 // NewEnvironment は新しいコマンド評価環境を返す。
@@ -25,9 +31,44 @@ func (e *Environment) RolledDice() []dice.Die {
 	return dice
 }
 
+// RolledDieDefinitions は、記録された各ダイスの定義を RolledDice と同じ順で返す。
+// 通常のダイスの定義はnilとなる。定義したダイスが振られていない場合はnilを返す。
+func (e *Environment) RolledDieDefinitions() []*dice.Definition {
+	faced := false
+	for _, d := range e.rolledDieDefinitions {
+		if d != nil {
+			faced = true
+			break
+		}
+	}
+
+	if !faced {
+		return nil
+	}
+
+	definitions := make([]*dice.Definition, len(e.rolledDieDefinitions))
+	copy(definitions, e.rolledDieDefinitions)
+
+	return definitions
+}
+
 // PushRolledDie は振られたダイスを記録に追加する。
 func (e *Environment) PushRolledDie(d dice.Die) {
+	e.PushRolledFacedDie(d, nil)
+}
+
+// PushRolledFacedDie は、定義したダイスの振られた結果を記録に追加する。
+// definition がnilの場合は通常のダイスとして記録する。
+func (e *Environment) PushRolledFacedDie(d dice.Die, definition *dice.Definition) {
+	e.pushRolledDie(d, definition, 0)
+	e.missingNonce = true
+}
+
+// pushRolledDie は、振られたダイスを、その定義およびノンスとともに記録に追加する。
+func (e *Environment) pushRolledDie(d dice.Die, definition *dice.Definition, nonce uint64) {
 	e.rolledDice = append(e.rolledDice, d)
+	e.rolledDieDefinitions = append(e.rolledDieDefinitions, definition)
+	e.rolledDieNonces = append(e.rolledDieNonces, nonce)
 }
 
 // AppendRolledDice は振られたダイスの列を記録に追加する。
@@ -37,7 +78,48 @@ func (e *Environment) AppendRolledDice(dice []dice.Die) {
 	}
 }
 
+// AppendRolledFacedDice は、定義したダイスの振られた結果の列を記録に追加する。
+func (e *Environment) AppendRolledFacedDice(dice []dice.Die, definition *dice.Definition) {
+	for _, d := range dice {
+		e.PushRolledFacedDie(d, definition)
+	}
+}
+
+// AppendRolledDiceWithNonces は、振られたダイスの列を、各ダイスの導出に使われたノンスとともに記録に追加する。
+// definition は通常のダイスの場合はnilとする。
+// nonces がnilの場合は、ノンスが得られなかったものとして記録する。
+func (e *Environment) AppendRolledDiceWithNonces(
+	dice []dice.Die,
+	definition *dice.Definition,
+	nonces []uint64,
+) {
+	if nonces == nil {
+		e.AppendRolledFacedDice(dice, definition)
+		return
+	}
+
+	for i, d := range dice {
+		e.pushRolledDie(d, definition, nonces[i])
+	}
+}
+
+// RolledDieNonces は、記録された各ダイスの導出に使われたノンスを RolledDice と同じ順で返す。
+// ノンスが得られなかったダイスがある場合や、ダイスが振られていない場合はnilを返す。
+func (e *Environment) RolledDieNonces() []uint64 {
+	if e.missingNonce || len(e.rolledDieNonces) == 0 {
+		return nil
+	}
+
+	nonces := make([]uint64, len(e.rolledDieNonces))
+	copy(nonces, e.rolledDieNonces)
+
+	return nonces
+}
+
 // ClearRolledDice は記録されたダイスロール結果をクリアする。
 func (e *Environment) ClearRolledDice() {
 	e.rolledDice = []dice.Die{}
+	e.rolledDieDefinitions = nil
+	e.rolledDieNonces = nil
+	e.missingNonce = false
 }
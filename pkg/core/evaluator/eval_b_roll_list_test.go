@@ -115,3 +115,83 @@ func TestEvalBRollList(t *testing.T) {
 		})
 	}
 }
+
+func TestEvalBRollList_Faced(t *testing.T) {
+	star, err := dice.NewDefinition("star", []dice.Face{
+		{Value: 0},
+		{Value: 1, Symbols: []string{"★"}},
+		{Value: 2, Symbols: []string{"★", "★"}},
+	})
+	if err != nil {
+		t.Fatalf("ダイスの定義エラー: %s", err)
+		return
+	}
+
+	registry := dice.NewRegistry()
+	if err := registry.Register(star); err != nil {
+		t.Fatalf("登録エラー: %s", err)
+		return
+	}
+
+	testcases := []struct {
+		input    string
+		expected []string
+		dice     []dice.Die
+	}{
+		{
+			input:    "3b{star}",
+			expected: []string{"1★", "0", "2★★"},
+			dice:     []dice.Die{{2, 3}, {1, 3}, {3, 3}},
+		},
+		{
+			input:    "2b{-1,0,1}",
+			expected: []string{"-1", "1"},
+			dice:     []dice.Die{{1, 3}, {3, 3}},
+		},
+		{
+			input:    "1b6+2b{star}",
+			expected: []string{"4", "0", "2★★"},
+			dice:     []dice.Die{{4, 6}, {1, 3}, {3, 3}},
+		},
+	}
+
+	for _, test := range testcases {
+		name := fmt.Sprintf("%q[%s]",
+			test.input, dice.FormatDiceWithoutSpaces(test.dice))
+		t.Run(name, func(t *testing.T) {
+			r, parseErr := parser.Parse("test", []byte(test.input))
+			if parseErr != nil {
+				t.Fatalf("構文エラー: %s", parseErr)
+				return
+			}
+
+			node := r.(ast.Node)
+
+			// ノードを評価する
+			dieFeeder := feeder.NewQueue(test.dice)
+			evaluator := NewEvaluator(roller.New(dieFeeder), NewEnvironment())
+			evaluator.DieDefinitions = registry
+
+			evaluated, evalErr := evaluator.Eval(node)
+			if evalErr != nil {
+				t.Fatalf("評価エラー: %s", evalErr)
+				return
+			}
+
+			obj, typeMatched := evaluated.(*object.Array)
+			if !typeMatched {
+				t.Fatalf("配列オブジェクトでない: %T (%+v)", evaluated, evaluated)
+				return
+			}
+
+			actual := []string{}
+			for _, e := range obj.Elements {
+				actual = append(actual, e.Inspect())
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("異なる評価結果: got=%v, want=%v", actual, test.expected)
+			}
+		})
+	}
+}
@@ -178,6 +178,26 @@ func TestEvalDRollExpr(t *testing.T) {
 			expected: 13,
 			dice:     []dice.Die{{2, 4}, {3, 3}, {5, 6}, {5, 6}, {4, 6}},
 		},
+		{
+			input:    "3D{avg}",
+			expected: 11,
+			dice:     []dice.Die{{1, 6}, {4, 6}, {6, 6}},
+		},
+		{
+			input:    "4D{fudge}+2",
+			expected: 3,
+			dice:     []dice.Die{{1, 6}, {3, 6}, {5, 6}, {6, 6}},
+		},
+		{
+			input:    "3D{1,1,2,2,3,4}*2",
+			expected: 16,
+			dice:     []dice.Die{{1, 6}, {5, 6}, {6, 6}},
+		},
+		{
+			input:    "[1...3]D{avg}",
+			expected: 8,
+			dice:     []dice.Die{{2, 3}, {2, 6}, {6, 6}},
+		},
 	}
 
 	for _, test := range testcases {
@@ -226,3 +246,183 @@ func TestEvalDRollExpr(t *testing.T) {
 		})
 	}
 }
+
+func TestEvalDRollExpr_UnknownDieDefinition(t *testing.T) {
+	r, parseErr := parser.Parse("test", []byte("2D{unknown}"))
+	if parseErr != nil {
+		t.Fatalf("構文エラー: %s", parseErr)
+		return
+	}
+
+	node := r.(ast.Node)
+
+	dieFeeder := feeder.NewQueue([]dice.Die{{1, 6}, {2, 6}})
+	evaluator := NewEvaluator(roller.New(dieFeeder), NewEnvironment())
+
+	if _, err := evaluator.Eval(node); err == nil {
+		t.Fatal("未定義のダイスを振れてしまった")
+	}
+
+	if rolledDice := evaluator.RolledDice(); len(rolledDice) > 0 {
+		t.Errorf("ダイスが振られた: %v", rolledDice)
+	}
+}
+
+func TestEvalDRollExpr_DieDefinitions(t *testing.T) {
+	r, parseErr := parser.Parse("test", []byte("2D{Coin}"))
+	if parseErr != nil {
+		t.Fatalf("構文エラー: %s", parseErr)
+		return
+	}
+
+	node := r.(ast.Node)
+
+	coin, err := dice.NewDefinitionFromValues("coin", 0, 1)
+	if err != nil {
+		t.Fatalf("ダイスの定義エラー: %s", err)
+		return
+	}
+
+	registry := dice.NewRegistry()
+	if err := registry.Register(coin); err != nil {
+		t.Fatalf("登録エラー: %s", err)
+		return
+	}
+
+	dieFeeder := feeder.NewQueue([]dice.Die{{2, 2}, {2, 2}})
+	evaluator := NewEvaluator(roller.New(dieFeeder), NewEnvironment())
+	evaluator.DieDefinitions = registry
+
+	evaluated, evalErr := evaluator.Eval(node)
+	if evalErr != nil {
+		t.Fatalf("評価エラー: %s", evalErr)
+		return
+	}
+
+	obj, typeMatched := evaluated.(*object.Integer)
+	if !typeMatched {
+		t.Fatalf("整数オブジェクトでない: %T (%+v)", evaluated, evaluated)
+		return
+	}
+
+	if obj.Value != 2 {
+		t.Errorf("異なる評価結果: got=%d, want=2", obj.Value)
+	}
+}
+
+// 同じ構文解析木を異なる登録簿の評価器で評価したとき、それぞれの登録簿の定義が使われることを確認する。
+func TestEvalDRollExpr_DieDefinitions_SharedNode(t *testing.T) {
+	r, parseErr := parser.Parse("test", []byte("2D{Coin}"))
+	if parseErr != nil {
+		t.Fatalf("構文エラー: %s", parseErr)
+		return
+	}
+
+	node := r.(ast.Node)
+
+	testcases := []struct {
+		values   []int
+		expected int
+	}{
+		{[]int{0, 1}, 2},
+		{[]int{10, 20}, 40},
+		{[]int{0, 1}, 2},
+	}
+
+	for i, test := range testcases {
+		t.Run(fmt.Sprintf("#%d %v", i+1, test.values), func(t *testing.T) {
+			coin, err := dice.NewDefinitionFromValues("coin", test.values...)
+			if err != nil {
+				t.Fatalf("ダイスの定義エラー: %s", err)
+				return
+			}
+
+			registry := dice.NewRegistry()
+			if err := registry.Register(coin); err != nil {
+				t.Fatalf("登録エラー: %s", err)
+				return
+			}
+
+			dieFeeder := feeder.NewQueue([]dice.Die{{2, 2}, {2, 2}})
+			evaluator := NewEvaluator(roller.New(dieFeeder), NewEnvironment())
+			evaluator.DieDefinitions = registry
+
+			evaluated, evalErr := evaluator.Eval(node)
+			if evalErr != nil {
+				t.Fatalf("評価エラー: %s", evalErr)
+				return
+			}
+
+			obj, typeMatched := evaluated.(*object.Integer)
+			if !typeMatched {
+				t.Fatalf("整数オブジェクトでない: %T (%+v)", evaluated, evaluated)
+				return
+			}
+
+			if obj.Value != test.expected {
+				t.Errorf("異なる評価結果: got=%d, want=%d", obj.Value, test.expected)
+			}
+
+			if definitions := evaluator.RolledDieDefinitions(); definitions[0] != coin {
+				t.Errorf("異なるダイスの定義: got=%v, want=%v", definitions[0], coin)
+			}
+		})
+	}
+}
+
+func TestEvalDRollExpr_RolledDieDefinitions(t *testing.T) {
+	avg, _ := dice.LookupDefinition("avg")
+
+	testcases := []struct {
+		input    string
+		dice     []dice.Die
+		expected []*dice.Definition
+	}{
+		{
+			input:    "2D6",
+			dice:     []dice.Die{{1, 6}, {2, 6}},
+			expected: nil,
+		},
+		{
+			input:    "3D{avg}",
+			dice:     []dice.Die{{1, 6}, {4, 6}, {6, 6}},
+			expected: []*dice.Definition{avg, avg, avg},
+		},
+		{
+			input:    "1D6+2D{avg}",
+			dice:     []dice.Die{{3, 6}, {1, 6}, {6, 6}},
+			expected: []*dice.Definition{nil, avg, avg},
+		},
+		{
+			input:    "[1...3]D{avg}",
+			dice:     []dice.Die{{2, 3}, {2, 6}, {6, 6}},
+			expected: []*dice.Definition{nil, avg, avg},
+		},
+	}
+
+	for _, test := range testcases {
+		name := fmt.Sprintf("%q[%s]",
+			test.input, dice.FormatDiceWithoutSpaces(test.dice))
+		t.Run(name, func(t *testing.T) {
+			r, parseErr := parser.Parse("test", []byte(test.input))
+			if parseErr != nil {
+				t.Fatalf("構文エラー: %s", parseErr)
+				return
+			}
+
+			dieFeeder := feeder.NewQueue(test.dice)
+			evaluator := NewEvaluator(roller.New(dieFeeder), NewEnvironment())
+
+			if _, evalErr := evaluator.Eval(r.(ast.Node)); evalErr != nil {
+				t.Fatalf("評価エラー: %s", evalErr)
+				return
+			}
+
+			actual := evaluator.RolledDieDefinitions()
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("異なるダイスの定義の記録: got=%v, want=%v",
+					actual, test.expected)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package evaluator
 import (
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
@@ -12,6 +13,11 @@ import (
 func (e *Evaluator) evalInfixExpression(
 	node ast.InfixExpression,
 ) (object.Object, error) {
+	// 面を定義したダイスのダイスロール
+	if faces, ok := node.Right().(*ast.DieFaces); ok {
+		return e.evalFacedRoll(node, faces)
+	}
+
 	left, right, err := e.evalInfixExpressionOperands(node)
 	if err != nil {
 		return nil, err
@@ -109,6 +115,7 @@ func (e *Evaluator) evalIntegerInfixExpression(
 }
 
 // evalIntegerDivide は除算を評価する。
+// 端数処理の方法が指定されていない場合は、評価器の DefaultRoundingMethod に従う。
 func (e *Evaluator) evalIntegerDivide(
 	divide *ast.Divide,
 	left *object.Integer,
@@ -121,7 +128,13 @@ func (e *Evaluator) evalIntegerDivide(
 		return nil, fmt.Errorf("%d divided by zero", leftValue)
 	}
 
-	switch divide.RoundingMethod {
+	roundingMethod := divide.RoundingMethod
+	if divide.Type() == ast.DIVIDE_WITH_ROUNDING_DOWN_NODE {
+		// 端数処理の方法が指定されていない場合は、既定の方法を使う
+		roundingMethod = e.DefaultRoundingMethod
+	}
+
+	switch roundingMethod {
 	case ast.ROUNDING_METHOD_ROUND_DOWN:
 		// 除算（小数点以下切り捨て）
 		return object.NewInteger(leftValue / rightValue), nil
@@ -164,6 +177,7 @@ func (e *Evaluator) evalSumRoll(
 }
 
 // evalBasicRoll はバラバラロールを評価する。
+// SortBRollValues が設定されている場合は、出目を昇順に並べ替える。
 // 返り値は、整数オブジェクトを要素として持つ配列オブジェクト、およびエラー。
 func (e *Evaluator) evalBasicRoll(
 	num *object.Integer,
@@ -177,14 +191,78 @@ func (e *Evaluator) evalBasicRoll(
 		return nil, err
 	}
 
-	intObjs := make([]object.Object, 0, len(rolledDice))
+	values := make([]int, 0, len(rolledDice))
 	for _, d := range rolledDice {
-		intObjs = append(intObjs, object.NewInteger(d.Value))
+		values = append(values, d.Value)
+	}
+
+	if e.SortBRollValues {
+		sort.Ints(values)
+	}
+
+	intObjs := make([]object.Object, 0, len(values))
+	for _, v := range values {
+		intObjs = append(intObjs, object.NewInteger(v))
 	}
 
 	return object.NewArrayByMove(intObjs), nil
 }
 
+// evalFacedRoll は、面を定義したダイスのダイスロールを評価する。
+//
+// 加算ロールの場合は、面の数値の合計を表す整数オブジェクトを返す。
+// バラバラロールの場合は、面オブジェクトを要素として持つ配列オブジェクトを返す。
+func (e *Evaluator) evalFacedRoll(
+	node ast.InfixExpression,
+	faces *ast.DieFaces,
+) (object.Object, error) {
+	if node.Left() == nil {
+		return nil, fmt.Errorf("operator %s: left is nil", node.Operator())
+	}
+
+	left, leftErr := e.Eval(node.Left())
+	if leftErr != nil {
+		return nil, leftErr
+	}
+
+	num, numIsInteger := left.(*object.Integer)
+	if !numIsInteger {
+		return nil, fmt.Errorf("operator not implemented: %s %s %s",
+			left.Type(), node.Operator(), faces.Type())
+	}
+
+	definition, err := e.resolveDieFaces(faces)
+	if err != nil {
+		return nil, err
+	}
+
+	rolledDice, err := e.RollFacedDice(num.Value, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node.Operator() {
+	case "D":
+		sum := ast.NewFacedSumRollResult(rolledDice, definition).Value()
+		return object.NewInteger(sum), nil
+	case "B":
+		faceObjs := make([]object.Object, 0, len(rolledDice))
+		for _, d := range rolledDice {
+			f, err := definition.Face(d)
+			if err != nil {
+				return nil, err
+			}
+
+			faceObjs = append(faceObjs, object.NewFace(f))
+		}
+
+		return object.NewArrayByMove(faceObjs), nil
+	}
+
+	return nil, fmt.Errorf("operator not implemented: %s %s %s",
+		left.Type(), node.Operator(), faces.Type())
+}
+
 // evalRandomNumber はランダム数値取り出しを評価する。
 func (e *Evaluator) evalRandomNumber(
 	min *object.Integer,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
 
@@ -85,19 +86,11 @@ func (e *Evaluator) evalCompoundingRoll(
 	thresholdVal := threshold.Value
 
 	if sidesVal < 1 {
-		return nil, fmt.Errorf(
-			"evalCompoundingRoll(num: %d, sides: %d): ダイスの面数が少なすぎます",
-			numVal,
-			sidesVal,
-		)
+		return nil, e.Locale.Errorf(locale.MSG_ERR_TOO_FEW_SIDES, sidesVal)
 	}
 
 	if numVal < 1 {
-		return nil, fmt.Errorf(
-			"evalCompoundingRoll(num: %d, sides: %d): 振るダイス数が少なすぎます",
-			numVal,
-			sidesVal,
-		)
+		return nil, e.Locale.Errorf(locale.MSG_ERR_TOO_FEW_DICE, numVal)
 	}
 
 	valueGroups := make([]object.Object, 0, numVal)
@@ -50,6 +50,17 @@ func (e *Evaluator) evalVarArgsOfRoll(node ast.InfixExpression) error {
 		return leftErr
 	}
 
+	// 面を定義したダイスの場合は、ダイスの定義を求める
+	if faces, ok := node.Right().(*ast.DieFaces); ok {
+		if _, err := e.resolveDieFaces(faces); err != nil {
+			return err
+		}
+
+		node.SetLeft(objectToIntNode(leftObj))
+
+		return nil
+	}
+
 	rightObj, rightErr := e.Eval(node.Right())
 	if rightErr != nil {
 		return rightErr
@@ -18,6 +18,7 @@ import (
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
 
@@ -28,6 +29,16 @@ type Evaluator struct {
 	// 個数振り足しロールにおける最大振り足し数
 	// TODO: 外部から変更するためのインターフェースを作る
 	MaxRerolls int
+	// {名前} で参照するダイスの定義のレジストリ
+	DieDefinitions *dice.Registry
+	// メッセージのロケール
+	Locale locale.Locale
+	// 端数処理の方法を指定しない除算（"/"）の端数処理の方法
+	DefaultRoundingMethod ast.RoundingMethodType
+	// バラバラロールの出目を昇順に並べ替えるかどうか
+	SortBRollValues bool
+	// D66の出目を昇順に並べ替える（小さい方を十の位とする）かどうか
+	SortD66 bool
 }
 
 // NewEvaluator は新しい評価器を返す。
@@ -36,9 +47,11 @@ type Evaluator struct {
 // env: 評価環境
 func NewEvaluator(diceRoller *roller.DiceRoller, env *Environment) *Evaluator {
 	return &Evaluator{
-		diceRoller: diceRoller,
-		env:        env,
-		MaxRerolls: 10000,
+		diceRoller:     diceRoller,
+		env:            env,
+		MaxRerolls:     10000,
+		DieDefinitions: dice.DefaultRegistry,
+		Locale:         locale.DEFAULT,
 	}
 }
 
@@ -47,6 +60,36 @@ func (e *Evaluator) RolledDice() []dice.Die {
 	return e.env.RolledDice()
 }
 
+// RolledDieDefinitions は、振られた各ダイスの定義を RolledDice と同じ順で返す。
+// 通常のダイスの定義はnilとなる。定義したダイスが振られていない場合はnilを返す。
+func (e *Evaluator) RolledDieDefinitions() []*dice.Definition {
+	return e.env.RolledDieDefinitions()
+}
+
+// RolledDieNonces は、振られた各ダイスの導出に使われたノンスを RolledDice と同じ順で返す。
+// ダイス供給機がノンスを返さない場合はnilを返す。
+func (e *Evaluator) RolledDieNonces() []uint64 {
+	return e.env.RolledDieNonces()
+}
+
+// resolveDieFaces は、ダイスの面の指定からダイスの定義を求める。
+// 名前で指定されたダイスの定義が見つからなかった場合はエラーを返す。
+//
+// 構文解析木は複数の評価器で共有される可能性があるため、求めた定義をノードに保存しない。
+// 名前で指定された定義は、評価のたびに評価器の DieDefinitions から引く。
+func (e *Evaluator) resolveDieFaces(node *ast.DieFaces) (*dice.Definition, error) {
+	if node.Values != nil {
+		return dice.NewDefinitionFromValues("", node.Values...)
+	}
+
+	d, found := e.DieDefinitions.Lookup(node.Name)
+	if !found {
+		return nil, fmt.Errorf("unknown die definition: %s", node.Name)
+	}
+
+	return d, nil
+}
+
 // Eval はnodeを評価してObjectに変換し、返す。
 func (e *Evaluator) Eval(node ast.Node) (object.Object, error) {
 	// 型で分岐する
@@ -91,12 +134,25 @@ func (e *Evaluator) evalCommand(node *ast.Command) (object.Object, error) {
 // RollDice は、sides個の面を持つダイスをnum個振り、その結果を返す。
 // また、ダイスロールの結果を記録する。
 func (e *Evaluator) RollDice(num int, sides int) ([]dice.Die, error) {
-	rolledDice, err := e.diceRoller.RollDice(num, sides)
+	rolledDice, nonces, err := e.diceRoller.RollDiceWithNonces(num, sides)
+	if err != nil {
+		return nil, e.Locale.Localize(err)
+	}
+
+	e.env.AppendRolledDiceWithNonces(rolledDice, nil, nonces)
+
+	return rolledDice, nil
+}
+
+// RollFacedDice は、定義したダイスをnum個振り、その結果を返す。
+// また、ダイスの定義とともにダイスロールの結果を記録する。
+func (e *Evaluator) RollFacedDice(num int, definition *dice.Definition) ([]dice.Die, error) {
+	rolledDice, nonces, err := e.diceRoller.RollDiceWithNonces(num, definition.Sides())
 	if err != nil {
-		return nil, err
+		return nil, e.Locale.Localize(err)
 	}
 
-	e.env.AppendRolledDice(rolledDice)
+	e.env.AppendRolledDiceWithNonces(rolledDice, definition, nonces)
 
 	return rolledDice, nil
 }
@@ -105,5 +161,10 @@ func (e *Evaluator) RollDice(num int, sides int) ([]dice.Die, error) {
 //
 // oを*object.Integerに変換できない場合はpanicに陥るので注意。
 func objectToIntNode(o object.Object) *ast.Int {
+	// ダイスの面の場合は、面の数値を使う
+	if f, ok := o.(*object.Face); ok {
+		return ast.NewInt(f.Face.Value)
+	}
+
 	return ast.NewInt(o.(*object.Integer).Value)
 }
@@ -1,8 +1,8 @@
 package evaluator
 
 import (
-	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/core/object"
 )
 
@@ -29,7 +29,7 @@ func (e *Evaluator) SetRerollThreshold(node *ast.Command) error {
 // CheckRRollThreshold は個数振り足しロールの振り足しの閾値をチェックする。
 func (e *Evaluator) CheckRRollThreshold(node *ast.RRollList) error {
 	if node.Threshold.IsNil() {
-		return fmt.Errorf("2R6>=5 あるいは 2R6[5] のように振り足し目標値を指定してください")
+		return e.Locale.Errorf(locale.MSG_ERR_R_ROLL_THRESHOLD_REQUIRED)
 	}
 
 	return e.checkRollThreshold(node.Threshold)
@@ -38,7 +38,7 @@ func (e *Evaluator) CheckRRollThreshold(node *ast.RRollList) error {
 // CheckURollThreshold は上方無限ロールの振り足しの閾値をチェックする。
 func (e *Evaluator) CheckURollThreshold(node *ast.RRollList) error {
 	if node.Threshold.IsNil() {
-		return fmt.Errorf("2U6[5] のように振り足し目標値を指定してください")
+		return e.Locale.Errorf(locale.MSG_ERR_U_ROLL_THRESHOLD_REQUIRED)
 	}
 
 	return e.checkRollThreshold(node.Threshold)
@@ -48,14 +48,14 @@ func (e *Evaluator) CheckURollThreshold(node *ast.RRollList) error {
 func (e *Evaluator) checkRollThreshold(thresholdNode ast.Node) error {
 	thresholdObj, evalErr := e.Eval(thresholdNode)
 	if evalErr != nil {
-		return fmt.Errorf("閾値評価エラー: %s", evalErr)
+		return e.Locale.Errorf(locale.MSG_ERR_THRESHOLD_EVAL, evalErr)
 	}
 
 	thresholdInt := thresholdObj.(*object.Integer)
 	threshold := thresholdInt.Value
 
 	if threshold < 2 {
-		return fmt.Errorf("振り足し目標値として2以上の整数を指定してください")
+		return e.Locale.Errorf(locale.MSG_ERR_THRESHOLD_TOO_SMALL)
 	}
 
 	return nil
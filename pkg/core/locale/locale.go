@@ -0,0 +1,146 @@
+/*
+利用者向けのメッセージを地域化するためのパッケージ。
+
+メッセージはメッセージ識別子で参照し、ロケールに対応するカタログから取り出す。
+既定のロケールは日本語（ja）で、従来の出力と同じ文字列を返す。
+カタログにメッセージが見つからない場合は、既定のロケールのメッセージを使う。
+*/
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ロケールを表す型。
+type Locale string
+
+const (
+	// 日本語
+	JA Locale = "ja"
+	// 英語
+	EN Locale = "en"
+
+	// 既定のロケール
+	DEFAULT = JA
+)
+
+// メッセージ識別子の型。
+type MessageID string
+
+// ロケールとメッセージカタログとの対応
+var catalogs = map[Locale]map[MessageID]string{
+	JA: catalogJA,
+	EN: catalogEN,
+}
+
+// メッセージ識別子と引数で表されるエラー。
+//
+// ロケールを持たないパッケージ（ダイスローラーやダイス供給機など）が返すエラーに使う。
+// Error は既定のロケールのメッセージを返す。
+// 利用者に示す際は、Locale.Localize でロケールに合わせたメッセージに変換する。
+type Error struct {
+	// メッセージ識別子
+	ID MessageID
+	// メッセージの引数
+	Args []interface{}
+}
+
+// NewError は、メッセージ識別子と引数で表される新しいエラーを返す。
+func NewError(id MessageID, a ...interface{}) *Error {
+	return &Error{
+		ID:   id,
+		Args: a,
+	}
+}
+
+// Error は既定のロケールのメッセージを返す。
+func (e *Error) Error() string {
+	return DEFAULT.Sprintf(e.ID, e.Args...)
+}
+
+// Parse は、ロケール名を解析してロケールを返す。
+//
+// 大文字小文字は区別しない。"en-US" や "ja_JP" のように地域が付加されている場合は、
+// 言語の部分のみを使う。対応していないロケールの場合はエラーを返す。
+func Parse(name string) (Locale, error) {
+	lang := strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+
+	l := Locale(lang)
+	if _, found := catalogs[l]; !found {
+		return "", fmt.Errorf("unknown locale: %s", name)
+	}
+
+	return l, nil
+}
+
+// Available は、利用可能なロケールのスライスを返す。
+// ロケールは名前の順に並べられる。
+func Available() []Locale {
+	locales := make([]Locale, 0, len(catalogs))
+	for l := range catalogs {
+		locales = append(locales, l)
+	}
+
+	sort.Slice(locales, func(i, j int) bool {
+		return locales[i] < locales[j]
+	})
+
+	return locales
+}
+
+// String はロケールの名前を返す。
+// 空のロケールの場合は既定のロケールの名前を返す。
+func (l Locale) String() string {
+	return string(l.orDefault())
+}
+
+// Text は、メッセージ識別子に対応するメッセージを返す。
+//
+// ロケールのカタログにメッセージが存在しない場合は、既定のロケールのメッセージを返す。
+// 既定のロケールにも存在しない場合は、メッセージ識別子をそのまま返す。
+func (l Locale) Text(id MessageID) string {
+	if s, found := catalogs[l.orDefault()][id]; found {
+		return s
+	}
+
+	if s, found := catalogs[DEFAULT][id]; found {
+		return s
+	}
+
+	return string(id)
+}
+
+// Sprintf は、メッセージ識別子に対応するメッセージを書式として整形した文字列を返す。
+func (l Locale) Sprintf(id MessageID, a ...interface{}) string {
+	return fmt.Sprintf(l.Text(id), a...)
+}
+
+// Errorf は、メッセージ識別子に対応するメッセージを書式として整形したエラーを返す。
+func (l Locale) Errorf(id MessageID, a ...interface{}) error {
+	return fmt.Errorf(l.Text(id), a...)
+}
+
+// Localize は、err がメッセージ識別子で表されるエラー（*Error）の場合、
+// そのロケールのメッセージのエラーを返す。それ以外の場合は err をそのまま返す。
+func (l Locale) Localize(err error) error {
+	e, ok := err.(*Error)
+	if !ok {
+		return err
+	}
+
+	return l.Errorf(e.ID, e.Args...)
+}
+
+// orDefault は、空のロケールの場合に既定のロケールを返す。
+func (l Locale) orDefault() Locale {
+	if l == "" {
+		return DEFAULT
+	}
+
+	return l
+}
@@ -0,0 +1,140 @@
+package locale
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testcases := []struct {
+		name     string
+		expected Locale
+	}{
+		{"ja", JA},
+		{"JA", JA},
+		{"ja-JP", JA},
+		{"en", EN},
+		{"en_US", EN},
+		{" En-gb ", EN},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Parse(test.name)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+			}
+
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParse_Unknown(t *testing.T) {
+	for _, name := range []string{"", "fr", "jp"} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(name); err == nil {
+				t.Fatal("未知のロケールを解析できてしまった")
+			}
+		})
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	expected := []Locale{EN, JA}
+	actual := Available()
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestLocale_Text(t *testing.T) {
+	testcases := []struct {
+		locale   Locale
+		id       MessageID
+		expected string
+	}{
+		{JA, MSG_SUCCESS, "成功"},
+		{EN, MSG_SUCCESS, "Success"},
+		// 空のロケールは既定のロケールとして扱う
+		{"", MSG_FAILURE, "失敗"},
+		// 未知のロケールは既定のロケールのメッセージを使う
+		{"fr", MSG_FAILURE, "失敗"},
+		// 未知のメッセージ識別子はそのまま返す
+		{EN, "unknown", "unknown"},
+	}
+
+	for _, test := range testcases {
+		t.Run(string(test.locale)+"/"+string(test.id), func(t *testing.T) {
+			actual := test.locale.Text(test.id)
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestLocale_Sprintf(t *testing.T) {
+	if actual := JA.Sprintf(MSG_NUM_OF_SUCCESSES, 3); actual != "成功数3" {
+		t.Errorf("got %q, want %q", actual, "成功数3")
+	}
+
+	if actual := EN.Sprintf(MSG_MAX_AND_SUM, 6, 10); actual != "6/10 (max/sum)" {
+		t.Errorf("got %q, want %q", actual, "6/10 (max/sum)")
+	}
+}
+
+// すべてのカタログが同じメッセージを持っていることを確認する。
+func TestCatalogs_Complete(t *testing.T) {
+	for l, catalog := range catalogs {
+		for id := range catalogs[DEFAULT] {
+			if _, found := catalog[id]; !found {
+				t.Errorf("%s: メッセージがない: %s", l, id)
+			}
+		}
+
+		for id := range catalog {
+			if _, found := catalogs[DEFAULT][id]; !found {
+				t.Errorf("%s: 既定のロケールにないメッセージ: %s", l, id)
+			}
+		}
+	}
+}
+
+func TestLocale_Localize(t *testing.T) {
+	testcases := []struct {
+		locale   Locale
+		err      error
+		expected string
+	}{
+		{JA, NewError(MSG_ERR_TOO_FEW_SIDES, 0), "ダイスの面数が少なすぎます: 0"},
+		{EN, NewError(MSG_ERR_TOO_FEW_SIDES, 0), "Too few sides on a die: 0"},
+		{EN, NewError(MSG_ERR_SIDES_MISMATCH, 10, "3/6"),
+			"The die does not have the requested number of sides: 3/6 for a 10-sided die"},
+		// メッセージ識別子で表されないエラーはそのまま返す
+		{EN, fmt.Errorf("その他のエラー"), "その他のエラー"},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("%s/%s", test.locale, test.err), func(t *testing.T) {
+			actual := test.locale.Localize(test.err).Error()
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+// Error は既定のロケールのメッセージを返す。
+func TestError_Error(t *testing.T) {
+	err := NewError(MSG_ERR_SIDES_MISMATCH, 10, "3/6")
+
+	expected := "ダイスの面数が指定と一致しません: 10面に対して 3/6"
+	if actual := err.Error(); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
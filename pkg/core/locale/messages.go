@@ -0,0 +1,155 @@
+package locale
+
+// コマンドの実行結果のメッセージ
+const (
+	// 成功判定：成功
+	MSG_SUCCESS MessageID = "success"
+	// 成功判定：失敗
+	MSG_FAILURE MessageID = "failure"
+	// クリティカル
+	MSG_CRITICAL MessageID = "critical"
+	// ファンブル
+	MSG_FUMBLE MessageID = "fumble"
+	// スペシャル
+	MSG_SPECIAL MessageID = "special"
+	// 成功数（引数：成功数）
+	MSG_NUM_OF_SUCCESSES MessageID = "numOfSuccesses"
+	// 上方無限ロールの最大値と合計（引数：最大値、合計）
+	MSG_MAX_AND_SUM MessageID = "maxAndSum"
+	// 計算結果
+	MSG_CALC_RESULT MessageID = "calcResult"
+)
+
+// エラーメッセージ
+const (
+	// 個数振り足しロールの振り足し目標値が指定されていない
+	MSG_ERR_R_ROLL_THRESHOLD_REQUIRED MessageID = "errRRollThresholdRequired"
+	// 上方無限ロールの振り足し目標値が指定されていない
+	MSG_ERR_U_ROLL_THRESHOLD_REQUIRED MessageID = "errURollThresholdRequired"
+	// 振り足し目標値の評価エラー（引数：エラー）
+	MSG_ERR_THRESHOLD_EVAL MessageID = "errThresholdEval"
+	// 振り足し目標値が小さすぎる
+	MSG_ERR_THRESHOLD_TOO_SMALL MessageID = "errThresholdTooSmall"
+	// ダイスの面数が少なすぎる（引数：面数）
+	MSG_ERR_TOO_FEW_SIDES MessageID = "errTooFewSides"
+	// 振るダイス数が少なすぎる（引数：ダイス数）
+	MSG_ERR_TOO_FEW_DICE MessageID = "errTooFewDice"
+)
+
+// ダイスの供給に関するエラーメッセージ
+const (
+	// 供給されたダイスの面数が要求と一致しない（引数：要求された面数、ダイス）
+	MSG_ERR_SIDES_MISMATCH MessageID = "errSidesMismatch"
+	// 供給できるダイスが残っていない
+	MSG_ERR_NO_DICE_LEFT MessageID = "errNoDiceLeft"
+	// 乱数源からの読み込みに失敗した（引数：エラー）
+	MSG_ERR_RANDOM_SOURCE MessageID = "errRandomSource"
+	// サーバシードが公開済み
+	MSG_ERR_SERVER_SEED_REVEALED MessageID = "errServerSeedRevealed"
+	// 出目が入力されていない
+	MSG_ERR_NO_DIE_INPUT MessageID = "errNoDieInput"
+	// 入力された出目が整数でない（引数：入力）
+	MSG_ERR_DIE_NOT_INTEGER MessageID = "errDieNotInteger"
+	// 入力された出目が範囲外（引数：出目、面数）
+	MSG_ERR_DIE_OUT_OF_RANGE MessageID = "errDieOutOfRange"
+)
+
+// 基本的なダイスボットのメッセージ
+const (
+	// ゲームシステム名
+	MSG_BASIC_GAME_NAME MessageID = "basicGameName"
+	// 使用法
+	MSG_BASIC_USAGE MessageID = "basicUsage"
+)
+
+// 日本語のメッセージカタログ
+var catalogJA = map[MessageID]string{
+	MSG_SUCCESS:          "成功",
+	MSG_FAILURE:          "失敗",
+	MSG_CRITICAL:         "クリティカル",
+	MSG_FUMBLE:           "ファンブル",
+	MSG_SPECIAL:          "スペシャル",
+	MSG_NUM_OF_SUCCESSES: "成功数%d",
+	MSG_MAX_AND_SUM:      "%d/%d (最大/合計)",
+	MSG_CALC_RESULT:      "計算結果",
+
+	MSG_ERR_R_ROLL_THRESHOLD_REQUIRED: "2R6>=5 あるいは 2R6[5] のように振り足し目標値を指定してください",
+	MSG_ERR_U_ROLL_THRESHOLD_REQUIRED: "2U6[5] のように振り足し目標値を指定してください",
+	MSG_ERR_THRESHOLD_EVAL:            "閾値評価エラー: %s",
+	MSG_ERR_THRESHOLD_TOO_SMALL:       "振り足し目標値として2以上の整数を指定してください",
+	MSG_ERR_TOO_FEW_SIDES:             "ダイスの面数が少なすぎます: %d",
+	MSG_ERR_TOO_FEW_DICE:              "振るダイス数が少なすぎます: %d",
+
+	MSG_ERR_SIDES_MISMATCH:       "ダイスの面数が指定と一致しません: %[1]d面に対して %[2]s",
+	MSG_ERR_NO_DICE_LEFT:         "取り出せるダイスがありません",
+	MSG_ERR_RANDOM_SOURCE:        "乱数源からの読み込みに失敗しました: %s",
+	MSG_ERR_SERVER_SEED_REVEALED: "サーバシードが公開済みです",
+	MSG_ERR_NO_DIE_INPUT:         "出目が入力されていません",
+	MSG_ERR_DIE_NOT_INTEGER:      "%q: 出目は整数で入力してください",
+	MSG_ERR_DIE_OUT_OF_RANGE:     "%d: 出目は1から%dまでの範囲で入力してください",
+
+	MSG_BASIC_GAME_NAME: "ダイスボット (指定無し)",
+	MSG_BASIC_USAGE: `【ダイスボット】チャットにダイス用の文字を入力するとダイスロールが可能
+入力例）２ｄ６＋１　攻撃！
+出力例）2d6+1　攻撃！
+　　　　  diceBot: (2d6) → 7
+上記のようにダイス文字の後ろに空白を入れて発言する事も可能。
+以下、使用例
+　3D6+1>=9 ：3d6+1で目標値9以上かの判定
+　1D100<=50 ：D100で50％目標の下方ロールの例
+　3U6[5] ：3d6のダイス目が5以上の場合に振り足しして合計する(上方無限)
+　3B6 ：3d6のダイス目をバラバラのまま出力する（合計しない）
+　10B6>=4 ：10d6を振り4以上のダイス目の個数を数える
+　(8/2)D(4+6)<=(5*3)：個数・ダイス・達成値には四則演算も使用可能
+　C(10-4*3/2+2)：C(計算式）で計算だけの実行も可能
+　choice[a,b,c]：列挙した要素から一つを選択表示。ランダム攻撃対象決定などに
+　S3d6 ： 各コマンドの先頭に「S」を付けると他人結果の見えないシークレットロール
+　3d6/2 ： ダイス出目を割り算（切り捨て）。切り上げは /2U、四捨五入は /2R。
+　D66 ： D66ダイス。順序はゲームに依存。D66N：そのまま、D66S：昇順。`,
+}
+
+// 英語のメッセージカタログ
+var catalogEN = map[MessageID]string{
+	MSG_SUCCESS:          "Success",
+	MSG_FAILURE:          "Failure",
+	MSG_CRITICAL:         "Critical",
+	MSG_FUMBLE:           "Fumble",
+	MSG_SPECIAL:          "Special",
+	MSG_NUM_OF_SUCCESSES: "Successes: %d",
+	MSG_MAX_AND_SUM:      "%d/%d (max/sum)",
+	MSG_CALC_RESULT:      "Result",
+
+	MSG_ERR_R_ROLL_THRESHOLD_REQUIRED: "Specify a reroll threshold, e.g. 2R6>=5 or 2R6[5]",
+	MSG_ERR_U_ROLL_THRESHOLD_REQUIRED: "Specify a reroll threshold, e.g. 2U6[5]",
+	MSG_ERR_THRESHOLD_EVAL:            "Threshold evaluation error: %s",
+	MSG_ERR_THRESHOLD_TOO_SMALL:       "The reroll threshold must be an integer of 2 or more",
+	MSG_ERR_TOO_FEW_SIDES:             "Too few sides on a die: %d",
+	MSG_ERR_TOO_FEW_DICE:              "Too few dice to roll: %d",
+
+	MSG_ERR_SIDES_MISMATCH:       "The die does not have the requested number of sides: %[2]s for a %[1]d-sided die",
+	MSG_ERR_NO_DICE_LEFT:         "No dice left to feed",
+	MSG_ERR_RANDOM_SOURCE:        "Failed to read from the random source: %s",
+	MSG_ERR_SERVER_SEED_REVEALED: "The server seed has already been revealed",
+	MSG_ERR_NO_DIE_INPUT:         "No die value was entered",
+	MSG_ERR_DIE_NOT_INTEGER:      "%q: enter the die value as an integer",
+	MSG_ERR_DIE_OUT_OF_RANGE:     "%d: enter a die value from 1 to %d",
+
+	MSG_BASIC_GAME_NAME: "DiceBot (no game system)",
+	MSG_BASIC_USAGE: `[DiceBot] Type dice notation in the chat to roll dice.
+Input example: 2d6+1 Attack!
+Output example: 2d6+1 Attack!
+    diceBot: (2d6) -> 7
+You may put a comment after the dice notation, separated by a space.
+Examples:
+  3D6+1>=9 : roll 3d6+1 and check whether it is 9 or more
+  1D100<=50 : roll D100 against a target of 50% or less
+  3U6[5] : roll 3d6, rolling again and adding for each die of 5 or more (exploding dice)
+  3B6 : roll 3d6 and show each die without summing
+  10B6>=4 : roll 10d6 and count the dice showing 4 or more
+  (8/2)D(4+6)<=(5*3) : arithmetic can be used for the number of dice, sides and target
+  C(10-4*3/2+2) : C(expression) only calculates
+  choice[a,b,c] : choose one of the listed items, e.g. to pick a random target
+  S3d6 : prefix any command with "S" for a secret roll hidden from others
+  3d6/2 : divide the result (rounded down). Use /2U to round up and /2R to round off.
+  D66 : D66 dice. The order depends on the game. D66N: as rolled, D66S: ascending.`,
+}
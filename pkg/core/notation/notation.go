@@ -10,12 +10,103 @@ package notation
 import (
 	"bytes"
 	"fmt"
-	"strings"
 
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/util"
 )
 
+// 中置表記の中で、1個のダイスの出目が書かれている範囲を表す構造体。
+// 定義したダイスの場合は、出目に対応する面が書かれている範囲を表す。
+type DieSpan struct {
+	// 開始位置（バイト単位）
+	Start int
+	// 終了位置（バイト単位）。この位置の文字は範囲に含まない。
+	End int
+	// 振られたダイス
+	Die dice.Die
+	// ダイスの定義。通常のダイスの場合はnil。
+	Definition *dice.Definition
+}
+
+// 中置表記と、その中のダイスの出目の範囲をまとめた構造体。
+type text struct {
+	// 中置表記
+	s string
+	// ダイスの出目の範囲（s の中の位置順）
+	dice []DieSpan
+}
+
+// plainText は、ダイスの出目を含まない中置表記を返す。
+func plainText(s string) text {
+	return text{s: s}
+}
+
+// ダイスの出目の範囲を保ちながら中置表記を組み立てる構造体。
+type textBuilder struct {
+	// 組み立て中の中置表記
+	buf bytes.Buffer
+	// ダイスの出目の範囲
+	dice []DieSpan
+}
+
+// WriteString は、ダイスの出目を含まない文字列を追加する。
+func (b *textBuilder) WriteString(s string) {
+	b.buf.WriteString(s)
+}
+
+// WriteText は、中置表記を追加する。
+// 中置表記の中のダイスの出目の範囲は、追加する位置に合わせてずらされる。
+func (b *textBuilder) WriteText(t text) {
+	offset := b.buf.Len()
+
+	for _, span := range t.dice {
+		span.Start += offset
+		span.End += offset
+		b.dice = append(b.dice, span)
+	}
+
+	b.buf.WriteString(t.s)
+}
+
+// WriteDie は、1個のダイスの出目を追加する。
+func (b *textBuilder) WriteDie(s string, d dice.Die, definition *dice.Definition) {
+	start := b.buf.Len()
+	b.buf.WriteString(s)
+
+	b.dice = append(b.dice, DieSpan{
+		Start:      start,
+		End:        b.buf.Len(),
+		Die:        d,
+		Definition: definition,
+	})
+}
+
+// Text は、組み立てた中置表記を返す。
+func (b *textBuilder) Text() text {
+	return text{s: b.buf.String(), dice: b.dice}
+}
+
+// joinTexts は、中置表記を区切り文字列で連結する。
+func joinTexts(ts []text, sep string) text {
+	var b textBuilder
+
+	for i, t := range ts {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+
+		b.WriteText(t)
+	}
+
+	return b.Text()
+}
+
+// concatTexts は、中置表記を順に連結する。
+func concatTexts(ts ...text) text {
+	return joinTexts(ts, "")
+}
+
 // InfixNotation は構文解析木の中置表記を返す。
 //
 // node: 構文解析木のルートノード,
@@ -29,6 +120,30 @@ import (
 // ルートノードに対してこの関数を呼び出すときはwalkingToLeftをtrueに
 // 設定し、右側の中置表記を求める際にはfalseを設定する。
 func InfixNotation(node ast.Node, walkingToLeft bool) (string, error) {
+	t, err := infixText(node, walkingToLeft)
+	if err != nil {
+		return "", err
+	}
+
+	return t.s, nil
+}
+
+// InfixNotationWithDice は、構文解析木の中置表記と、その中のダイスの出目の範囲を返す。
+//
+// 出目の範囲は、加算ロール結果のノードの各ダイスについて、中置表記の中の位置順に並べられる。
+// 引数は InfixNotation と同じ。
+func InfixNotationWithDice(node ast.Node, walkingToLeft bool) (string, []DieSpan, error) {
+	t, err := infixText(node, walkingToLeft)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return t.s, t.dice, nil
+}
+
+// infixText は構文解析木の中置表記を返す。
+// 引数は InfixNotation と同じ。
+func infixText(node ast.Node, walkingToLeft bool) (text, error) {
 	switch n := node.(type) {
 	case *ast.BRollList:
 		return infixNotationOfBRollList(n)
@@ -54,16 +169,18 @@ func InfixNotation(node ast.Node, walkingToLeft bool) (string, error) {
 			return infixNotationOfInfixExpression(n, walkingToLeft)
 		}
 	case *ast.Int:
-		return fmt.Sprintf("%d", n.Value), nil
+		return plainText(fmt.Sprintf("%d", n.Value)), nil
+	case *ast.DieFaces:
+		return plainText(n.Notation()), nil
 	case *ast.SumRollResult:
 		return infixNotationOfSumRollResult(n)
 	}
 
-	return "", fmt.Errorf("infix notation not implemented: %s", node.Type())
+	return text{}, fmt.Errorf("infix notation not implemented: %s", node.Type())
 }
 
 // infixNotationOfCommand はコマンドの中置表記を返す。
-func infixNotationOfCommand(node *ast.Command, walkingToLeft bool) (string, error) {
+func infixNotationOfCommand(node *ast.Command, walkingToLeft bool) (text, error) {
 	switch node.Type() {
 	case ast.CALC_NODE:
 		return infixNotationOfCalc(node)
@@ -73,77 +190,77 @@ func infixNotationOfCommand(node *ast.Command, walkingToLeft bool) (string, erro
 }
 
 // infixNotationOfNormalCommand は通常のコマンドの中置表記を返す。
-func infixNotationOfNormalCommand(node *ast.Command, walkingToLeft bool) (string, error) {
-	expr, err := InfixNotation(node.Expression, walkingToLeft)
+func infixNotationOfNormalCommand(node *ast.Command, walkingToLeft bool) (text, error) {
+	expr, err := infixText(node.Expression, walkingToLeft)
 	if err != nil {
-		return "", err
+		return text{}, err
 	}
 
 	return expr, nil
 }
 
 // infixNotationOfCalc は計算ノードの中置表記を返す。
-func infixNotationOfCalc(node *ast.Command) (string, error) {
-	expr, err := InfixNotation(node.Expression, true)
+func infixNotationOfCalc(node *ast.Command) (text, error) {
+	expr, err := infixText(node.Expression, true)
 	if err != nil {
-		return "", err
+		return text{}, err
 	}
 
-	return fmt.Sprintf("C(%s)", expr), nil
+	return concatTexts(plainText("C("), expr, plainText(")")), nil
 }
 
 // infixNotationOfBRollList はバラバラロール列の中置表記を返す。
-func infixNotationOfBRollList(node *ast.BRollList) (string, error) {
-	infixNotations := make([]string, 0, len(node.BRolls))
+func infixNotationOfBRollList(node *ast.BRollList) (text, error) {
+	infixNotations := make([]text, 0, len(node.BRolls))
 	for _, b := range node.BRolls {
-		n, err := InfixNotation(b, true)
+		n, err := infixText(b, true)
 		if err != nil {
-			return "", err
+			return text{}, err
 		}
 
 		infixNotations = append(infixNotations, n)
 	}
 
-	return strings.Join(infixNotations, "+"), nil
+	return joinTexts(infixNotations, "+"), nil
 }
 
 // infixNotationOfRRollList は個数振り足しロール列の中置表記を返す。
-func infixNotationOfRRollList(node *ast.RRollList) (string, error) {
-	var out bytes.Buffer
+func infixNotationOfRRollList(node *ast.RRollList) (text, error) {
+	var out textBuilder
 
-	infixNotations := make([]string, 0, len(node.RRolls))
+	infixNotations := make([]text, 0, len(node.RRolls))
 	for _, r := range node.RRolls {
-		n, err := InfixNotation(r, true)
+		n, err := infixText(r, true)
 		if err != nil {
-			return "", err
+			return text{}, err
 		}
 
 		infixNotations = append(infixNotations, n)
 	}
 
-	out.WriteString(strings.Join(infixNotations, "+"))
+	out.WriteText(joinTexts(infixNotations, "+"))
 
 	if !node.Threshold.IsNil() {
-		infixNotationOfThreshold, err := InfixNotation(node.Threshold, true)
+		infixNotationOfThreshold, err := infixText(node.Threshold, true)
 		if err != nil {
-			return "", err
+			return text{}, err
 		}
 
 		out.WriteString("[")
-		out.WriteString(infixNotationOfThreshold)
+		out.WriteText(infixNotationOfThreshold)
 		out.WriteString("]")
 	}
 
-	return out.String(), nil
+	return out.Text(), nil
 }
 
 // infixNotationOfURollExpr は上方無限ロール式の中置表記を返す。
-func infixNotationOfURollExpr(node *ast.URollExpr) (string, error) {
+func infixNotationOfURollExpr(node *ast.URollExpr) (text, error) {
 	if node.Bonus == nil {
 		uRollListNotation, uRollListNotationErr :=
-			InfixNotation(node.URollList, true)
+			infixText(node.URollList, true)
 		if uRollListNotationErr != nil {
-			return "", uRollListNotationErr
+			return text{}, uRollListNotationErr
 		}
 
 		return uRollListNotation, nil
@@ -158,66 +275,73 @@ func infixNotationOfURollExpr(node *ast.URollExpr) (string, error) {
 	copiedBonus.SetLeft(node.URollList)
 
 	bonusInfixNotation, bonusInfixNotationErr :=
-		InfixNotation(&copiedBonus, true)
+		infixText(&copiedBonus, true)
 	if bonusInfixNotationErr != nil {
-		return "", bonusInfixNotationErr
+		return text{}, bonusInfixNotationErr
 	}
 
 	return bonusInfixNotation, nil
 }
 
-func infixNotationOfChoice(node *ast.Choice) (string, error) {
-	itemValues := make([]string, 0, len(node.Items))
+func infixNotationOfChoice(node *ast.Choice) (text, error) {
+	itemValues := make([]text, 0, len(node.Items))
 
 	for _, item := range node.Items {
-		itemValues = append(itemValues, item.Value)
+		itemValues = append(itemValues, plainText(item.Value))
 	}
 
-	var out bytes.Buffer
+	var out textBuilder
 
 	out.WriteString("CHOICE[")
-	out.WriteString(strings.Join(itemValues, ","))
+	out.WriteText(joinTexts(itemValues, ","))
 	out.WriteString("]")
 
-	return out.String(), nil
+	return out.Text(), nil
 }
 
 // infixNotationOfCompare は比較式の中置表記を返す。
-func infixNotationOfCompare(node ast.InfixExpression) (string, error) {
-	leftInfixNotation, leftErr := InfixNotation(node.Left(), true)
+func infixNotationOfCompare(node ast.InfixExpression) (text, error) {
+	leftInfixNotation, leftErr := infixText(node.Left(), true)
 	if leftErr != nil {
-		return "", leftErr
+		return text{}, leftErr
 	}
 
-	rightInfixNotation, rightErr := InfixNotation(node.Right(), true)
+	rightInfixNotation, rightErr := infixText(node.Right(), true)
 	if rightErr != nil {
-		return "", rightErr
+		return text{}, rightErr
 	}
 
-	return leftInfixNotation + node.Operator() + rightInfixNotation, nil
+	return concatTexts(
+		leftInfixNotation,
+		plainText(node.Operator()),
+		rightInfixNotation,
+	), nil
 }
 
 // infixNotationOfPrefixExpression は前置式の中置表記を返す。
-func infixNotationOfPrefixExpression(node ast.PrefixExpression, walkingToLeft bool) (string, error) {
+func infixNotationOfPrefixExpression(node ast.PrefixExpression, walkingToLeft bool) (text, error) {
 	right := node.Right()
 
 	if right.IsPrimaryExpression() {
 		// 一次式の場合は括弧で囲まない
-		rightInfixNotation, err := InfixNotation(right, walkingToLeft)
+		rightInfixNotation, err := infixText(right, walkingToLeft)
 		if err != nil {
-			return "", err
+			return text{}, err
 		}
 
-		return node.Operator() + rightInfixNotation, nil
+		return concatTexts(plainText(node.Operator()), rightInfixNotation), nil
 	}
 
 	// 一次式でない場合は括弧で囲む
-	rightInfixNotation, err := InfixNotation(right, true)
+	rightInfixNotation, err := infixText(right, true)
 	if err != nil {
-		return "", err
+		return text{}, err
 	}
 
-	return node.Operator() + Parenthesize(rightInfixNotation), nil
+	return concatTexts(
+		plainText(node.Operator()),
+		parenthesizeText(rightInfixNotation),
+	), nil
 }
 
 // parenthesizeChildOfInfixExpression は中置式の子ノードの中置表記を返す。
@@ -231,16 +355,16 @@ func parenthesizeChildOfInfixExpression(
 	child ast.Node,
 	parentIsAssociative bool,
 	walkingToLeft bool,
-) (string, error) {
+) (text, error) {
 	switch c := child.(type) {
 	case ast.PrefixExpression:
 		{
-			infixNotationOfChild, err := InfixNotation(child, walkingToLeft)
+			infixNotationOfChild, err := infixText(child, walkingToLeft)
 			if err != nil {
-				return "", err
+				return text{}, err
 			}
 
-			return Parenthesize(infixNotationOfChild), nil
+			return parenthesizeText(infixNotationOfChild), nil
 		}
 	case ast.InfixExpression:
 		{
@@ -248,25 +372,25 @@ func parenthesizeChildOfInfixExpression(
 			samePrecedenceAndNonAssociative :=
 				c.Precedence() == parent.Precedence() && !parentIsAssociative
 			if lowPrecedence || samePrecedenceAndNonAssociative {
-				infixNotationOfChild, err := InfixNotation(child, true)
+				infixNotationOfChild, err := infixText(child, true)
 				if err != nil {
-					return "", err
+					return text{}, err
 				}
-				return Parenthesize(infixNotationOfChild), nil
+				return parenthesizeText(infixNotationOfChild), nil
 			}
 
-			infixNotationOfChild, err := InfixNotation(child, walkingToLeft)
+			infixNotationOfChild, err := infixText(child, walkingToLeft)
 			if err != nil {
-				return "", err
+				return text{}, err
 			}
 
 			return infixNotationOfChild, nil
 		}
 	default:
 		{
-			infixNotationOfChild, err := InfixNotation(child, walkingToLeft)
+			infixNotationOfChild, err := infixText(child, walkingToLeft)
 			if err != nil {
-				return "", err
+				return text{}, err
 			}
 
 			return infixNotationOfChild, nil
@@ -279,37 +403,42 @@ func Parenthesize(s string) string {
 	return "(" + s + ")"
 }
 
+// parenthesizeText は中置表記を括弧で囲む。
+func parenthesizeText(t text) text {
+	return concatTexts(plainText("("), t, plainText(")"))
+}
+
 // infixNotationOfInfixExpression は中置式の中置表記を返す。
-func infixNotationOfInfixExpression(node ast.InfixExpression, walkingToLeft bool) (string, error) {
+func infixNotationOfInfixExpression(node ast.InfixExpression, walkingToLeft bool) (text, error) {
 	left, right, err := infixNotationsOfInfixExpressionChildren(node, walkingToLeft)
 	if err != nil {
-		return "", err
+		return text{}, err
 	}
 
-	return left + node.Operator() + right, nil
+	return concatTexts(left, plainText(node.Operator()), right), nil
 }
 
 // infixNotationOfDivide は除算の中置表記を返す。
 // 除算では端数処理の方法を除数の後で示す必要があるため、処理が特別になる。
-func infixNotationOfDivide(node *ast.Divide, walkingToLeft bool) (string, error) {
+func infixNotationOfDivide(node *ast.Divide, walkingToLeft bool) (text, error) {
 	left, right, err := infixNotationsOfInfixExpressionChildren(node, walkingToLeft)
 	if err != nil {
-		return "", err
+		return text{}, err
 	}
 
-	var buf bytes.Buffer
+	var buf textBuilder
 
-	buf.WriteString(left)
+	buf.WriteText(left)
 	buf.WriteString("/")
-	buf.WriteString(right)
+	buf.WriteText(right)
 	buf.WriteString(node.RoundingMethod.String())
 
-	return buf.String(), nil
+	return buf.Text(), nil
 }
 
 // infixNotationsOfInfixExpressionChildren は中置式の左右の子ノードの中置表記を返す。
-func infixNotationsOfInfixExpressionChildren(node ast.InfixExpression, walkingToLeft bool) (string, string, error) {
-	var leftInfixNotation string
+func infixNotationsOfInfixExpressionChildren(node ast.InfixExpression, walkingToLeft bool) (text, text, error) {
+	var leftInfixNotation text
 	var leftErr error
 
 	// 演算子が左結合性の場合、左端の単項マイナスは特別扱い
@@ -328,7 +457,7 @@ func infixNotationsOfInfixExpressionChildren(node ast.InfixExpression, walkingTo
 		)
 	}
 	if leftErr != nil {
-		return "", "", leftErr
+		return text{}, text{}, leftErr
 	}
 
 	rightInfixNotation, rightErr := parenthesizeChildOfInfixExpression(
@@ -338,37 +467,46 @@ func infixNotationsOfInfixExpressionChildren(node ast.InfixExpression, walkingTo
 		false,
 	)
 	if rightErr != nil {
-		return "", "", rightErr
+		return text{}, text{}, rightErr
 	}
 
 	return leftInfixNotation, rightInfixNotation, nil
 }
 
 // infixNotationOfRandomNumber はランダム数値取り出しの中置表記を返す。
-func infixNotationOfRandomNumber(node ast.InfixExpression) (string, error) {
-	var out bytes.Buffer
+func infixNotationOfRandomNumber(node ast.InfixExpression) (text, error) {
+	var out textBuilder
 
 	n, err := infixNotationOfInfixExpression(node, true)
 	if err != nil {
-		return "", err
+		return text{}, err
 	}
 
 	out.WriteString("[")
-	out.WriteString(n)
+	out.WriteText(n)
 	out.WriteString("]")
 
-	return out.String(), nil
+	return out.Text(), nil
 }
 
 // infixNotationOfSumRollResult は加算ロール結果の中置表記を返す。
-func infixNotationOfSumRollResult(node *ast.SumRollResult) (string, error) {
-	dieValueStrs := []string{}
+// 定義したダイスの場合は、出目の代わりに面を並べる。
+func infixNotationOfSumRollResult(node *ast.SumRollResult) (text, error) {
+	var out textBuilder
+
+	out.WriteString(fmt.Sprintf("%d[", node.Value()))
+
+	for i, f := range node.Faces() {
+		if i > 0 {
+			out.WriteString(",")
+		}
 
-	for _, d := range node.Dice {
-		dieValueStrs = append(dieValueStrs, fmt.Sprintf("%d", d.Value))
+		out.WriteDie(f.String(), node.Dice[i], node.Definition)
 	}
 
-	return fmt.Sprintf("%d[%s]", node.Value(), strings.Join(dieValueStrs, ",")), nil
+	out.WriteString("]")
+
+	return out.Text(), nil
 }
 
 // FormatModifier は修正値を符号に応じて整形する
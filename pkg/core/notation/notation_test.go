@@ -2,9 +2,11 @@ package notation
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"github.com/raa0121/GoBCDice/pkg/core/parser"
 )
 
@@ -436,6 +438,9 @@ func TestInfixNotation(t *testing.T) {
 		{"1+2D6", "1+2D6"},
 		{"-2D6+1", "-2D6+1"},
 		{"+2D6+1", "2D6+1"},
+		{"3d{avg}", "3D{avg}"},
+		{"3D{-1,0,1}+1", "3D{-1,0,1}+1"},
+		{"(1+2)D{fudge}", "(1+2)D{fudge}"},
 		{"2d6+1-1-2-3-4", "2D6+1-1-2-3-4"},
 		{"2D6+4D10", "2D6+4D10"},
 		{"(2D6)", "2D6"},
@@ -500,6 +505,8 @@ func TestInfixNotation(t *testing.T) {
 		{"2b[4...6]", "2B[4...6]"},
 		{"[1...3]b[4...6]", "[1...3]B[4...6]"},
 		{"(1*2)b6", "(1*2)B6"},
+		{"2b{avg}", "2B{avg}"},
+		{"2b6+3b{1,2,3}", "2B6+3B{1,2,3}"},
 		{"([1...3]+1)b6", "([1...3]+1)B6"},
 		{"2b(2+4)", "2B(2+4)"},
 		{"2b([3...5]+1)", "2B([3...5]+1)"},
@@ -592,3 +599,85 @@ func TestInfixNotation(t *testing.T) {
 		})
 	}
 }
+
+// TestInfixNotationWithDice は中置表記の中のダイスの出目の範囲をテストする
+func TestInfixNotationWithDice(t *testing.T) {
+	definition, definitionErr := dice.NewDefinition("t", []dice.Face{
+		{Value: 0, Symbols: []string{"空"}},
+		{Value: 1},
+		{Value: 2, Symbols: []string{"★"}},
+	})
+	if definitionErr != nil {
+		t.Fatalf("ダイスの定義エラー: %s", definitionErr)
+		return
+	}
+
+	testcases := []struct {
+		name     string
+		node     ast.Node
+		expected string
+		dice     []DieSpan
+	}{
+		{
+			name:     "加算ロール結果",
+			node:     ast.NewSumRollResult([]dice.Die{{6, 6}, {1, 6}}),
+			expected: "7[6,1]",
+			dice: []DieSpan{
+				{Start: 2, End: 3, Die: dice.Die{6, 6}},
+				{Start: 4, End: 5, Die: dice.Die{1, 6}},
+			},
+		},
+		{
+			name: "括弧と演算子",
+			node: ast.NewDRollExpr(ast.NewAdd(
+				ast.NewMultiply(
+					ast.NewUnaryMinus(ast.NewSumRollResult([]dice.Die{{10, 10}})),
+					ast.NewAdd(ast.NewInt(1), ast.NewInt(2)),
+				),
+				ast.NewSumRollResult([]dice.Die{{3, 4}, {4, 4}}),
+			)),
+			expected: "-10[10]*(1+2)+7[3,4]",
+			dice: []DieSpan{
+				{Start: 4, End: 6, Die: dice.Die{10, 10}},
+				{Start: 16, End: 17, Die: dice.Die{3, 4}},
+				{Start: 18, End: 19, Die: dice.Die{4, 4}},
+			},
+		},
+		{
+			name: "定義したダイス",
+			node: ast.NewFacedSumRollResult(
+				[]dice.Die{{1, 3}, {3, 3}},
+				definition,
+			),
+			expected: "2[空,2★]",
+			dice: []DieSpan{
+				{Start: 2, End: 5, Die: dice.Die{1, 3}, Definition: definition},
+				{Start: 6, End: 10, Die: dice.Die{3, 3}, Definition: definition},
+			},
+		},
+		{
+			name:     "ダイスを含まない",
+			node:     ast.NewCalc(ast.NewAdd(ast.NewInt(1), ast.NewInt(2))),
+			expected: "C(1+2)",
+			dice:     nil,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			actual, actualDice, err := InfixNotationWithDice(test.node, true)
+			if err != nil {
+				t.Fatalf("中置表記生成エラー: %s", err)
+				return
+			}
+
+			if actual != test.expected {
+				t.Fatalf("中置表記: got %q, want %q", actual, test.expected)
+			}
+
+			if !reflect.DeepEqual(actualDice, test.dice) {
+				t.Errorf("出目の範囲: got %+v, want %+v", actualDice, test.dice)
+			}
+		})
+	}
+}
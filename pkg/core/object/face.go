@@ -0,0 +1,33 @@
+package object
+
+import (
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+// ダイスの面オブジェクトの構造体。
+// 面を定義したダイスのバラバラロールの結果の要素となる。
+type Face struct {
+	// ダイスの面
+	Face dice.Face
+}
+
+// NewFace は新しいダイスの面オブジェクトを返す。
+func NewFace(f dice.Face) *Face {
+	return &Face{Face: f}
+}
+
+// Type はオブジェクトの種類を返す。
+func (f *Face) Type() ObjectType {
+	return FACE_OBJ
+}
+
+// Inspect はオブジェクトの内容を文字列として返す。
+// 面の記号があれば記号を含む。
+func (f *Face) Inspect() string {
+	return f.Face.String()
+}
+
+// Integer は、面の数値を整数オブジェクトとして返す。
+func (f *Face) Integer() *Integer {
+	return NewInteger(f.Face.Value)
+}
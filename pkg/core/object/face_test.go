@@ -0,0 +1,36 @@
+package object
+
+import (
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"testing"
+)
+
+func TestFace_Inspect(t *testing.T) {
+	testcases := []struct {
+		obj      *Face
+		expected string
+	}{
+		{NewFace(dice.Face{Value: 3}), "3"},
+		{NewFace(dice.Face{Value: -1}), "-1"},
+		{NewFace(dice.Face{Symbols: []string{"★"}}), "★"},
+		{NewFace(dice.Face{Value: 2, Symbols: []string{"★"}}), "2★"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.expected, func(t *testing.T) {
+			actual := test.obj.Inspect()
+			if actual != test.expected {
+				t.Fatalf("got=%q, want=%q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestFace_Integer(t *testing.T) {
+	f := NewFace(dice.Face{Value: 2, Symbols: []string{"★"}})
+
+	actual := f.Integer().Value
+	if actual != 2 {
+		t.Fatalf("got=%d, want=%d", actual, 2)
+	}
+}
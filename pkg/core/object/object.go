@@ -26,6 +26,7 @@ const (
 	R_ROLL_COMP_RESULT_OBJ
 	U_ROLL_EXPR_RESULT_OBJ
 	U_ROLL_COMP_RESULT_OBJ
+	FACE_OBJ
 )
 
 // オブジェクトの種類とそれを表す文字列との対応
@@ -40,6 +41,7 @@ var objectTypeString = map[ObjectType]string{
 	R_ROLL_COMP_RESULT_OBJ: "R_ROLL_COMP_RESULT",
 	U_ROLL_EXPR_RESULT_OBJ: "U_ROLL_EXPR_RESULT",
 	U_ROLL_COMP_RESULT_OBJ: "U_ROLL_COMP_RESULT",
+	FACE_OBJ:               "FACE",
 }
 
 // Object はオブジェクトが持つインターフェース。
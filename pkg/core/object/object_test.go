@@ -17,6 +17,7 @@ func TestObject_Type(t *testing.T) {
 		{&RRollCompResult{}, "R_ROLL_COMP_RESULT"},
 		{&URollExprResult{}, "U_ROLL_EXPR_RESULT"},
 		{&URollCompResult{}, "U_ROLL_COMP_RESULT"},
+		{&Face{}, "FACE"},
 	}
 
 	for _, test := range testcases {
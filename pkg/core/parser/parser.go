@@ -1542,13 +1542,22 @@ var g = &grammar{
 						&labeledExpr{
 							pos:   position{line: 307, col: 31, offset: 7317},
 							label: "sides",
-							expr: &ruleRefExpr{
-								pos:  position{line: 307, col: 37, offset: 7323},
-								name: "RollOperand",
+							expr: &choiceExpr{
+								pos: position{line: 307, col: 38, offset: 7324},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 307, col: 38, offset: 7324},
+										name: "DieFaces",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 307, col: 49, offset: 7335},
+										name: "RollOperand",
+									},
+								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 307, col: 49, offset: 7335},
+							pos:  position{line: 307, col: 62, offset: 7348},
 							name: "IncRandCount",
 						},
 					},
@@ -1557,36 +1566,45 @@ var g = &grammar{
 		},
 		{
 			name: "BRoll",
-			pos:  position{line: 314, col: 1, offset: 7458},
+			pos:  position{line: 314, col: 1, offset: 7471},
 			expr: &actionExpr{
-				pos: position{line: 314, col: 10, offset: 7467},
+				pos: position{line: 314, col: 10, offset: 7480},
 				run: (*parser).callonBRoll1,
 				expr: &seqExpr{
-					pos: position{line: 314, col: 10, offset: 7467},
+					pos: position{line: 314, col: 10, offset: 7480},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 314, col: 10, offset: 7467},
+							pos:   position{line: 314, col: 10, offset: 7480},
 							label: "num",
 							expr: &ruleRefExpr{
-								pos:  position{line: 314, col: 14, offset: 7471},
+								pos:  position{line: 314, col: 14, offset: 7484},
 								name: "RollOperand",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 314, col: 26, offset: 7483},
+							pos:        position{line: 314, col: 26, offset: 7496},
 							val:        "b",
 							ignoreCase: true,
 						},
 						&labeledExpr{
-							pos:   position{line: 314, col: 31, offset: 7488},
+							pos:   position{line: 314, col: 31, offset: 7501},
 							label: "sides",
-							expr: &ruleRefExpr{
-								pos:  position{line: 314, col: 37, offset: 7494},
-								name: "RollOperand",
+							expr: &choiceExpr{
+								pos: position{line: 314, col: 38, offset: 7508},
+								alternatives: []interface{}{
+									&ruleRefExpr{
+										pos:  position{line: 314, col: 38, offset: 7508},
+										name: "DieFaces",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 314, col: 49, offset: 7519},
+										name: "RollOperand",
+									},
+								},
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 314, col: 49, offset: 7506},
+							pos:  position{line: 314, col: 62, offset: 7532},
 							name: "IncRandCount",
 						},
 					},
@@ -1595,36 +1613,36 @@ var g = &grammar{
 		},
 		{
 			name: "RRoll",
-			pos:  position{line: 321, col: 1, offset: 7629},
+			pos:  position{line: 321, col: 1, offset: 7655},
 			expr: &actionExpr{
-				pos: position{line: 321, col: 10, offset: 7638},
+				pos: position{line: 321, col: 10, offset: 7664},
 				run: (*parser).callonRRoll1,
 				expr: &seqExpr{
-					pos: position{line: 321, col: 10, offset: 7638},
+					pos: position{line: 321, col: 10, offset: 7664},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 321, col: 10, offset: 7638},
+							pos:   position{line: 321, col: 10, offset: 7664},
 							label: "num",
 							expr: &ruleRefExpr{
-								pos:  position{line: 321, col: 14, offset: 7642},
+								pos:  position{line: 321, col: 14, offset: 7668},
 								name: "RollOperand",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 321, col: 26, offset: 7654},
+							pos:        position{line: 321, col: 26, offset: 7680},
 							val:        "r",
 							ignoreCase: true,
 						},
 						&labeledExpr{
-							pos:   position{line: 321, col: 31, offset: 7659},
+							pos:   position{line: 321, col: 31, offset: 7685},
 							label: "sides",
 							expr: &ruleRefExpr{
-								pos:  position{line: 321, col: 37, offset: 7665},
+								pos:  position{line: 321, col: 37, offset: 7691},
 								name: "RollOperand",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 321, col: 49, offset: 7677},
+							pos:  position{line: 321, col: 49, offset: 7703},
 							name: "IncRandCount",
 						},
 					},
@@ -1633,36 +1651,36 @@ var g = &grammar{
 		},
 		{
 			name: "URoll",
-			pos:  position{line: 328, col: 1, offset: 7800},
+			pos:  position{line: 328, col: 1, offset: 7826},
 			expr: &actionExpr{
-				pos: position{line: 328, col: 10, offset: 7809},
+				pos: position{line: 328, col: 10, offset: 7835},
 				run: (*parser).callonURoll1,
 				expr: &seqExpr{
-					pos: position{line: 328, col: 10, offset: 7809},
+					pos: position{line: 328, col: 10, offset: 7835},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 328, col: 10, offset: 7809},
+							pos:   position{line: 328, col: 10, offset: 7835},
 							label: "num",
 							expr: &ruleRefExpr{
-								pos:  position{line: 328, col: 14, offset: 7813},
+								pos:  position{line: 328, col: 14, offset: 7839},
 								name: "RollOperand",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 328, col: 26, offset: 7825},
+							pos:        position{line: 328, col: 26, offset: 7851},
 							val:        "u",
 							ignoreCase: true,
 						},
 						&labeledExpr{
-							pos:   position{line: 328, col: 31, offset: 7830},
+							pos:   position{line: 328, col: 31, offset: 7856},
 							label: "sides",
 							expr: &ruleRefExpr{
-								pos:  position{line: 328, col: 37, offset: 7836},
+								pos:  position{line: 328, col: 37, offset: 7862},
 								name: "RollOperand",
 							},
 						},
 						&ruleRefExpr{
-							pos:  position{line: 328, col: 49, offset: 7848},
+							pos:  position{line: 328, col: 49, offset: 7874},
 							name: "IncRandCount",
 						},
 					},
@@ -1671,67 +1689,230 @@ var g = &grammar{
 		},
 		{
 			name: "RollOperand",
-			pos:  position{line: 335, col: 1, offset: 7971},
+			pos:  position{line: 335, col: 1, offset: 7997},
 			expr: &choiceExpr{
-				pos: position{line: 335, col: 16, offset: 7986},
+				pos: position{line: 335, col: 16, offset: 8012},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 335, col: 16, offset: 7986},
+						pos:  position{line: 335, col: 16, offset: 8012},
 						name: "Integer",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 335, col: 26, offset: 7996},
+						pos:  position{line: 335, col: 26, offset: 8022},
 						name: "RandomNumber",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 335, col: 41, offset: 8011},
+						pos:  position{line: 335, col: 41, offset: 8037},
 						name: "ParenthesizedIntRandExpr",
 					},
 				},
 			},
 		},
+		{
+			name: "DieFaces",
+			pos:  position{line: 337, col: 1, offset: 8063},
+			expr: &choiceExpr{
+				pos: position{line: 337, col: 13, offset: 8075},
+				alternatives: []interface{}{
+					&ruleRefExpr{
+						pos:  position{line: 337, col: 13, offset: 8075},
+						name: "NamedDieFaces",
+					},
+					&ruleRefExpr{
+						pos:  position{line: 337, col: 29, offset: 8091},
+						name: "ListedDieFaces",
+					},
+				},
+			},
+		},
+		{
+			name: "NamedDieFaces",
+			pos:  position{line: 339, col: 1, offset: 8107},
+			expr: &actionExpr{
+				pos: position{line: 339, col: 18, offset: 8124},
+				run: (*parser).callonNamedDieFaces1,
+				expr: &seqExpr{
+					pos: position{line: 339, col: 18, offset: 8124},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 339, col: 18, offset: 8124},
+							val:        "{",
+							ignoreCase: false,
+						},
+						&labeledExpr{
+							pos:   position{line: 339, col: 22, offset: 8128},
+							label: "name",
+							expr: &ruleRefExpr{
+								pos:  position{line: 339, col: 27, offset: 8133},
+								name: "DieDefinitionName",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 339, col: 45, offset: 8151},
+							val:        "}",
+							ignoreCase: false,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "DieDefinitionName",
+			pos:  position{line: 343, col: 1, offset: 8209},
+			expr: &actionExpr{
+				pos: position{line: 343, col: 22, offset: 8230},
+				run: (*parser).callonDieDefinitionName1,
+				expr: &seqExpr{
+					pos: position{line: 343, col: 22, offset: 8230},
+					exprs: []interface{}{
+						&charClassMatcher{
+							pos:        position{line: 343, col: 22, offset: 8230},
+							val:        "[A-Za-z_]",
+							chars:      []rune{'_'},
+							ranges:     []rune{'A', 'Z', 'a', 'z'},
+							ignoreCase: false,
+							inverted:   false,
+						},
+						&zeroOrMoreExpr{
+							pos: position{line: 343, col: 31, offset: 8239},
+							expr: &charClassMatcher{
+								pos:        position{line: 343, col: 31, offset: 8239},
+								val:        "[A-Za-z0-9_]",
+								chars:      []rune{'_'},
+								ranges:     []rune{'A', 'Z', 'a', 'z', '0', '9'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "ListedDieFaces",
+			pos:  position{line: 347, col: 1, offset: 8286},
+			expr: &actionExpr{
+				pos: position{line: 347, col: 19, offset: 8304},
+				run: (*parser).callonListedDieFaces1,
+				expr: &seqExpr{
+					pos: position{line: 347, col: 19, offset: 8304},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 347, col: 19, offset: 8304},
+							val:        "{",
+							ignoreCase: false,
+						},
+						&labeledExpr{
+							pos:   position{line: 347, col: 23, offset: 8308},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 347, col: 29, offset: 8314},
+								name: "FaceValue",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 347, col: 39, offset: 8324},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 347, col: 44, offset: 8329},
+								expr: &seqExpr{
+									pos: position{line: 347, col: 45, offset: 8330},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 347, col: 45, offset: 8330},
+											val:        ",",
+											ignoreCase: false,
+										},
+										&ruleRefExpr{
+											pos:  position{line: 347, col: 49, offset: 8334},
+											name: "FaceValue",
+										},
+									},
+								},
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 347, col: 61, offset: 8346},
+							val:        "}",
+							ignoreCase: false,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "FaceValue",
+			pos:  position{line: 358, col: 1, offset: 8536},
+			expr: &actionExpr{
+				pos: position{line: 358, col: 14, offset: 8549},
+				run: (*parser).callonFaceValue1,
+				expr: &seqExpr{
+					pos: position{line: 358, col: 14, offset: 8549},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 358, col: 14, offset: 8549},
+							expr: &litMatcher{
+								pos:        position{line: 358, col: 14, offset: 8549},
+								val:        "-",
+								ignoreCase: false,
+							},
+						},
+						&oneOrMoreExpr{
+							pos: position{line: 358, col: 19, offset: 8554},
+							expr: &charClassMatcher{
+								pos:        position{line: 358, col: 19, offset: 8554},
+								val:        "[0-9]",
+								ranges:     []rune{'0', '9'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "RandomNumber",
-			pos:  position{line: 337, col: 1, offset: 8037},
+			pos:  position{line: 365, col: 1, offset: 8702},
 			expr: &actionExpr{
-				pos: position{line: 337, col: 17, offset: 8053},
+				pos: position{line: 365, col: 17, offset: 8718},
 				run: (*parser).callonRandomNumber1,
 				expr: &seqExpr{
-					pos: position{line: 337, col: 17, offset: 8053},
+					pos: position{line: 365, col: 17, offset: 8718},
 					exprs: []interface{}{
 						&litMatcher{
-							pos:        position{line: 337, col: 17, offset: 8053},
+							pos:        position{line: 365, col: 17, offset: 8718},
 							val:        "[",
 							ignoreCase: false,
 						},
 						&labeledExpr{
-							pos:   position{line: 337, col: 21, offset: 8057},
+							pos:   position{line: 365, col: 21, offset: 8722},
 							label: "min",
 							expr: &ruleRefExpr{
-								pos:  position{line: 337, col: 25, offset: 8061},
+								pos:  position{line: 365, col: 25, offset: 8726},
 								name: "RandomNumberOperand",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 337, col: 45, offset: 8081},
+							pos:        position{line: 365, col: 45, offset: 8746},
 							val:        "...",
 							ignoreCase: false,
 						},
 						&labeledExpr{
-							pos:   position{line: 337, col: 51, offset: 8087},
+							pos:   position{line: 365, col: 51, offset: 8752},
 							label: "max",
 							expr: &ruleRefExpr{
-								pos:  position{line: 337, col: 55, offset: 8091},
+								pos:  position{line: 365, col: 55, offset: 8756},
 								name: "RandomNumberOperand",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 337, col: 75, offset: 8111},
+							pos:        position{line: 365, col: 75, offset: 8776},
 							val:        "]",
 							ignoreCase: false,
 						},
 						&ruleRefExpr{
-							pos:  position{line: 337, col: 79, offset: 8115},
+							pos:  position{line: 365, col: 79, offset: 8780},
 							name: "IncRandCount",
 						},
 					},
@@ -1740,16 +1921,16 @@ var g = &grammar{
 		},
 		{
 			name: "RandomNumberOperand",
-			pos:  position{line: 344, col: 1, offset: 8239},
+			pos:  position{line: 372, col: 1, offset: 8904},
 			expr: &choiceExpr{
-				pos: position{line: 344, col: 24, offset: 8262},
+				pos: position{line: 372, col: 24, offset: 8927},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 344, col: 24, offset: 8262},
+						pos:  position{line: 372, col: 24, offset: 8927},
 						name: "Integer",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 344, col: 34, offset: 8272},
+						pos:  position{line: 372, col: 34, offset: 8937},
 						name: "ParenthesizedIntExpr",
 					},
 				},
@@ -1757,30 +1938,30 @@ var g = &grammar{
 		},
 		{
 			name: "ResetRandCount",
-			pos:  position{line: 346, col: 1, offset: 8294},
+			pos:  position{line: 374, col: 1, offset: 8959},
 			expr: &stateCodeExpr{
-				pos: position{line: 346, col: 19, offset: 8312},
+				pos: position{line: 374, col: 19, offset: 8977},
 				run: (*parser).callonResetRandCount1,
 			},
 		},
 		{
 			name: "IncRandCount",
-			pos:  position{line: 351, col: 1, offset: 8356},
+			pos:  position{line: 379, col: 1, offset: 9021},
 			expr: &stateCodeExpr{
-				pos: position{line: 351, col: 17, offset: 8372},
+				pos: position{line: 379, col: 17, offset: 9037},
 				run: (*parser).callonIncRandCount1,
 			},
 		},
 		{
 			name: "Integer",
-			pos:  position{line: 356, col: 1, offset: 8445},
+			pos:  position{line: 384, col: 1, offset: 9110},
 			expr: &actionExpr{
-				pos: position{line: 356, col: 12, offset: 8456},
+				pos: position{line: 384, col: 12, offset: 9121},
 				run: (*parser).callonInteger1,
 				expr: &oneOrMoreExpr{
-					pos: position{line: 356, col: 12, offset: 8456},
+					pos: position{line: 384, col: 12, offset: 9121},
 					expr: &charClassMatcher{
-						pos:        position{line: 356, col: 12, offset: 8456},
+						pos:        position{line: 384, col: 12, offset: 9121},
 						val:        "[0-9]",
 						ranges:     []rune{'0', '9'},
 						ignoreCase: false,
@@ -1791,37 +1972,37 @@ var g = &grammar{
 		},
 		{
 			name: "CompareOp",
-			pos:  position{line: 365, col: 1, offset: 8625},
+			pos:  position{line: 393, col: 1, offset: 9290},
 			expr: &choiceExpr{
-				pos: position{line: 365, col: 14, offset: 8638},
+				pos: position{line: 393, col: 14, offset: 9303},
 				alternatives: []interface{}{
 					&litMatcher{
-						pos:        position{line: 365, col: 14, offset: 8638},
+						pos:        position{line: 393, col: 14, offset: 9303},
 						val:        "=",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 365, col: 20, offset: 8644},
+						pos:        position{line: 393, col: 20, offset: 9309},
 						val:        "<>",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 365, col: 27, offset: 8651},
+						pos:        position{line: 393, col: 27, offset: 9316},
 						val:        "<=",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 365, col: 34, offset: 8658},
+						pos:        position{line: 393, col: 34, offset: 9323},
 						val:        "<",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 365, col: 40, offset: 8664},
+						pos:        position{line: 393, col: 40, offset: 9329},
 						val:        ">=",
 						ignoreCase: false,
 					},
 					&litMatcher{
-						pos:        position{line: 365, col: 47, offset: 8671},
+						pos:        position{line: 393, col: 47, offset: 9336},
 						val:        ">",
 						ignoreCase: false,
 					},
@@ -1830,11 +2011,11 @@ var g = &grammar{
 		},
 		{
 			name: "EOT",
-			pos:  position{line: 367, col: 1, offset: 8676},
+			pos:  position{line: 395, col: 1, offset: 9341},
 			expr: &notExpr{
-				pos: position{line: 367, col: 8, offset: 8683},
+				pos: position{line: 395, col: 8, offset: 9348},
 				expr: &anyMatcher{
-					line: 367, col: 9, offset: 8684,
+					line: 395, col: 9, offset: 9349,
 				},
 			},
 		},
@@ -2308,6 +2489,56 @@ func (p *parser) callonURoll1() (interface{}, error) {
 	return p.cur.onURoll1(stack["num"], stack["sides"])
 }
 
+func (c *current) onNamedDieFaces1(name interface{}) (interface{}, error) {
+	return ast.NewNamedDieFaces(name.(string)), nil
+}
+
+func (p *parser) callonNamedDieFaces1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNamedDieFaces1(stack["name"])
+}
+
+func (c *current) onDieDefinitionName1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonDieDefinitionName1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onDieDefinitionName1()
+}
+
+func (c *current) onListedDieFaces1(first, rest interface{}) (interface{}, error) {
+	values := []int{first.(int)}
+
+	for _, r := range toIfaceSlice(rest) {
+		rs := toIfaceSlice(r)
+		values = append(values, rs[1].(int))
+	}
+
+	return ast.NewListedDieFaces(values), nil
+}
+
+func (p *parser) callonListedDieFaces1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onListedDieFaces1(stack["first"], stack["rest"])
+}
+
+func (c *current) onFaceValue1() (interface{}, error) {
+	// TODO: 整数が大きすぎるときなどのエラー処理が必要
+	value, _ := strconv.Atoi(string(c.text))
+
+	return value, nil
+}
+
+func (p *parser) callonFaceValue1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFaceValue1()
+}
+
 func (c *current) onRandomNumber1(min, max interface{}) (interface{}, error) {
 	minNode := min.(ast.Node)
 	maxNode := max.(ast.Node)
@@ -148,6 +148,25 @@ func TestParse(t *testing.T) {
 		{"(1+1)d[1...5]", "(DRollExpr (DRoll (+ 1 1) (RandomNumber 1 5)))", false},
 		{"([1...4]+1)d([2...4]+2)-1", "(DRollExpr (- (DRoll (+ (RandomNumber 1 4) 1) (+ (RandomNumber 2 4) 2)) 1))", false},
 
+		// 面を定義したダイスの加算ロール
+		{"3D{avg}", "(DRollExpr (DRoll 3 {avg}))", false},
+		{"3d{AVG}", "(DRollExpr (DRoll 3 {AVG}))", false},
+		{"2D{my_die2}", "(DRollExpr (DRoll 2 {my_die2}))", false},
+		{"3D{1,1,2,2,3,4}", "(DRollExpr (DRoll 3 {1,1,2,2,3,4}))", false},
+		{"4D{-1,0,1}", "(DRollExpr (DRoll 4 {-1,0,1}))", false},
+		{"3D{avg}+2D6+1", "(DRollExpr (+ (+ (DRoll 3 {avg}) (DRoll 2 6)) 1))", false},
+		{"[1...3]D{avg}", "(DRollExpr (DRoll (RandomNumber 1 3) {avg}))", false},
+		{"3D{avg}>=10", "(DRollComp (>= (DRoll 3 {avg}) 10))", false},
+		{"3D{}", "", true},
+		{"3D{1a}", "", true},
+		{"3D{1,}", "", true},
+		{"3D{ avg }", "", true},
+		// 記号を持つ面は列挙できない（登録したダイスの定義を名前で指定する）
+		{"4D{-,0,+}", "", true},
+		{"3D{1,2★}", "", true},
+		{"3B{1,a}", "", true},
+		{"{avg}D6", "", true},
+
 		// 加算ロール式の成功判定
 		{"2d6=7", "(DRollComp (= (DRoll 2 6) 7))", false},
 		{"2d6<>7", "(DRollComp (<> (DRoll 2 6) 7))", false},
@@ -180,6 +199,11 @@ func TestParse(t *testing.T) {
 		{"2b6+3b8+5b12", "(BRollList (BRoll 2 6) (BRoll 3 8) (BRoll 5 12))", false},
 		{"2b6+1", "", true},
 		{"1+2b6", "", true},
+		{"3b{avg}", "(BRollList (BRoll 3 {avg}))", false},
+		{"3b{1,1,2}+2b6", "(BRollList (BRoll 3 {1,1,2}) (BRoll 2 6))", false},
+		{"3b{avg}>=4", "(BRollComp (>= (BRollList (BRoll 3 {avg})) 4))", false},
+		{"3r{avg}", "", true},
+		{"3u{avg}", "", true},
 
 		// バラバラロールの成功数カウント
 		{"2b6=3", "(BRollComp (= (BRollList (BRoll 2 6)) 3))", false},
@@ -0,0 +1,545 @@
+/*
+宣言的に定義したゲームシステムのダイスボットのパッケージ。
+
+Go のコードを書かずに、JSONまたはYAMLのファイルでゲームシステムを定義できる。
+定義ファイルには、ゲーム識別子、ゲームシステム名、使用法の説明、
+BCDiceの基本コマンドに対応付けたコマンド、クリティカルなどの判定規則、および表を記述する。
+
+例（YAML）：
+
+	gameId: Homebrew
+	gameName: 自作システム
+	sortKey: しさくしすてむ
+	defaults:
+	  rounding: roundUp
+	  sortBRoll: true
+	outcomes:
+	  critical:
+	    min: 12
+	  fumble:
+	    max: 2
+	commands:
+	  - pattern: "HB{target:int}"
+	    expression: "2D6>={target}"
+	tables:
+	  - command: FT
+	    name: ファンブル表
+	    type: lookup
+	    dice: 2D6
+	    items: [武器を落とす, 転倒, ...]
+
+LoadDir を使うと、ディレクトリ内の定義ファイルをすべて読み込み、dicebot/list パッケージに登録できる。
+YAMLの解析には gopkg.in/yaml.v2 を使う（YAML 1.1）。
+引用符のない yes、no、on、off などは真偽値となるので、文字列として使う場合は引用符で囲むこと。
+*/
+package declarative
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/list"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/table"
+)
+
+// 定義ファイルから構築したダイスボット。
+type DiceBot struct {
+	// 構築済みの定義
+	compiled *compiledDefinition
+}
+
+var _ dicebot.MetadataDiceBot = (*DiceBot)(nil)
+var _ dicebot.EvaluationDefaultsDiceBot = (*DiceBot)(nil)
+
+// 構築済みの定義
+type compiledDefinition struct {
+	// 元の定義
+	def *Definition
+	// 使用法の説明
+	usage string
+	// 並べ替えのキー
+	sortKey string
+	// コマンドの接頭辞
+	prefixes []string
+	// 評価の既定値
+	defaults dicebot.EvaluationDefaults
+	// コマンドのルーター
+	router *dicebot.Router
+	// 表
+	tables table.Set
+}
+
+// GameID はゲーム識別子を返す。
+func (b *DiceBot) GameID() string {
+	return b.compiled.def.GameID
+}
+
+// GameName はゲームシステム名を返す。
+func (b *DiceBot) GameName() string {
+	return b.compiled.def.GameName
+}
+
+// Usage はダイスボットの使用法の説明を返す。
+func (b *DiceBot) Usage() string {
+	return b.compiled.usage
+}
+
+// SortKey は並べ替えのキーを返す。
+func (b *DiceBot) SortKey() string {
+	return b.compiled.sortKey
+}
+
+// HelpMessage はヘルプメッセージを返す。
+// 定義ファイルはロケールに対応していないため、どのロケールでも使用法の説明を返す。
+func (b *DiceBot) HelpMessage(_ locale.Locale) string {
+	return b.compiled.usage
+}
+
+// CommandPrefixes は、ゲームシステム固有のコマンドの接頭辞のスライスを返す。
+// 定義されたコマンドの接頭辞と表のコマンド名が含まれる。
+func (b *DiceBot) CommandPrefixes() []string {
+	return append([]string{}, b.compiled.prefixes...)
+}
+
+// EvaluationDefaults は、ゲームシステムの評価の既定値を返す。
+func (b *DiceBot) EvaluationDefaults() dicebot.EvaluationDefaults {
+	return b.compiled.defaults
+}
+
+// ExecuteCommand は指定されたコマンドを実行する。
+// 表のコマンドを優先し、次に定義されたコマンドを照合する。
+func (b *DiceBot) ExecuteCommand(c string, ev *evaluator.Evaluator) (*command.Result, error) {
+	name := strings.TrimSpace(c)
+	if _, found := b.compiled.tables.Find(name); found {
+		return b.compiled.tables.Execute(name, b.GameID(), ev)
+	}
+
+	if !b.compiled.router.MayMatch(name) {
+		return nil, fmt.Errorf("no game-system-specific command: %s", c)
+	}
+
+	return b.compiled.router.Execute(name, ev)
+}
+
+// NewConstructor は、定義からダイスボットのコンストラクタを作る。
+// 定義が不正な場合はエラーを返す。
+func NewConstructor(def *Definition) (dicebot.DiceBotConstructor, error) {
+	compiled, err := compile(def)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", def.GameID, err)
+	}
+
+	return func() dicebot.DiceBot {
+		return &DiceBot{compiled: compiled}
+	}, nil
+}
+
+// Register は、定義から構築したダイスボットを dicebot/list パッケージに登録する。
+// 定義が不正な場合や、識別子が登録済みのものと重複する場合はエラーを返す。
+func Register(def *Definition) error {
+	return register(def, list.Add)
+}
+
+// register は、定義から構築したダイスボットをaddを使って登録する。
+func register(def *Definition, add addFunc) error {
+	constructor, err := NewConstructor(def)
+	if err != nil {
+		return err
+	}
+
+	return add(def.GameID, constructor, def.Aliases...)
+}
+
+// LoadDir は、ディレクトリ内の定義ファイル（.json、.yaml、.yml）をファイル名の順に読み込み、
+// dicebot/list パッケージに登録する。返り値は登録したゲーム識別子のスライスとエラー。
+//
+// エラーが発生した場合はその時点で読み込みを中止する。それまでに読み込んだものは登録されたままとなる。
+func LoadDir(dir string) ([]string, error) {
+	return loadDir(dir, list.Add)
+}
+
+// ダイスボットを登録する関数の型
+type addFunc func(gameID string, constructor dicebot.DiceBotConstructor, aliases ...string) error
+
+// loadDir は、ディレクトリ内の定義ファイルを読み込み、addを使って登録する。
+func loadDir(dir string, add addFunc) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		if _, err := FormatFromPath(f.Name()); err == nil {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	gameIDs := []string{}
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+
+		def, err := LoadFile(path)
+		if err != nil {
+			return gameIDs, err
+		}
+
+		if err := register(def, add); err != nil {
+			return gameIDs, fmt.Errorf("%s: %s", path, err)
+		}
+
+		gameIDs = append(gameIDs, def.GameID)
+	}
+
+	return gameIDs, nil
+}
+
+// compile は定義を検証し、ダイスボットで使う形に変換する。
+func compile(def *Definition) (*compiledDefinition, error) {
+	if strings.TrimSpace(def.GameID) == "" {
+		return nil, fmt.Errorf("gameId is required")
+	}
+
+	defaults, err := compileDefaults(def.Defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := compileTables(def.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	router := dicebot.NewRouter()
+	for i, c := range def.Commands {
+		h, err := compileCommand(def, c)
+		if err != nil {
+			return nil, fmt.Errorf("commands[%d]: %s", i, err)
+		}
+
+		if err := handleSafely(router, c.Pattern, h); err != nil {
+			return nil, fmt.Errorf("commands[%d]: %s", i, err)
+		}
+	}
+
+	usage := def.Usage
+	if usage == "" {
+		usage = generateUsage(def, tables)
+	}
+
+	sortKey := def.SortKey
+	if sortKey == "" {
+		sortKey = strings.ToLower(def.GameID)
+	}
+
+	return &compiledDefinition{
+		def:      def,
+		usage:    usage,
+		sortKey:  sortKey,
+		prefixes: commandPrefixes(router, tables),
+		defaults: defaults,
+		router:   router,
+		tables:   tables,
+	}, nil
+}
+
+// 端数処理の名前と方法との対応
+var roundingMethods = map[string]ast.RoundingMethodType{
+	"":          ast.ROUNDING_METHOD_ROUND_DOWN,
+	"roundDown": ast.ROUNDING_METHOD_ROUND_DOWN,
+	"round":     ast.ROUNDING_METHOD_ROUND,
+	"roundUp":   ast.ROUNDING_METHOD_ROUND_UP,
+}
+
+// compileDefaults は評価の既定値の定義を変換する。
+// 定義が省略された場合は、既定値をそのまま返す。
+func compileDefaults(d *DefaultsDefinition) (dicebot.EvaluationDefaults, error) {
+	if d == nil {
+		return dicebot.EvaluationDefaults{}, nil
+	}
+
+	roundingMethod, found := roundingMethods[d.Rounding]
+	if !found {
+		return dicebot.EvaluationDefaults{}, fmt.Errorf("defaults: unknown rounding: %q", d.Rounding)
+	}
+
+	return dicebot.EvaluationDefaults{
+		RoundingMethod:  roundingMethod,
+		SortBRollValues: d.SortBRoll,
+		SortD66:         d.SortD66,
+	}, nil
+}
+
+// commandPrefixes は、ルーターの接頭辞と表のコマンド名を合わせたものを、
+// 大文字に変換し、重複を除いて辞書順に並べたスライスを返す。
+func commandPrefixes(router *dicebot.Router, tables table.Set) []string {
+	seen := map[string]bool{}
+	prefixes := []string{}
+
+	names := router.Prefixes()
+	for _, c := range tables {
+		names = append(names, strings.ToUpper(c.Name))
+	}
+
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			prefixes = append(prefixes, name)
+		}
+	}
+
+	sort.Strings(prefixes)
+
+	return prefixes
+}
+
+// handleSafely は、ルーターにパターンを登録する。
+// パターンが不正な場合は、panic をエラーに変換して返す。
+func handleSafely(r *dicebot.Router, pattern string, h dicebot.Handler) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	r.Handle(pattern, h)
+
+	return nil
+}
+
+// 式の中の引数の参照を表す正規表現
+var exprArgRefRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(?:\|([^{}]*))?\}`)
+
+// compileCommand はコマンドの処理関数を作る。
+func compileCommand(def *Definition, c CommandDefinition) (dicebot.Handler, error) {
+	if c.Pattern == "" {
+		return nil, fmt.Errorf("pattern is required")
+	}
+
+	if c.Expression == "" {
+		return nil, fmt.Errorf("expression is required")
+	}
+
+	// 引数をすべて "1" に置き換えて構文を確認する
+	sample := exprArgRefRe.ReplaceAllString(c.Expression, "1")
+	if _, err := parser.Parse("expression", []byte(sample)); err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %s", c.Expression, err)
+	}
+
+	rules := def.Outcomes
+	if c.Outcomes != nil {
+		rules = c.Outcomes
+	}
+
+	gameID := def.GameID
+	expression := c.Expression
+
+	return func(args *dicebot.Args, ev *evaluator.Evaluator) (*command.Result, error) {
+		input := expandExpression(expression, args)
+
+		node, err := parser.Parse("input", []byte(input))
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := command.Execute(node.(ast.Node), gameID, ev)
+		if err != nil {
+			return nil, err
+		}
+
+		applyOutcomeRules(result, rules)
+
+		return result, nil
+	}, nil
+}
+
+// expandExpression は、式の中の引数の参照を引数の値に置き換える。
+func expandExpression(expression string, args *dicebot.Args) string {
+	return exprArgRefRe.ReplaceAllStringFunc(expression, func(ref string) string {
+		m := exprArgRefRe.FindStringSubmatch(ref)
+		name, defaultValue := m[1], m[2]
+
+		if !args.Has(name) {
+			return defaultValue
+		}
+
+		return args.String(name)
+	})
+}
+
+// applyOutcomeRules は、振られたダイスの出目の合計に判定規則を適用する。
+// ダイスが振られなかった場合は何もしない。
+func applyOutcomeRules(result *command.Result, rules *OutcomeRules) {
+	if rules == nil || len(result.RolledDice) == 0 {
+		return
+	}
+
+	sum := 0
+	for _, d := range result.RolledDice {
+		sum += d.Value
+	}
+
+	switch {
+	case rules.Critical.Contains(sum):
+		result.SetCritical()
+	case rules.Fumble.Contains(sum):
+		result.SetFumble()
+	case rules.Special.Contains(sum):
+		result.SetSpecial()
+	}
+}
+
+// 振るダイスの表記を表す正規表現
+var tableDiceRe = regexp.MustCompile(`(?i)\A(\d+)D(\d+)\z`)
+
+// compileTables は表の定義を表に変換する。
+// 続けて引く表の参照は、すべての表を作った後に解決する。
+func compileTables(defs []TableDefinition) (table.Set, error) {
+	set := table.Set{}
+	commandToTable := map[string]table.Table{}
+
+	type nextRef struct {
+		item *table.Item
+		name string
+	}
+	refs := []nextRef{}
+
+	for i, d := range defs {
+		if d.Command == "" {
+			return nil, fmt.Errorf("tables[%d]: command is required", i)
+		}
+
+		key := strings.ToUpper(d.Command)
+		if _, duplicated := commandToTable[key]; duplicated {
+			return nil, fmt.Errorf("tables[%d]: duplicate command: %s", i, d.Command)
+		}
+
+		name := d.Name
+		if name == "" {
+			name = d.Command
+		}
+
+		var t table.Table
+		var items []*table.Item
+
+		switch d.Type {
+		case "range", "lookup":
+			m := tableDiceRe.FindStringSubmatch(d.Dice)
+			if m == nil {
+				return nil, fmt.Errorf("tables[%d]: invalid dice: %q", i, d.Dice)
+			}
+
+			num, _ := strconv.Atoi(m[1])
+			sides, _ := strconv.Atoi(m[2])
+
+			if d.Type == "range" {
+				rt := &table.RangeTable{Title: name, Num: num, Sides: sides}
+				for _, item := range d.Items {
+					max := item.Max
+					if max == 0 {
+						max = item.Min
+					}
+
+					rt.Items = append(rt.Items, table.RangeItem{
+						Min:  item.Min,
+						Max:  max,
+						Item: table.Item{Text: item.Text},
+					})
+				}
+
+				for j := range rt.Items {
+					items = append(items, &rt.Items[j].Item)
+				}
+				t = rt
+			} else {
+				lt := &table.LookupTable{Title: name, Num: num, Sides: sides}
+				for _, item := range d.Items {
+					lt.Items = append(lt.Items, table.Item{Text: item.Text})
+				}
+
+				for j := range lt.Items {
+					items = append(items, &lt.Items[j])
+				}
+				t = lt
+			}
+		case "d66":
+			dt := &table.D66Table{Title: name}
+
+			switch d.Order {
+			case "":
+				dt.Order = table.D66_GAME_DEFAULT
+			case "asRolled":
+				dt.Order = table.D66_AS_ROLLED
+			case "ascending":
+				dt.Order = table.D66_ASCENDING
+			default:
+				return nil, fmt.Errorf("tables[%d]: unknown D66 order: %q", i, d.Order)
+			}
+
+			for _, item := range d.Items {
+				dt.Items = append(dt.Items, table.D66Item{
+					Value: item.Value,
+					Item:  table.Item{Text: item.Text},
+				})
+			}
+
+			for j := range dt.Items {
+				items = append(items, &dt.Items[j].Item)
+			}
+			t = dt
+		default:
+			return nil, fmt.Errorf("tables[%d]: unknown table type: %q", i, d.Type)
+		}
+
+		for j, item := range d.Items {
+			if item.Next != "" {
+				refs = append(refs, nextRef{item: items[j], name: item.Next})
+			}
+		}
+
+		commandToTable[key] = t
+		set = append(set, table.Command{Name: d.Command, Table: t})
+	}
+
+	for _, ref := range refs {
+		next, found := commandToTable[strings.ToUpper(ref.name)]
+		if !found {
+			return nil, fmt.Errorf("unknown table: %s", ref.name)
+		}
+
+		ref.item.Next = next
+	}
+
+	return set, nil
+}
+
+// generateUsage は、コマンドと表の一覧から使用法の説明を生成する。
+func generateUsage(def *Definition, tables table.Set) string {
+	lines := []string{fmt.Sprintf("【%s】", def.GameName)}
+
+	for _, c := range def.Commands {
+		lines = append(lines, fmt.Sprintf("%s：%s", c.Pattern, c.Expression))
+	}
+
+	if len(tables) > 0 {
+		lines = append(lines, tables.Usage())
+	}
+
+	return strings.Join(lines, "\n")
+}
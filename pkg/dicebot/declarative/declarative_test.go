@@ -0,0 +1,380 @@
+package declarative
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+)
+
+// loadTestDiceBot はテスト用の定義ファイルからダイスボットを構築する。
+func loadTestDiceBot(t *testing.T, filename string) dicebot.DiceBot {
+	def, err := LoadFile(filepath.Join("testdata", filename))
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	constructor, err := NewConstructor(def)
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	return constructor()
+}
+
+func TestLoadFile_YAMLAndJSONAreEquivalent(t *testing.T) {
+	yamlDef, err := LoadFile(filepath.Join("testdata", "homebrew.yaml"))
+	if err != nil {
+		t.Fatalf("YAML読み込みエラー: %s", err)
+	}
+
+	jsonDef, err := LoadFile(filepath.Join("testdata", "homebrew.json"))
+	if err != nil {
+		t.Fatalf("JSON読み込みエラー: %s", err)
+	}
+
+	if !reflect.DeepEqual(yamlDef, jsonDef) {
+		t.Errorf("YAMLとJSONの定義が異なる:\nYAML: %+v\nJSON: %+v", yamlDef, jsonDef)
+	}
+}
+
+func TestDiceBot_ExecuteCommand(t *testing.T) {
+	testcases := []struct {
+		input              string
+		dice               []dice.Die
+		expected           string
+		expectedIsCritical bool
+		expectedIsFumble   bool
+	}{
+		{
+			input:    "HB8",
+			dice:     []dice.Die{{3, 6}, {5, 6}},
+			expected: "Homebrew : (2D6>=8) ＞ 8[3,5] ＞ 8 ＞ 成功",
+		},
+		{
+			input:              "hb13",
+			dice:               []dice.Die{{6, 6}, {6, 6}},
+			expected:           "Homebrew : (2D6>=13) ＞ 12[6,6] ＞ 12 ＞ 成功 ＞ クリティカル",
+			expectedIsCritical: true,
+		},
+		{
+			input:            "HB2",
+			dice:             []dice.Die{{1, 6}, {1, 6}},
+			expected:         "Homebrew : (2D6>=2) ＞ 2[1,1] ＞ 2 ＞ 失敗 ＞ ファンブル",
+			expectedIsFumble: true,
+		},
+		{
+			input:    "DMG",
+			dice:     []dice.Die{{3, 6}},
+			expected: "Homebrew : (1D6+0) ＞ 3[3]+0 ＞ 3",
+		},
+		{
+			input:              "DMG-2",
+			dice:               []dice.Die{{6, 6}},
+			expected:           "Homebrew : (1D6+(-2)) ＞ 6[6]+(-2) ＞ 4 ＞ クリティカル",
+			expectedIsCritical: true,
+		},
+		{
+			input:    "FT",
+			dice:     []dice.Die{{2, 6}},
+			expected: "Homebrew : ファンブル表(2) ＞ 武器を落とす",
+		},
+		{
+			input:    "ft",
+			dice:     []dice.Die{{6, 6}, {6, 6}, {6, 6}},
+			expected: "Homebrew : ファンブル表(6) ＞ 負傷する ＞ 負傷表(12[6,6]) ＞ Mr. O'Brien's # 重傷",
+		},
+		{
+			input:    "ST",
+			dice:     []dice.Die{{6, 6}, {3, 6}},
+			expected: "Homebrew : シーン表(36) ＞ 森 # 深い森",
+		},
+	}
+
+	for _, filename := range []string{"homebrew.yaml", "homebrew.json"} {
+		b := loadTestDiceBot(t, filename)
+
+		for _, test := range testcases {
+			t.Run(filename+"/"+test.input, func(t *testing.T) {
+				ev := evaluator.NewEvaluator(
+					roller.New(feeder.NewQueue(test.dice)),
+					evaluator.NewEnvironment(),
+				)
+
+				r, err := b.ExecuteCommand(test.input, ev)
+				if err != nil {
+					t.Fatalf("実行エラー: %s", err)
+					return
+				}
+
+				if actual := r.Message(); actual != test.expected {
+					t.Errorf("got %q, want %q", actual, test.expected)
+				}
+
+				if r.IsCritical != test.expectedIsCritical {
+					t.Errorf("IsCritical: got %v, want %v", r.IsCritical, test.expectedIsCritical)
+				}
+
+				if r.IsFumble != test.expectedIsFumble {
+					t.Errorf("IsFumble: got %v, want %v", r.IsFumble, test.expectedIsFumble)
+				}
+			})
+		}
+	}
+}
+
+func TestDiceBot_ExecuteUnknownCommand(t *testing.T) {
+	b := loadTestDiceBot(t, "homebrew.yaml")
+
+	for _, input := range []string{"2D6", "HB", "XX", "FT1"} {
+		ev := evaluator.NewEvaluator(
+			roller.New(feeder.NewEmptyQueue()),
+			evaluator.NewEnvironment(),
+		)
+
+		if _, err := b.ExecuteCommand(input, ev); err == nil {
+			t.Errorf("%q: エラーが発生しなかった", input)
+		}
+	}
+}
+
+func TestDiceBot_Info(t *testing.T) {
+	b := loadTestDiceBot(t, "homebrew.yaml")
+
+	if actual := b.GameID(); actual != "Homebrew" {
+		t.Errorf("GameID: got %q", actual)
+	}
+
+	if actual := b.GameName(); actual != "自作システム" {
+		t.Errorf("GameName: got %q", actual)
+	}
+
+	expectedUsage := "HB目標値：2D6で判定\n\nFT：ファンブル表"
+	if actual := b.Usage(); actual != expectedUsage {
+		t.Errorf("Usage: got %q, want %q", actual, expectedUsage)
+	}
+
+	if actual := dicebot.SortKey(b); actual != "しさくしすてむ" {
+		t.Errorf("SortKey: got %q", actual)
+	}
+
+	if actual := dicebot.HelpMessage(b, locale.EN); actual != expectedUsage {
+		t.Errorf("HelpMessage: got %q, want %q", actual, expectedUsage)
+	}
+
+	expectedPrefixes := []string{"DMG", "FT", "HB", "IT", "ST"}
+	if actual := dicebot.CommandPrefixes(b); !reflect.DeepEqual(actual, expectedPrefixes) {
+		t.Errorf("CommandPrefixes: got %v, want %v", actual, expectedPrefixes)
+	}
+}
+
+func TestGenerateUsage(t *testing.T) {
+	def, err := Parse([]byte(`
+gameId: Mini
+gameName: 小さなシステム
+commands:
+  - pattern: "M{target:int}"
+    expression: "1D100<={target}"
+tables:
+  - command: T
+    name: 表
+    type: lookup
+    dice: 1D2
+    items: [a, b]
+`), FORMAT_YAML)
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	constructor, err := NewConstructor(def)
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	expected := "【小さなシステム】\nM{target:int}：1D100<={target}\nT：表(1D2)"
+	if actual := constructor().Usage(); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
+
+func TestNewConstructor_InvalidDefinition(t *testing.T) {
+	testcases := []struct {
+		name string
+		json string
+	}{
+		{"NoGameID", `{"gameName": "x"}`},
+		{"NoPattern", `{"gameId": "X", "commands": [{"expression": "2D6"}]}`},
+		{"NoExpression", `{"gameId": "X", "commands": [{"pattern": "X"}]}`},
+		{"InvalidExpression", `{"gameId": "X", "commands": [{"pattern": "X", "expression": "2D"}]}`},
+		{"InvalidPattern", `{"gameId": "X", "commands": [{"pattern": "X{a:float}", "expression": "2D6"}]}`},
+		{"UnknownTableType", `{"gameId": "X", "tables": [{"command": "T", "type": "x"}]}`},
+		{"InvalidTableDice", `{"gameId": "X", "tables": [{"command": "T", "type": "range", "dice": "D6"}]}`},
+		{"UnknownRounding", `{"gameId": "X", "defaults": {"rounding": "x"}}`},
+		{"UnknownD66Order", `{"gameId": "X", "tables": [{"command": "T", "type": "d66", "order": "x"}]}`},
+		{"DuplicateTable", `{"gameId": "X", "tables": [` +
+			`{"command": "T", "type": "d66"}, {"command": "t", "type": "d66"}]}`},
+		{"UnknownNextTable", `{"gameId": "X", "tables": [` +
+			`{"command": "T", "type": "d66", "items": [{"value": 11, "next": "U"}]}]}`},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			def, err := Parse([]byte(test.json), FORMAT_JSON)
+			if err != nil {
+				t.Fatalf("読み込みエラー: %s", err)
+			}
+
+			if _, err := NewConstructor(def); err == nil {
+				t.Error("不正な定義が受け入れられた")
+			}
+		})
+	}
+}
+
+func TestDiceBot_EvaluationDefaults(t *testing.T) {
+	def, err := Parse([]byte(`{
+		"gameId": "Defaults",
+		"defaults": {"rounding": "roundUp", "sortBRoll": true, "sortD66": true},
+		"commands": [
+			{"pattern": "DV", "expression": "2D6/3"},
+			{"pattern": "BR", "expression": "3B6"}
+		],
+		"tables": [
+			{"command": "T", "name": "表", "type": "d66", "items": [{"value": 36, "text": "36"}, {"value": 63, "text": "63"}]}
+		]
+	}`), FORMAT_JSON)
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	constructor, err := NewConstructor(def)
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	b := constructor()
+
+	testcases := []struct {
+		input    string
+		dice     []dice.Die
+		expected string
+	}{
+		{"DV", []dice.Die{{3, 6}, {4, 6}}, "Defaults : (2D6/3) ＞ 7[3,4]/3 ＞ 3"},
+		{"BR", []dice.Die{{5, 6}, {1, 6}, {3, 6}}, "Defaults : (3B6) ＞ 1,3,5"},
+		{"T", []dice.Die{{6, 6}, {3, 6}}, "Defaults : 表(36) ＞ 36"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.input, func(t *testing.T) {
+			ev := evaluator.NewEvaluator(
+				roller.New(feeder.NewQueue(test.dice)),
+				evaluator.NewEnvironment(),
+			)
+			dicebot.ApplyEvaluationDefaults(b, ev)
+
+			r, err := b.ExecuteCommand(test.input, ev)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+			}
+
+			if actual := r.Message(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParse_YAMLFlowMapping(t *testing.T) {
+	source := "gameId: X\ntables:\n  - command: T\n    type: range\n    dice: 1D6\n    items:\n" +
+		"      - {min: 1, max: 3, text: 'a, b'}\n      - {min: 4, max: 6, text: c}\n"
+
+	def, err := Parse([]byte(source), FORMAT_YAML)
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	expected := []TableItemDefinition{
+		{Min: 1, Max: 3, Text: "a, b"},
+		{Min: 4, Max: 6, Text: "c"},
+	}
+	if !reflect.DeepEqual(def.Tables[0].Items, expected) {
+		t.Errorf("got %+v, want %+v", def.Tables[0].Items, expected)
+	}
+}
+
+func TestParse_YAMLFloatForInt(t *testing.T) {
+	source := "gameId: X\noutcomes:\n  critical:\n    min: 1.5\n"
+
+	if _, err := Parse([]byte(source), FORMAT_YAML); err == nil {
+		t.Error("整数のフィールドに小数が受け入れられた")
+	}
+}
+
+func TestParse_UnknownField(t *testing.T) {
+	if _, err := Parse([]byte(`{"gameId": "X", "gameNmae": "x"}`), FORMAT_JSON); err == nil {
+		t.Error("未知のフィールドが受け入れられた")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "declarative")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"b.json":     `{"gameId": "B", "aliases": ["BB"]}`,
+		"a.yml":      "gameId: A\n",
+		"readme.txt": "無視される",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registered := map[string][]string{}
+	add := func(gameID string, _ dicebot.DiceBotConstructor, aliases ...string) error {
+		registered[gameID] = aliases
+		return nil
+	}
+
+	gameIDs, err := loadDir(dir, add)
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	if expected := []string{"A", "B"}; !reflect.DeepEqual(gameIDs, expected) {
+		t.Errorf("got %v, want %v", gameIDs, expected)
+	}
+
+	if expected := []string{"BB"}; !reflect.DeepEqual(registered["B"], expected) {
+		t.Errorf("aliases: got %v, want %v", registered["B"], expected)
+	}
+}
+
+func TestLoadDir_InvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "declarative")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "x.yaml"), []byte("gameId: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	add := func(string, dicebot.DiceBotConstructor, ...string) error { return nil }
+	if _, err := loadDir(dir, add); err == nil {
+		t.Error("不正なファイルでエラーが発生しなかった")
+	}
+}
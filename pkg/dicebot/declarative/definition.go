@@ -0,0 +1,227 @@
+package declarative
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// 定義ファイルの形式の型
+type Format int
+
+const (
+	// JSON
+	FORMAT_JSON Format = iota
+	// YAML
+	FORMAT_YAML
+)
+
+// 定義ファイルの拡張子と形式との対応
+var extToFormat = map[string]Format{
+	".json": FORMAT_JSON,
+	".yaml": FORMAT_YAML,
+	".yml":  FORMAT_YAML,
+}
+
+// FormatFromPath は、ファイルの拡張子から定義ファイルの形式を判定する。
+// 対応していない拡張子の場合はエラーを返す。
+func FormatFromPath(path string) (Format, error) {
+	f, found := extToFormat[strings.ToLower(filepath.Ext(path))]
+	if !found {
+		return 0, fmt.Errorf("unsupported definition file: %s", path)
+	}
+
+	return f, nil
+}
+
+// ゲームシステムの定義。
+type Definition struct {
+	// ゲーム識別子
+	GameID string `json:"gameId"`
+	// ゲームシステム名
+	GameName string `json:"gameName"`
+	// 並べ替えのキー（ゲームシステム名の読み。省略した場合はゲーム識別子を小文字にしたもの）
+	SortKey string `json:"sortKey"`
+	// 使用法の説明（省略した場合はコマンドと表の一覧から生成する）
+	Usage string `json:"usage"`
+	// ゲーム識別子の別名
+	Aliases []string `json:"aliases"`
+	// すべてのコマンドに適用する判定規則
+	Outcomes *OutcomeRules `json:"outcomes"`
+	// 評価の既定値
+	Defaults *DefaultsDefinition `json:"defaults"`
+	// コマンド
+	Commands []CommandDefinition `json:"commands"`
+	// 表
+	Tables []TableDefinition `json:"tables"`
+}
+
+// 評価の既定値の定義。
+type DefaultsDefinition struct {
+	// 端数処理の方法を指定しない除算（"/"）の端数処理
+	// （"roundDown"、"round" または "roundUp"。省略した場合は "roundDown"）
+	Rounding string `json:"rounding"`
+	// バラバラロールの出目を昇順に並べ替えるかどうか
+	SortBRoll bool `json:"sortBRoll"`
+	// D66の出目を昇順に並べ替えるかどうか（order を省略したD66の表に適用される）
+	SortD66 bool `json:"sortD66"`
+}
+
+// コマンドの定義。
+type CommandDefinition struct {
+	// コマンドのパターン（dicebot.Router の形式）
+	Pattern string `json:"pattern"`
+	// 実行するBCDiceの基本コマンド
+	//
+	// {名前} はパターンの引数に置き換えられる。
+	// 引数が省略された場合の既定値は {名前|既定値} で指定する。
+	Expression string `json:"expression"`
+	// コマンドに適用する判定規則（省略した場合はゲームシステムの判定規則を使う）
+	Outcomes *OutcomeRules `json:"outcomes"`
+}
+
+// クリティカルなどの判定規則。
+// 各範囲は、振られたダイスの出目の合計に対して適用される。
+type OutcomeRules struct {
+	// クリティカルとなる範囲
+	Critical *ValueRange `json:"critical"`
+	// ファンブルとなる範囲
+	Fumble *ValueRange `json:"fumble"`
+	// スペシャルとなる範囲
+	Special *ValueRange `json:"special"`
+}
+
+// 値の範囲。
+// 省略した境界は制限されない。
+type ValueRange struct {
+	// 最小値
+	Min *int `json:"min"`
+	// 最大値
+	Max *int `json:"max"`
+}
+
+// Contains は、値が範囲に含まれるかどうかを返す。
+func (r *ValueRange) Contains(value int) bool {
+	if r == nil {
+		return false
+	}
+
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+
+	return true
+}
+
+// 表の定義。
+type TableDefinition struct {
+	// 表を引くコマンド名
+	Command string `json:"command"`
+	// 表の名前
+	Name string `json:"name"`
+	// 表の種類（"range"、"lookup"、"d66"）
+	Type string `json:"type"`
+	// 振るダイス（"2D6" など。"range" および "lookup" で使う）
+	Dice string `json:"dice"`
+	// D66の出目の並べ方（"asRolled" または "ascending"。"d66" で使う）
+	//
+	// 省略した場合はゲームシステムの既定値（defaults.sortD66）に従う。
+	Order string `json:"order"`
+	// 項目
+	Items []TableItemDefinition `json:"items"`
+}
+
+// 表の項目の定義。
+//
+// "lookup" の表では、項目を文字列のみで記述することもできる。
+type TableItemDefinition struct {
+	// 出目の最小値（"range" で使う）
+	Min int `json:"min"`
+	// 出目の最大値（"range" で使う。省略した場合は最小値と同じ）
+	Max int `json:"max"`
+	// 出目（"d66" で使う）
+	Value int `json:"value"`
+	// 項目の文字列
+	Text string `json:"text"`
+	// 続けて引く表のコマンド名
+	Next string `json:"next"`
+}
+
+// UnmarshalJSON は、文字列またはオブジェクトから表の項目の定義を読み込む。
+func (d *TableItemDefinition) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*d = TableItemDefinition{Text: text}
+		return nil
+	}
+
+	// UnmarshalJSON の再帰呼び出しを避けるための型
+	type plain TableItemDefinition
+
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	*d = TableItemDefinition(p)
+
+	return nil
+}
+
+// Parse は、指定された形式の定義を読み込む。
+// 未知のフィールドはエラーとなる。
+func Parse(data []byte, format Format) (*Definition, error) {
+	jsonData := data
+
+	if format == FORMAT_YAML {
+		v, err := parseYAML(data)
+		if err != nil {
+			return nil, err
+		}
+
+		j, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonData = j
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+
+	var def Definition
+	if err := dec.Decode(&def); err != nil {
+		return nil, err
+	}
+
+	return &def, nil
+}
+
+// LoadFile は定義ファイルを読み込む。
+// 形式はファイルの拡張子から判定する。
+func LoadFile(path string) (*Definition, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return def, nil
+}
@@ -0,0 +1,78 @@
+package declarative
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// parseYAML は、YAMLを解析し、JSONに変換できる値に変換する。
+//
+// 解析には gopkg.in/yaml.v2 を使う。
+// マッピングのキーは文字列に限る。文字列以外のキーはエラーとなる。
+// 複数の文書を含む場合は、最初の文書のみを解析する。
+// 空の文書は空のマッピングとして扱う。
+func parseYAML(data []byte) (interface{}, error) {
+	var v interface{}
+	if err := yaml.UnmarshalStrict(data, &v); err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	return convertYAMLValue(v, "")
+}
+
+// convertYAMLValue は、yaml.v2 で解析した値を、JSONに変換できる値に変換する。
+//
+// yaml.v2 はマッピングを map[interface{}]interface{} として返すので、
+// キーを文字列に変換した map[string]interface{} に置き換える。
+// path はエラーメッセージに含める値の位置。
+func convertYAMLValue(v interface{}, path string) (interface{}, error) {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(x))
+
+		for key, value := range x {
+			k, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("yaml: %s: mapping key must be a string: %v", yamlPath(path), key)
+			}
+
+			converted, err := convertYAMLValue(value, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+
+			m[k] = converted
+		}
+
+		return m, nil
+	case []interface{}:
+		s := make([]interface{}, 0, len(x))
+
+		for i, value := range x {
+			converted, err := convertYAMLValue(value, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+
+			s = append(s, converted)
+		}
+
+		return s, nil
+	}
+
+	return v, nil
+}
+
+// yamlPath は、エラーメッセージに含める値の位置を返す。
+func yamlPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+
+	return path
+}
@@ -0,0 +1,183 @@
+package declarative
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	testcases := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			name:     "Empty",
+			input:    "# コメントのみ\n\n",
+			expected: map[string]interface{}{},
+		},
+		{
+			name:  "Scalars",
+			input: "a: 1\nb: -2\nc: true\nd: null\ne: ~\nf: text with spaces\ng: \"q\\\"uoted\"\nh: 'it''s'\ni: 3D6\n",
+			expected: map[string]interface{}{
+				"a": 1, "b": -2, "c": true, "d": nil, "e": nil,
+				"f": "text with spaces", "g": `q"uoted`, "h": "it's", "i": "3D6",
+			},
+		},
+		{
+			name:  "Comments",
+			input: "a: x # comment\nb: \"y # not comment\" # comment\nc: a#b\n",
+			expected: map[string]interface{}{
+				"a": "x", "b": "y # not comment", "c": "a#b",
+			},
+		},
+		{
+			name:  "NestedMapping",
+			input: "a:\n  b:\n    c: 1\n  d: 2\ne: 3\n",
+			expected: map[string]interface{}{
+				"a": map[string]interface{}{
+					"b": map[string]interface{}{"c": 1},
+					"d": 2,
+				},
+				"e": 3,
+			},
+		},
+		{
+			name:  "Sequence",
+			input: "a:\n  - 1\n  - x\nb:\n- v\n- z\n",
+			expected: map[string]interface{}{
+				"a": []interface{}{1, "x"},
+				"b": []interface{}{"v", "z"},
+			},
+		},
+		{
+			name:  "SequenceOfMappings",
+			input: "a:\n  - b: 1\n    c: 2\n  -   d: 3\n  -\n    e: 4\n",
+			expected: map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{"b": 1, "c": 2},
+					map[string]interface{}{"d": 3},
+					map[string]interface{}{"e": 4},
+				},
+			},
+		},
+		{
+			name:  "NestedSequence",
+			input: "- - 1\n  - 2\n- 3\n",
+			expected: []interface{}{
+				[]interface{}{1, 2},
+				3,
+			},
+		},
+		{
+			name:  "FlowSequence",
+			input: "a: [1, \"b, c\", 'd']\nb: []\n",
+			expected: map[string]interface{}{
+				"a": []interface{}{1, "b, c", "d"},
+				"b": []interface{}{},
+			},
+		},
+		{
+			name:  "Literal",
+			input: "a: |\n  line 1\n\n    line 2\nb: |-\n  x\n  y\n\nc: 1\n",
+			expected: map[string]interface{}{
+				"a": "line 1\n\n  line 2\n",
+				"b": "x\ny",
+				"c": 1,
+			},
+		},
+		{
+			name:  "QuotedKey",
+			input: "\"a: b\": 1\n'c': 2\n",
+			expected: map[string]interface{}{
+				"a: b": 1, "c": 2,
+			},
+		},
+		{
+			name:  "FlowMapping",
+			input: "rules:\n  - {min: 2, max: 3}\n  - {text: 'a, b'}\n",
+			expected: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"min": 2, "max": 3},
+					map[string]interface{}{"text": "a, b"},
+				},
+			},
+		},
+		{
+			name:  "NestedFlowSequence",
+			input: "a: [[1], []]\n",
+			expected: map[string]interface{}{
+				"a": []interface{}{[]interface{}{1}, []interface{}{}},
+			},
+		},
+		{
+			name:  "Float",
+			input: "a: 1.5\nb: '1.5'\n",
+			expected: map[string]interface{}{
+				"a": 1.5, "b": "1.5",
+			},
+		},
+		{
+			name:  "Folded",
+			input: "a: >\n  line 1\n  line 2\n\n  line 3\nb: 1\n",
+			expected: map[string]interface{}{
+				"a": "line 1 line 2\nline 3\n",
+				"b": 1,
+			},
+		},
+		{
+			// YAML 1.1 の規則に従い、引用符のない yes などは真偽値となる
+			name:  "Booleans",
+			input: "a: [yes, 'yes', off]\n",
+			expected: map[string]interface{}{
+				"a": []interface{}{true, "yes", false},
+			},
+		},
+		{
+			name:  "CRLF",
+			input: "a: 1\r\nb: 2\r\n",
+			expected: map[string]interface{}{
+				"a": 1, "b": 2,
+			},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := parseYAML([]byte(test.input))
+			if err != nil {
+				t.Fatalf("構文エラー: %s", err)
+				return
+			}
+
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %#v, want %#v", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseYAML_Error(t *testing.T) {
+	testcases := []struct {
+		name  string
+		input string
+	}{
+		{"TabIndent", "a:\n\tb: 1\n"},
+		{"UnexpectedIndent", "a: 1\n  b: 2\n"},
+		{"DuplicateKey", "a: 1\na: 2\n"},
+		{"NotMapping", "a: 1\njust text\n"},
+		{"NonStringKey", "1: a\n"},
+		{"NestedNonStringKey", "a:\n  - {true: 1}\n"},
+		{"UnterminatedFlowMapping", "a: {b: 1\n"},
+		{"UnterminatedQuote", "a: \"x\n"},
+		{"SequenceInMapping", "a: 1\n- b\n"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseYAML([]byte(test.input)); err == nil {
+				t.Error("エラーが発生しなかった")
+			}
+		})
+	}
+}
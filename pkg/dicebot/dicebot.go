@@ -4,14 +4,21 @@
 package dicebot
 
 import (
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
 	"github.com/raa0121/GoBCDice/pkg/core/command"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 )
 
 // ダイスボットを構築する関数の型。
 type DiceBotConstructor func() DiceBot
 
 // ダイスボットのインターフェース。
+//
+// 基本コマンドの成功判定（2D6>=7、1D100<=50 など）の結果を変更したいダイスボットは、
+// command.Check2D6Hook や command.Check1D100Hook などのフックのインターフェースを実装する。
 type DiceBot interface {
 	// GameID はゲーム識別子を返す。
 	GameID() string
@@ -22,3 +29,116 @@ type DiceBot interface {
 	// ExecuteCommand は指定されたコマンドを実行する。
 	ExecuteCommand(command string, ev *evaluator.Evaluator) (*command.Result, error)
 }
+
+// ゲームシステム名と使用法の説明を地域化できるダイスボットのインターフェース。
+//
+// このインターフェースの実装は任意。実装していないダイスボットでは、
+// どのロケールでも GameName および Usage の結果が使われる。
+type LocalizedDiceBot interface {
+	DiceBot
+
+	// LocalizedGameName は、指定されたロケールのゲームシステム名を返す。
+	LocalizedGameName(l locale.Locale) string
+	// LocalizedUsage は、指定されたロケールのダイスボットの使用法の説明を返す。
+	LocalizedUsage(l locale.Locale) string
+}
+
+// GameName は、指定されたロケールにおけるダイスボットのゲームシステム名を返す。
+func GameName(b DiceBot, l locale.Locale) string {
+	if lb, ok := b.(LocalizedDiceBot); ok {
+		return lb.LocalizedGameName(l)
+	}
+
+	return b.GameName()
+}
+
+// Usage は、指定されたロケールにおけるダイスボットの使用法の説明を返す。
+func Usage(b DiceBot, l locale.Locale) string {
+	if lb, ok := b.(LocalizedDiceBot); ok {
+		return lb.LocalizedUsage(l)
+	}
+
+	return b.Usage()
+}
+
+// 並べ替えのキー、ヘルプメッセージ、コマンドの接頭辞などの追加情報を提供するダイスボットのインターフェース。
+//
+// このインターフェースの実装は任意。実装していないダイスボットでは、
+// SortKey、HelpMessage、CommandPrefixes の各関数が既定値を返す。
+type MetadataDiceBot interface {
+	DiceBot
+
+	// SortKey は、ゲームシステムを並べ替えるためのキーを返す。
+	// 日本語のゲームシステム名の場合は、ひらがなの読みを使う。
+	SortKey() string
+	// HelpMessage は、指定されたロケールのヘルプメッセージを返す。
+	HelpMessage(l locale.Locale) string
+	// CommandPrefixes は、ゲームシステム固有のコマンドの接頭辞のスライスを返す。
+	CommandPrefixes() []string
+}
+
+// SortKey は、ダイスボットの並べ替えのキーを返す。
+// MetadataDiceBot を実装していない場合は、ゲーム識別子を小文字にしたものを返す。
+func SortKey(b DiceBot) string {
+	if mb, ok := b.(MetadataDiceBot); ok {
+		return mb.SortKey()
+	}
+
+	return strings.ToLower(b.GameID())
+}
+
+// HelpMessage は、指定されたロケールにおけるダイスボットのヘルプメッセージを返す。
+// MetadataDiceBot を実装していない場合は、使用法の説明を返す。
+func HelpMessage(b DiceBot, l locale.Locale) string {
+	if mb, ok := b.(MetadataDiceBot); ok {
+		return mb.HelpMessage(l)
+	}
+
+	return Usage(b, l)
+}
+
+// CommandPrefixes は、ダイスボットのゲームシステム固有のコマンドの接頭辞のスライスを返す。
+// MetadataDiceBot を実装していない場合は空のスライスを返す。
+func CommandPrefixes(b DiceBot) []string {
+	if mb, ok := b.(MetadataDiceBot); ok {
+		return mb.CommandPrefixes()
+	}
+
+	return []string{}
+}
+
+// ゲームシステムごとの評価の既定値。
+type EvaluationDefaults struct {
+	// 端数処理の方法を指定しない除算（"/"）の端数処理の方法
+	RoundingMethod ast.RoundingMethodType
+	// バラバラロールの出目を昇順に並べ替えるかどうか
+	SortBRollValues bool
+	// D66の出目を昇順に並べ替える（小さい方を十の位とする）かどうか
+	SortD66 bool
+}
+
+// Apply は評価の既定値を評価器に設定する。
+func (d EvaluationDefaults) Apply(ev *evaluator.Evaluator) {
+	ev.DefaultRoundingMethod = d.RoundingMethod
+	ev.SortBRollValues = d.SortBRollValues
+	ev.SortD66 = d.SortD66
+}
+
+// 評価の既定値を提供するダイスボットのインターフェース。
+//
+// このインターフェースの実装は任意。実装していないダイスボットでは、
+// 除算は切り捨て、バラバラロールおよびD66の出目は振った順となる。
+type EvaluationDefaultsDiceBot interface {
+	DiceBot
+
+	// EvaluationDefaults は、ゲームシステムの評価の既定値を返す。
+	EvaluationDefaults() EvaluationDefaults
+}
+
+// ApplyEvaluationDefaults は、ダイスボットの評価の既定値を評価器に設定する。
+// EvaluationDefaultsDiceBot を実装していない場合は何もしない。
+func ApplyEvaluationDefaults(b DiceBot, ev *evaluator.Evaluator) {
+	if db, ok := b.(EvaluationDefaultsDiceBot); ok {
+		db.EvaluationDefaults().Apply(ev)
+	}
+}
@@ -0,0 +1,466 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+)
+
+const (
+	// 1回の呼び出しの制限時間の既定値
+	DEFAULT_TIMEOUT = 5 * time.Second
+	// 終了を要求してから強制終了するまでの時間
+	CLOSE_TIMEOUT = 1 * time.Second
+)
+
+// 一度に振ることができるダイスの数の上限
+const maxDiceToRoll = 1000
+
+// 外部ダイスボットのプロセスの設定。
+type Config struct {
+	// 実行するファイルのパス
+	Path string
+	// コマンドライン引数
+	Args []string
+	// 作業ディレクトリ（空の場合は現在のディレクトリ）
+	Dir string
+	// 追加する環境変数（"名前=値" の形式）
+	Env []string
+	// 1回の呼び出しの制限時間（0の場合は DEFAULT_TIMEOUT）
+	Timeout time.Duration
+	// 標準エラー出力の書き込み先（nilの場合は破棄する）
+	Stderr io.Writer
+}
+
+// 外部ダイスボットのプロセスと通信するクライアント。
+//
+// プロセスは最初の呼び出しの際に起動する。呼び出しが制限時間を超えた場合や、
+// プロセスが異常終了した場合はプロセスを終了させ、次の呼び出しの際に起動し直す。
+// 制限時間には要求の送信も含まれる。
+//
+// プロセスは独自のプロセスグループで起動し、強制終了の際はグループ全体を終了させる
+// （Windowsを除く）。これにより、外部ダイスボットが起動した子プロセスが
+// 標準出力を開いたままにしていても、呼び出しが制限時間内に終わる。
+// 呼び出しは排他的に行われるため、複数のゴルーチンから使用できる。
+type Client struct {
+	// 設定
+	config Config
+
+	// 排他制御
+	mu sync.Mutex
+	// 実行中のプロセス（起動していない場合はnil）
+	proc *process
+	// 次の要求の識別子
+	nextID int64
+	// 取得済みのダイスボットの情報
+	info *Info
+}
+
+// 実行中の外部ダイスボットのプロセス
+type process struct {
+	// コマンド
+	cmd *exec.Cmd
+	// 標準入力
+	stdin io.WriteCloser
+	// 標準出力
+	stdout io.ReadCloser
+	// 標準入出力の接続
+	conn *conn
+	// 受信したメッセージ（プロセスの出力が終わると閉じられる）
+	messages chan *message
+	// 受信を終えた原因のエラー（messages が閉じられた後に参照できる）
+	readErr error
+	// プロセスの終了を待ち終えると閉じられる
+	done chan struct{}
+}
+
+// NewClient は新しいクライアントを返す。プロセスはまだ起動しない。
+func NewClient(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+
+	return &Client{config: config}
+}
+
+// Info はダイスボットの情報を返す。
+// 情報は最初の呼び出しで取得し、以後はそれを返す。
+func (c *Client) Info() (*Info, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.info != nil {
+		return c.info, nil
+	}
+
+	raw, err := c.call(METHOD_INFO, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+	if err := json.Unmarshal(raw, info); err != nil {
+		return nil, fmt.Errorf("%s: invalid result: %s", METHOD_INFO, err)
+	}
+
+	if info.GameID == "" {
+		return nil, fmt.Errorf("%s: gameId is required", METHOD_INFO)
+	}
+
+	c.info = info
+
+	return info, nil
+}
+
+// Execute は外部ダイスボットでコマンドを実行する。
+// 外部ダイスボットがコマンドを扱わなかった場合は、結果としてnilを返す。
+//
+// 外部ダイスボットからのダイスロールの要求は ev を使って処理される。
+func (c *Client) Execute(input string, ev *evaluator.Evaluator) (*ExecuteResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	params := &ExecuteParams{
+		Command: input,
+		Locale:  string(ev.Locale),
+	}
+
+	raw, err := c.call(METHOD_EXECUTE, params, ev)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ExecuteResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("%s: invalid result: %s", METHOD_EXECUTE, err)
+	}
+
+	return result, nil
+}
+
+// Close はプロセスを終了させる。
+//
+// 標準入力を閉じて終了を待ち、CLOSE_TIMEOUT 以内に終了しなかった場合は強制終了する。
+// 終了後に呼び出しがあった場合は、プロセスを起動し直す。
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.proc == nil {
+		return nil
+	}
+
+	p := c.proc
+	c.proc = nil
+
+	p.stdin.Close()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(CLOSE_TIMEOUT):
+		p.kill()
+		return fmt.Errorf("%s: killed because it did not exit", c.config.Path)
+	}
+}
+
+// call はメソッドを呼び出し、結果を返す。
+// 呼び出しの間に外部ダイスボットから届いた要求は、evを使って処理する。
+//
+// 呼び出し元で排他制御を行うこと。
+func (c *Client) call(method string, params interface{}, ev *evaluator.Evaluator) (json.RawMessage, error) {
+	if c.proc == nil {
+		p, err := c.start()
+		if err != nil {
+			return nil, err
+		}
+
+		c.proc = p
+	}
+
+	p := c.proc
+
+	c.nextID++
+	id := c.nextID
+
+	req := &message{ID: id, Method: method}
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Params = b
+	}
+
+	timer := time.NewTimer(c.config.Timeout)
+	defer timer.Stop()
+
+	if err := c.writeWithDeadline(timer, func() error {
+		return p.conn.write(req)
+	}); err != nil {
+		return nil, fmt.Errorf("%s: %s", method, err)
+	}
+
+	for {
+		select {
+		case m, ok := <-p.messages:
+			if !ok {
+				c.abort()
+				return nil, fmt.Errorf("%s: process exited: %s", method, p.readErr)
+			}
+
+			if m.isRequest() {
+				result, err := c.handleRequest(m, ev)
+				if respondErr := c.writeWithDeadline(timer, func() error {
+					return p.conn.respond(m.ID, result, err)
+				}); respondErr != nil {
+					return nil, fmt.Errorf("%s: %s", method, respondErr)
+				}
+
+				continue
+			}
+
+			if m.ID != id {
+				// 制限時間を過ぎた以前の要求への応答などは無視する
+				continue
+			}
+
+			if m.Error != nil {
+				return nil, m.Error
+			}
+
+			return m.Result, nil
+		case <-timer.C:
+			c.abort()
+			return nil, fmt.Errorf("%s: timed out after %s", method, c.config.Timeout)
+		}
+	}
+}
+
+// writeWithDeadline は、timerが満了するまでにwriteを実行する。
+// 書き込みに失敗した場合や、外部ダイスボットが標準入力を読まずに制限時間を超えた場合は、
+// プロセスを強制終了させてエラーを返す。
+//
+// 呼び出し元で排他制御を行うこと。
+func (c *Client) writeWithDeadline(timer *time.Timer, write func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- write()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			c.abort()
+			return err
+		}
+
+		return nil
+	case <-timer.C:
+		// プロセスを強制終了させると標準入力が閉じられ、書き込みも終わる
+		c.abort()
+		return fmt.Errorf("timed out after %s", c.config.Timeout)
+	}
+}
+
+// abort はプロセスを強制終了させる。次の呼び出しの際にプロセスを起動し直す。
+//
+// 呼び出し元で排他制御を行うこと。
+func (c *Client) abort() {
+	if c.proc == nil {
+		return
+	}
+
+	c.proc.kill()
+	c.proc = nil
+}
+
+// start はプロセスを起動する。
+func (c *Client) start() (*process, error) {
+	cmd := exec.Command(c.config.Path, c.config.Args...)
+	cmd.Dir = c.config.Dir
+	setProcessGroup(cmd)
+
+	if len(c.config.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.config.Env...)
+	}
+
+	if c.config.Stderr != nil {
+		cmd.Stderr = c.config.Stderr
+	} else {
+		cmd.Stderr = ioutil.Discard
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &process{
+		cmd:      cmd,
+		stdin:    stdin,
+		stdout:   stdout,
+		conn:     newConn(stdout, stdin),
+		messages: make(chan *message),
+		done:     make(chan struct{}),
+	}
+
+	go p.receive()
+
+	return p, nil
+}
+
+// receive は、プロセスの出力からメッセージを読み込んで messages に送る。
+// 出力が終わると messages を閉じ、プロセスの終了を待つ。
+func (p *process) receive() {
+	for {
+		m, err := p.conn.read()
+		if err != nil {
+			p.readErr = err
+			break
+		}
+
+		p.messages <- m
+	}
+
+	close(p.messages)
+
+	p.cmd.Wait()
+	close(p.done)
+}
+
+// kill はプロセスグループを強制終了させる。
+//
+// 子プロセスが標準出力を開いたままにしていても受信が終わるように、標準出力を自ら閉じる。
+// 終了は待たない（プロセスの終了は受信側のゴルーチンが待つ）。
+func (p *process) kill() {
+	p.stdin.Close()
+	killProcessGroup(p.cmd)
+	p.stdout.Close()
+
+	// 受信側のゴルーチンが終了できるように、残りのメッセージを読み捨てる
+	go func() {
+		for range p.messages {
+		}
+	}()
+}
+
+// handleRequest は外部ダイスボットからの要求を処理する。
+func (c *Client) handleRequest(m *message, ev *evaluator.Evaluator) (interface{}, error) {
+	if ev == nil {
+		return nil, &RPCError{
+			Code:    ERROR_INTERNAL,
+			Message: m.Method + " can be called only while executing a command",
+		}
+	}
+
+	switch m.Method {
+	case METHOD_ROLL_DICE:
+		params := &RollDiceParams{}
+		if err := json.Unmarshal(m.Params, params); err != nil {
+			return nil, &RPCError{Code: ERROR_INVALID_PARAMS, Message: err.Error()}
+		}
+
+		return rollDice(params, ev)
+	case METHOD_EXECUTE_BASIC:
+		params := &ExecuteBasicParams{}
+		if err := json.Unmarshal(m.Params, params); err != nil {
+			return nil, &RPCError{Code: ERROR_INVALID_PARAMS, Message: err.Error()}
+		}
+
+		gameID := ""
+		if c.info != nil {
+			gameID = c.info.GameID
+		}
+
+		return executeBasic(params, gameID, ev)
+	}
+
+	return nil, &RPCError{Code: ERROR_METHOD_NOT_FOUND, Message: "method not found: " + m.Method}
+}
+
+// rollDice は、ホストのダイス供給機を使ってダイスを振る。
+// 振られたダイスはコマンドの実行結果に記録される。
+func rollDice(params *RollDiceParams, ev *evaluator.Evaluator) (*RollDiceResult, error) {
+	if params.Num > maxDiceToRoll {
+		return nil, &RPCError{
+			Code:    ERROR_INVALID_PARAMS,
+			Message: fmt.Sprintf("invalid number of dice: %d", params.Num),
+		}
+	}
+
+	rolledDice, err := ev.RollDice(params.Num, params.Sides)
+	if err != nil {
+		return nil, &RPCError{Code: ERROR_INVALID_PARAMS, Message: err.Error()}
+	}
+
+	values := make([]int, 0, len(rolledDice))
+	for _, d := range rolledDice {
+		values = append(values, d.Value)
+	}
+
+	return &RollDiceResult{Values: values}, nil
+}
+
+// executeBasic はBCDiceの基本コマンドを実行する。
+func executeBasic(params *ExecuteBasicParams, gameID string, ev *evaluator.Evaluator) (*ExecuteResult, error) {
+	node, err := parser.Parse("external", []byte(params.Command))
+	if err != nil {
+		return nil, &RPCError{
+			Code:    ERROR_INVALID_PARAMS,
+			Message: "syntax error: " + params.Command,
+		}
+	}
+
+	numOfRolledDice := len(ev.RolledDice())
+
+	r, err := command.Execute(node.(ast.Node), gameID, ev)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExecuteResult{
+		Parts:    r.MessageParts,
+		Message:  r.JoinedMessageParts(),
+		Total:    r.Total,
+		Critical: r.IsCritical,
+		Fumble:   r.IsFumble,
+		Special:  r.IsSpecial,
+		Dice:     []int{},
+	}
+
+	switch r.SuccessCheckResult {
+	case command.SUCCESS_CHECK_SUCCESS:
+		success := true
+		result.Success = &success
+	case command.SUCCESS_CHECK_FAILURE:
+		success := false
+		result.Success = &success
+	}
+
+	for _, d := range ev.RolledDice()[numOfRolledDice:] {
+		result.Dice = append(result.Dice, d.Value)
+	}
+
+	return result, nil
+}
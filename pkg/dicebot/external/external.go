@@ -0,0 +1,255 @@
+/*
+別のプロセスで動作するダイスボット（外部ダイスボット）のパッケージ。
+
+本体に組み込みたくない独自のダイスボットを、別の実行ファイルとして動作させることができる。
+外部ダイスボットとは、標準入出力を通じて JSON-RPC 2.0 のメッセージを1行に1つずつ送受信する。
+
+ホストから外部ダイスボットへの要求は以下の通り。
+
+* info: ダイスボットの情報（Info）を返す。
+
+* execute: コマンドを実行し、結果（ExecuteResult）を返す。
+ゲームシステム固有のコマンドではない場合は null を返す。
+
+execute の処理中に、外部ダイスボットはホストに以下の要求を送ることができる。
+
+* rollDice: ホストのダイス供給機を使ってダイスを振る。
+振られたダイスはコマンドの実行結果に記録されるため、再現や検証ができる。
+
+* executeBasic: BCDiceの基本コマンドを実行する。
+
+例（ダイスボット → ホスト）：
+
+	{"jsonrpc":"2.0","id":1,"method":"rollDice","params":{"num":2,"sides":6}}
+
+外部ダイスボットは、標準入力が閉じられたら終了しなければならない。
+Go で外部ダイスボットを書く場合は Serve を使うことができる。
+*/
+package external
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/list"
+)
+
+// 外部ダイスボット。
+type DiceBot struct {
+	// クライアント
+	client *Client
+	// ダイスボットの情報
+	info *Info
+}
+
+var _ dicebot.MetadataDiceBot = (*DiceBot)(nil)
+
+// GameID はゲーム識別子を返す。
+func (b *DiceBot) GameID() string {
+	return b.info.GameID
+}
+
+// GameName はゲームシステム名を返す。
+func (b *DiceBot) GameName() string {
+	return b.info.GameName
+}
+
+// Usage はダイスボットの使用法の説明を返す。
+func (b *DiceBot) Usage() string {
+	return b.info.Usage
+}
+
+// SortKey は、ゲームシステムを並べ替えるためのキーを返す。
+func (b *DiceBot) SortKey() string {
+	if b.info.SortKey == "" {
+		return strings.ToLower(b.info.GameID)
+	}
+
+	return b.info.SortKey
+}
+
+// HelpMessage はヘルプメッセージを返す。
+func (b *DiceBot) HelpMessage(_ locale.Locale) string {
+	return b.info.Usage
+}
+
+// CommandPrefixes は、ゲームシステム固有のコマンドの接頭辞のスライスを返す。
+func (b *DiceBot) CommandPrefixes() []string {
+	return append([]string{}, b.info.CommandPrefixes...)
+}
+
+// ExecuteCommand は、外部ダイスボットで指定されたコマンドを実行する。
+func (b *DiceBot) ExecuteCommand(c string, ev *evaluator.Evaluator) (*command.Result, error) {
+	r, err := b.client.Execute(c, ev)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.info.GameID, err)
+	}
+
+	if r == nil {
+		return nil, fmt.Errorf("no game-system-specific command: %s", c)
+	}
+
+	return newResult(r, b.info.GameID, ev)
+}
+
+// newResult は、外部ダイスボットの実行結果をコマンドの実行結果に変換する。
+func newResult(r *ExecuteResult, gameID string, ev *evaluator.Evaluator) (*command.Result, error) {
+	result := &command.Result{
+		GameID:         gameID,
+		RolledDice:     ev.RolledDice(),
+		DieDefinitions: ev.RolledDieDefinitions(),
+		Nonces:         ev.RolledDieNonces(),
+		Locale:         ev.Locale,
+		Total:          r.Total,
+		IsCritical:     r.Critical,
+		IsFumble:       r.Fumble,
+		IsSpecial:      r.Special,
+	}
+
+	switch {
+	case len(r.Parts) > 0:
+		result.MessageParts = append([]string{}, r.Parts...)
+	case r.Message != "":
+		result.MessageParts = []string{r.Message}
+	default:
+		return nil, fmt.Errorf("%s: parts or message is required", gameID)
+	}
+
+	if r.Success != nil {
+		if *r.Success {
+			result.SuccessCheckResult = command.SUCCESS_CHECK_SUCCESS
+		} else {
+			result.SuccessCheckResult = command.SUCCESS_CHECK_FAILURE
+		}
+	}
+
+	return result, nil
+}
+
+// NewConstructor は、クライアントからダイスボットのコンストラクタを作る。
+// 構築したダイスボットはクライアント（プロセス）を共有する。
+// ダイスボットの情報を取得できなかった場合はエラーを返す。
+func NewConstructor(client *Client) (dicebot.DiceBotConstructor, error) {
+	info, err := client.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() dicebot.DiceBot {
+		return &DiceBot{client: client, info: info}
+	}, nil
+}
+
+// 登録したクライアント
+var (
+	registeredClientsMu sync.Mutex
+	registeredClients   []*Client
+)
+
+// Register は、クライアントから構築したダイスボットを dicebot/list パッケージに登録する。
+// ダイスボットの情報を取得できなかった場合や、識別子が登録済みのものと重複する場合は、
+// クライアントのプロセスを終了させてエラーを返す。
+//
+// 登録したクライアントのプロセスは CloseAll で終了させる。
+func Register(client *Client) error {
+	if err := register(client, list.Add); err != nil {
+		client.Close()
+		return err
+	}
+
+	registeredClientsMu.Lock()
+	defer registeredClientsMu.Unlock()
+
+	registeredClients = append(registeredClients, client)
+
+	return nil
+}
+
+// ダイスボットを登録する関数の型
+type addFunc func(gameID string, constructor dicebot.DiceBotConstructor, aliases ...string) error
+
+// register は、クライアントから構築したダイスボットをaddを使って登録する。
+func register(client *Client, add addFunc) error {
+	constructor, err := NewConstructor(client)
+	if err != nil {
+		return err
+	}
+
+	info, _ := client.Info()
+
+	return add(info.GameID, constructor, info.Aliases...)
+}
+
+// CloseAll は、Register で登録したすべてのクライアントのプロセスを終了させる。
+func CloseAll() {
+	registeredClientsMu.Lock()
+	defer registeredClientsMu.Unlock()
+
+	for _, c := range registeredClients {
+		c.Close()
+	}
+}
+
+// LoadDir は、ディレクトリ内の実行可能なファイルをファイル名の順に外部ダイスボットとして起動し、
+// dicebot/list パッケージに登録する。返り値は登録したゲーム識別子のスライスとエラー。
+// 名前が "." で始まるファイルは無視する。
+//
+// 各プロセスの作業ディレクトリは dir となる。
+// エラーが発生した場合はその時点で読み込みを中止する。それまでに読み込んだものは登録されたままとなる。
+func LoadDir(dir string, config Config) ([]string, error) {
+	return loadDir(dir, config, Register)
+}
+
+// loadDir は、ディレクトリ内の実行可能なファイルを外部ダイスボットとして、registerを使って登録する。
+// config の Path および Dir はファイルごとに設定される。
+func loadDir(dir string, config Config, register func(c *Client) error) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if !f.Mode().IsRegular() || f.Mode().Perm()&0111 == 0 {
+			continue
+		}
+
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	gameIDs := []string{}
+	for _, name := range names {
+		c := config
+		c.Path = filepath.Join(absDir, name)
+		c.Dir = absDir
+
+		client := NewClient(c)
+		if err := register(client); err != nil {
+			client.Close()
+			return gameIDs, fmt.Errorf("%s: %s", c.Path, err)
+		}
+
+		info, _ := client.Info()
+		gameIDs = append(gameIDs, info.GameID)
+	}
+
+	return gameIDs, nil
+}
@@ -0,0 +1,448 @@
+package external
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+)
+
+// テスト用の外部ダイスボットとして起動するための環境変数
+const testPluginEnv = "GOBCDICE_EXTERNAL_TEST_PLUGIN"
+
+// 競合検出器を有効にした場合に、終了時の待ち時間をなくすための環境変数
+//
+// 待ち時間があると、CLOSE_TIMEOUT 以内に終了しなくなる。
+const goraceEnv = "GORACE=atexit_sleep_ms=0"
+
+// TestMain は、環境変数が設定されている場合、テスト用の外部ダイスボットとして動作する。
+func TestMain(m *testing.M) {
+	if gameID := os.Getenv(testPluginEnv); gameID != "" {
+		if err := Serve(&testHandler{gameID: gameID}, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// テスト用の外部ダイスボットの処理
+type testHandler struct {
+	// ゲーム識別子
+	gameID string
+}
+
+var _ Handler = (*testHandler)(nil)
+
+func (h *testHandler) Info() *Info {
+	return &Info{
+		GameID:          h.gameID,
+		GameName:        "ハウスルール",
+		Usage:           "HR{n}：nD6の合計",
+		SortKey:         "はうするうる",
+		Aliases:         []string{h.gameID + "Alias"},
+		CommandPrefixes: []string{"HR", "BASIC", "SLOW", "CRASH"},
+	}
+}
+
+func (h *testHandler) Execute(params *ExecuteParams, host *Host) (*ExecuteResult, error) {
+	switch {
+	case strings.HasPrefix(params.Command, "HR"):
+		n, err := strconv.Atoi(params.Command[2:])
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := host.RollDice(n, 6)
+		if err != nil {
+			return nil, err
+		}
+
+		sum := 0
+		for _, v := range values {
+			sum += v
+		}
+
+		success := sum >= 10
+
+		return &ExecuteResult{
+			Parts:    []string{params.Command, strconv.Itoa(sum), params.Locale},
+			Total:    &sum,
+			Success:  &success,
+			Critical: sum == 6*n,
+		}, nil
+	case params.Command == "BASIC":
+		r, err := host.ExecuteBasic("2D6>=7")
+		if err != nil {
+			return nil, err
+		}
+
+		return &ExecuteResult{Message: fmt.Sprintf("%s %v", r.Message, r.Dice)}, nil
+	case params.Command == "SLOW":
+		time.Sleep(10 * time.Second)
+		return &ExecuteResult{Message: "slow"}, nil
+	case params.Command == "CRASH":
+		os.Exit(1)
+	}
+
+	return nil, nil
+}
+
+// newTestClient はテスト用の外部ダイスボットのクライアントを返す。
+// 使用後は Close を呼び出してプロセスを終了させること。
+func newTestClient(gameID string) *Client {
+	return NewClient(Config{
+		Path:    os.Args[0],
+		Env:     []string{testPluginEnv + "=" + gameID, goraceEnv},
+		Timeout: 2 * time.Second,
+		Stderr:  os.Stderr,
+	})
+}
+
+// newTestDiceBot はテスト用の外部ダイスボットを返す。
+func newTestDiceBot(t *testing.T, c *Client) dicebot.DiceBot {
+	constructor, err := NewConstructor(c)
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	return constructor()
+}
+
+// newTestEvaluator は、指定されたダイスを順に返す評価器を返す。
+func newTestEvaluator(ds []dice.Die) *evaluator.Evaluator {
+	return evaluator.NewEvaluator(
+		roller.New(feeder.NewQueue(ds)),
+		evaluator.NewEnvironment(),
+	)
+}
+
+func TestDiceBot_Info(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	b := newTestDiceBot(t, c)
+
+	if b.GameID() != "HouseRule" {
+		t.Errorf("GameID: got %q, want %q", b.GameID(), "HouseRule")
+	}
+
+	if b.GameName() != "ハウスルール" {
+		t.Errorf("GameName: got %q, want %q", b.GameName(), "ハウスルール")
+	}
+
+	if actual := dicebot.SortKey(b); actual != "はうするうる" {
+		t.Errorf("SortKey: got %q, want %q", actual, "はうするうる")
+	}
+
+	expectedPrefixes := []string{"HR", "BASIC", "SLOW", "CRASH"}
+	if actual := dicebot.CommandPrefixes(b); !reflect.DeepEqual(actual, expectedPrefixes) {
+		t.Errorf("CommandPrefixes: got %v, want %v", actual, expectedPrefixes)
+	}
+}
+
+func TestDiceBot_ExecuteCommand(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	b := newTestDiceBot(t, c)
+
+	testcases := []struct {
+		input              string
+		dice               []dice.Die
+		expected           string
+		expectedTotal      int
+		expectedIsCritical bool
+	}{
+		{
+			input:         "HR2",
+			dice:          []dice.Die{{3, 6}, {5, 6}},
+			expected:      "HouseRule : HR2 ＞ 8 ＞ ja",
+			expectedTotal: 8,
+		},
+		{
+			input:              "HR3",
+			dice:               []dice.Die{{6, 6}, {6, 6}, {6, 6}},
+			expected:           "HouseRule : HR3 ＞ 18 ＞ ja",
+			expectedTotal:      18,
+			expectedIsCritical: true,
+		},
+		{
+			input:    "BASIC",
+			dice:     []dice.Die{{3, 6}, {4, 6}},
+			expected: "HouseRule : (2D6>=7) ＞ 7[3,4] ＞ 7 ＞ 成功 [3 4]",
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.input, func(t *testing.T) {
+			ev := newTestEvaluator(test.dice)
+
+			r, err := b.ExecuteCommand(test.input, ev)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+			}
+
+			if actual := r.Message(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+
+			if !reflect.DeepEqual(r.RolledDice, test.dice) {
+				t.Errorf("RolledDice: got %v, want %v", r.RolledDice, test.dice)
+			}
+
+			if test.expectedTotal != 0 && (r.Total == nil || *r.Total != test.expectedTotal) {
+				t.Errorf("Total: got %v, want %d", r.Total, test.expectedTotal)
+			}
+
+			if r.IsCritical != test.expectedIsCritical {
+				t.Errorf("IsCritical: got %t, want %t", r.IsCritical, test.expectedIsCritical)
+			}
+		})
+	}
+}
+
+func TestDiceBot_ExecuteUnknownCommand(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	b := newTestDiceBot(t, c)
+
+	if _, err := b.ExecuteCommand("2D6", newTestEvaluator(nil)); err == nil {
+		t.Error("未知のコマンドでエラーが発生しなかった")
+	}
+}
+
+func TestDiceBot_ExecuteCommand_DieFeederError(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	b := newTestDiceBot(t, c)
+
+	// ダイスが足りない場合は、ホストのエラーが外部ダイスボットを通じて返される
+	if _, err := b.ExecuteCommand("HR2", newTestEvaluator([]dice.Die{{1, 6}})); err == nil {
+		t.Error("ダイス供給機のエラーが返されなかった")
+	}
+
+	// プロセスは引き続き使える
+	r, err := b.ExecuteCommand("HR1", newTestEvaluator([]dice.Die{{4, 6}}))
+	if err != nil {
+		t.Fatalf("実行エラー: %s", err)
+	}
+
+	if actual := r.Message(); actual != "HouseRule : HR1 ＞ 4 ＞ ja" {
+		t.Errorf("got %q", actual)
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	c.config.Timeout = 200 * time.Millisecond
+
+	b := newTestDiceBot(t, c)
+
+	_, err := b.ExecuteCommand("SLOW", newTestEvaluator(nil))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("制限時間を超えたことが報告されなかった: %v", err)
+	}
+
+	// プロセスが起動し直される
+	c.config.Timeout = 2 * time.Second
+
+	if _, err := b.ExecuteCommand("HR1", newTestEvaluator([]dice.Die{{4, 6}})); err != nil {
+		t.Errorf("起動し直したプロセスでの実行エラー: %s", err)
+	}
+}
+
+// newScriptClient は、シェルスクリプトを外部ダイスボットとして実行するクライアントを返す。
+// 使用後は、返された関数を呼び出してプロセスを終了させ、スクリプトを削除すること。
+func newScriptClient(t *testing.T, body string, timeout time.Duration) (*Client, func()) {
+	if runtime.GOOS == "windows" {
+		t.Skip("シェルスクリプトを実行できない")
+	}
+
+	dir, err := ioutil.TempDir("", "external")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "plugin")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	c := NewClient(Config{Path: path, Timeout: timeout})
+
+	return c, func() {
+		c.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestClient_TimeoutWithChildProcess(t *testing.T) {
+	// 子プロセスが標準出力を開いたままにする
+	c, cleanup := newScriptClient(t, "sleep 30", 500*time.Millisecond)
+	defer cleanup()
+
+	start := time.Now()
+
+	_, err := c.Info()
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("制限時間を超えたことが報告されなかった: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("呼び出しが制限時間内に終わらなかった: %s", elapsed)
+	}
+
+	// 以後の呼び出しもブロックされない
+	start = time.Now()
+	c.Info()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("2回目の呼び出しが制限時間内に終わらなかった: %s", elapsed)
+	}
+}
+
+func TestClient_WriteTimeout(t *testing.T) {
+	// 標準入力を読まない
+	c, cleanup := newScriptClient(t, "exec sleep 30", 500*time.Millisecond)
+	defer cleanup()
+
+	// パイプのバッファより大きな要求を送る
+	input := strings.Repeat("A", 1<<20)
+
+	start := time.Now()
+
+	_, err := c.Execute(input, newTestEvaluator(nil))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("制限時間を超えたことが報告されなかった: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("呼び出しが制限時間内に終わらなかった: %s", elapsed)
+	}
+}
+
+func TestClient_ProcessExited(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	b := newTestDiceBot(t, c)
+
+	if _, err := b.ExecuteCommand("CRASH", newTestEvaluator(nil)); err == nil {
+		t.Fatal("プロセスの異常終了が報告されなかった")
+	}
+
+	// プロセスが起動し直される
+	if _, err := b.ExecuteCommand("HR1", newTestEvaluator([]dice.Die{{4, 6}})); err != nil {
+		t.Errorf("起動し直したプロセスでの実行エラー: %s", err)
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	c := newTestClient("HouseRule")
+	defer c.Close()
+
+	b := newTestDiceBot(t, c)
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("終了エラー: %s", err)
+	}
+
+	if c.proc != nil {
+		t.Fatal("プロセスが残っている")
+	}
+
+	// 終了後の呼び出しではプロセスが起動し直される
+	if _, err := b.ExecuteCommand("HR1", newTestEvaluator([]dice.Die{{4, 6}})); err != nil {
+		t.Errorf("起動し直したプロセスでの実行エラー: %s", err)
+	}
+}
+
+func TestClient_StartError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "external")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewClient(Config{Path: filepath.Join(dir, "not-found")})
+
+	if _, err := c.Info(); err == nil {
+		t.Error("存在しないファイルでエラーが発生しなかった")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("シェルスクリプトを実行できない")
+	}
+
+	dir, err := ioutil.TempDir("", "external")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeScript := func(name string, gameID string, perm os.FileMode) {
+		script := fmt.Sprintf("#!/bin/sh\n%s=%s exec %q\n", testPluginEnv, gameID, os.Args[0])
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(script), perm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeScript("b-bot", "BBot", 0755)
+	writeScript("a-bot", "ABot", 0755)
+	writeScript("not-executable", "NotExecutable", 0644)
+	writeScript(".hidden", "Hidden", 0755)
+
+	registered := map[string][]string{}
+	clients := []*Client{}
+	register := func(c *Client) error {
+		clients = append(clients, c)
+
+		return register(c, func(gameID string, _ dicebot.DiceBotConstructor, aliases ...string) error {
+			registered[gameID] = aliases
+			return nil
+		})
+	}
+
+	defer func() {
+		for _, c := range clients {
+			c.Close()
+		}
+	}()
+
+	gameIDs, err := loadDir(dir, Config{Env: []string{goraceEnv}}, register)
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	expected := []string{"ABot", "BBot"}
+	if !reflect.DeepEqual(gameIDs, expected) {
+		t.Errorf("got %v, want %v", gameIDs, expected)
+	}
+
+	if !reflect.DeepEqual(registered["ABot"], []string{"ABotAlias"}) {
+		t.Errorf("別名: got %v", registered["ABot"])
+	}
+}
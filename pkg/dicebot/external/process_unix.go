@@ -0,0 +1,26 @@
+//go:build !windows
+// +build !windows
+
+package external
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup は、プロセスを独自のプロセスグループで起動するように設定する。
+// 外部ダイスボットが起動した子プロセスもまとめて強制終了できるようにするため。
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup は、プロセスグループ全体を強制終了させる。
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	// プロセスグループの識別子はプロセスの識別子と等しい
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	cmd.Process.Kill()
+}
@@ -0,0 +1,19 @@
+package external
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup は何もしない。Windowsではプロセスグループを使わない。
+func setProcessGroup(_ *exec.Cmd) {
+}
+
+// killProcessGroup はプロセスを強制終了させる。
+// Windowsでは子プロセスまでは終了させない。
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	cmd.Process.Kill()
+}
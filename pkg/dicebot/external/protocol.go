@@ -0,0 +1,243 @@
+package external
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// プロトコルのメソッド名
+const (
+	// ダイスボットの情報を取得する（ホスト → ダイスボット）
+	METHOD_INFO = "info"
+	// コマンドを実行する（ホスト → ダイスボット）
+	METHOD_EXECUTE = "execute"
+	// ダイスを振る（ダイスボット → ホスト）
+	METHOD_ROLL_DICE = "rollDice"
+	// BCDiceの基本コマンドを実行する（ダイスボット → ホスト）
+	METHOD_EXECUTE_BASIC = "executeBasic"
+)
+
+// JSON-RPCのバージョン
+const jsonRPCVersion = "2.0"
+
+// JSON-RPCのエラーコード
+const (
+	// メソッドが存在しない
+	ERROR_METHOD_NOT_FOUND = -32601
+	// 引数が不正
+	ERROR_INVALID_PARAMS = -32602
+	// 処理中のエラー
+	ERROR_INTERNAL = -32603
+)
+
+// JSON-RPCのメッセージ。
+//
+// 要求（Method が空でない）と応答（Method が空）の両方を表す。
+type message struct {
+	// JSON-RPCのバージョン（"2.0"）
+	JSONRPC string `json:"jsonrpc"`
+	// 要求の識別子
+	ID int64 `json:"id"`
+	// メソッド名（要求の場合）
+	Method string `json:"method,omitempty"`
+	// 引数（要求の場合）
+	Params json.RawMessage `json:"params,omitempty"`
+	// 結果（成功した応答の場合）
+	Result json.RawMessage `json:"result,omitempty"`
+	// エラー（失敗した応答の場合）
+	Error *RPCError `json:"error,omitempty"`
+}
+
+// isRequest は、メッセージが要求かどうかを返す。
+func (m *message) isRequest() bool {
+	return m.Method != ""
+}
+
+// JSON-RPCのエラー。
+type RPCError struct {
+	// エラーコード
+	Code int `json:"code"`
+	// エラーメッセージ
+	Message string `json:"message"`
+}
+
+// Error はエラーメッセージを返す。
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// ダイスボットの情報（info の結果）。
+type Info struct {
+	// ゲーム識別子
+	GameID string `json:"gameId"`
+	// ゲームシステム名
+	GameName string `json:"gameName"`
+	// 使用法の説明
+	Usage string `json:"usage"`
+	// 並べ替えのキー（省略した場合はゲーム識別子を小文字にしたもの）
+	SortKey string `json:"sortKey,omitempty"`
+	// ゲーム識別子の別名
+	Aliases []string `json:"aliases,omitempty"`
+	// ゲームシステム固有のコマンドの接頭辞
+	CommandPrefixes []string `json:"prefixes,omitempty"`
+}
+
+// execute の引数。
+type ExecuteParams struct {
+	// コマンド
+	Command string `json:"command"`
+	// メッセージのロケール
+	Locale string `json:"locale"`
+}
+
+// コマンドの実行結果（execute および executeBasic の結果）。
+//
+// execute の結果が null の場合は、ゲームシステム固有のコマンドではないことを表す。
+type ExecuteResult struct {
+	// メッセージの部分
+	Parts []string `json:"parts,omitempty"`
+	// メッセージ（Parts を省略した場合に使われる）
+	Message string `json:"message,omitempty"`
+	// 最終的な数値
+	Total *int `json:"total,omitempty"`
+	// 成功判定の結果（判定しない場合は省略する）
+	Success *bool `json:"success,omitempty"`
+	// クリティカルかどうか
+	Critical bool `json:"critical,omitempty"`
+	// ファンブルかどうか
+	Fumble bool `json:"fumble,omitempty"`
+	// スペシャルかどうか
+	Special bool `json:"special,omitempty"`
+	// 振られたダイスの出目（executeBasic の結果のみ）
+	Dice []int `json:"dice,omitempty"`
+}
+
+// rollDice の引数。
+type RollDiceParams struct {
+	// 振るダイスの数
+	Num int `json:"num"`
+	// ダイスの面の数
+	Sides int `json:"sides"`
+}
+
+// rollDice の結果。
+type RollDiceResult struct {
+	// 出目
+	Values []int `json:"values"`
+}
+
+// executeBasic の引数。
+type ExecuteBasicParams struct {
+	// BCDiceの基本コマンド
+	Command string `json:"command"`
+}
+
+// 改行区切りのJSON-RPCのメッセージを送受信する接続。
+type conn struct {
+	// 書き込みの排他制御
+	mu sync.Mutex
+	// 書き込み先
+	w io.Writer
+	// 読み込み元
+	r *bufio.Reader
+}
+
+// 1行の最大の長さ
+const maxLineLength = 1 << 20
+
+// newConn は新しい接続を返す。
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		w: w,
+		r: bufio.NewReader(r),
+	}
+}
+
+// write はメッセージを1行のJSONとして書き込む。
+func (c *conn) write(m *message) error {
+	m.JSONRPC = jsonRPCVersion
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err = c.w.Write(append(b, '\n'))
+	return err
+}
+
+// read は1行を読み込み、メッセージとして返す。
+// 空行は読み飛ばす。
+func (c *conn) read() (*message, error) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(line) == 0 {
+			continue
+		}
+
+		m := &message{}
+		if err := json.Unmarshal(line, m); err != nil {
+			return nil, fmt.Errorf("invalid message: %s", err)
+		}
+
+		return m, nil
+	}
+}
+
+// readLine は1行を読み込み、末尾の改行を取り除いて返す。
+// 行が長すぎる場合はエラーを返す。
+func (c *conn) readLine() ([]byte, error) {
+	line := []byte{}
+
+	for {
+		chunk, isPrefix, err := c.r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+
+		line = append(line, chunk...)
+		if len(line) > maxLineLength {
+			return nil, fmt.Errorf("message too long")
+		}
+
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// respond は要求に対する応答を書き込む。
+// errがnilでない場合はエラーの応答となる。
+func (c *conn) respond(id int64, result interface{}, err error) error {
+	m := &message{ID: id}
+
+	if err != nil {
+		rpcErr, ok := err.(*RPCError)
+		if !ok {
+			rpcErr = &RPCError{Code: ERROR_INTERNAL, Message: err.Error()}
+		}
+
+		m.Error = rpcErr
+
+		return c.write(m)
+	}
+
+	b, marshalErr := json.Marshal(result)
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	m.Result = b
+
+	return c.write(m)
+}
@@ -0,0 +1,134 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// 外部ダイスボットの処理のインターフェース。
+// Serve に渡して使う。
+type Handler interface {
+	// Info はダイスボットの情報を返す。
+	Info() *Info
+	// Execute はコマンドを実行する。
+	// ゲームシステム固有のコマンドではない場合は、結果としてnilを返す。
+	Execute(params *ExecuteParams, host *Host) (*ExecuteResult, error)
+}
+
+// コマンドの実行中にホストへ要求を送るための構造体。
+type Host struct {
+	// 接続
+	conn *conn
+	// 次の要求の識別子
+	nextID int64
+}
+
+// RollDice は、ホストのダイス供給機を使ってsides面のダイスをnum個振り、出目を返す。
+func (h *Host) RollDice(num int, sides int) ([]int, error) {
+	raw, err := h.call(METHOD_ROLL_DICE, &RollDiceParams{Num: num, Sides: sides})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RollDiceResult{}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return nil, fmt.Errorf("%s: invalid result: %s", METHOD_ROLL_DICE, err)
+	}
+
+	return result.Values, nil
+}
+
+// ExecuteBasic は、ホストでBCDiceの基本コマンドを実行し、結果を返す。
+func (h *Host) ExecuteBasic(c string) (*ExecuteResult, error) {
+	raw, err := h.call(METHOD_EXECUTE_BASIC, &ExecuteBasicParams{Command: c})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExecuteResult{}
+	if err := json.Unmarshal(raw, result); err != nil {
+		return nil, fmt.Errorf("%s: invalid result: %s", METHOD_EXECUTE_BASIC, err)
+	}
+
+	return result, nil
+}
+
+// call はホストのメソッドを呼び出し、結果を返す。
+func (h *Host) call(method string, params interface{}) (json.RawMessage, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	h.nextID++
+	id := h.nextID
+
+	if err := h.conn.write(&message{ID: id, Method: method, Params: b}); err != nil {
+		return nil, err
+	}
+
+	for {
+		m, err := h.conn.read()
+		if err != nil {
+			return nil, err
+		}
+
+		if m.isRequest() || m.ID != id {
+			return nil, fmt.Errorf("%s: unexpected message", method)
+		}
+
+		if m.Error != nil {
+			return nil, m.Error
+		}
+
+		return m.Result, nil
+	}
+}
+
+// Serve は、外部ダイスボットとしてホストからの要求を処理する。
+// 通常は r に標準入力、w に標準出力を指定する。
+//
+// r が終端に達すると nil を返す。
+func Serve(handler Handler, r io.Reader, w io.Writer) error {
+	c := newConn(r, w)
+
+	for {
+		m, err := c.read()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !m.isRequest() {
+			continue
+		}
+
+		result, handleErr := handle(handler, c, m)
+		if err := c.respond(m.ID, result, handleErr); err != nil {
+			return err
+		}
+	}
+}
+
+// handle はホストからの要求を処理する。
+func handle(handler Handler, c *conn, m *message) (interface{}, error) {
+	switch m.Method {
+	case METHOD_INFO:
+		return handler.Info(), nil
+	case METHOD_EXECUTE:
+		params := &ExecuteParams{}
+		if err := json.Unmarshal(m.Params, params); err != nil {
+			return nil, &RPCError{Code: ERROR_INVALID_PARAMS, Message: err.Error()}
+		}
+
+		host := &Host{conn: c}
+
+		return handler.Execute(params, host)
+	}
+
+	return nil, &RPCError{Code: ERROR_METHOD_NOT_FOUND, Message: "method not found: " + m.Method}
+}
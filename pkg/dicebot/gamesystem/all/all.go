@@ -0,0 +1,15 @@
+/*
+すべてのゲームシステムのダイスボットを登録するためのパッケージ。
+
+このパッケージをインポートすると、各ゲームシステムのパッケージの init 関数が実行され、
+ダイスボットが dicebot/list パッケージに登録される。
+
+	import _ "github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/all"
+
+ゲームシステムを追加した場合は、このパッケージにインポートを追加する。
+*/
+package all
+
+import (
+	_ "github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/basic"
+)
@@ -7,18 +7,29 @@ import (
 	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/command"
 	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
 	"github.com/raa0121/GoBCDice/pkg/dicebot"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/list"
 )
 
 const (
 	// 基本的なダイスボットのゲーム識別子
-	GAME_ID = "DiceBot"
+	GAME_ID = list.BASIC_GAME_ID
+	// 並べ替えのキー（先頭に置かれるように記号から始める）
+	SORT_KEY = "*たいすほつと"
 )
 
+func init() {
+	list.Register(GAME_ID, New)
+}
+
 // 基本的なダイスボット。
 type Basic struct {
 }
 
+var _ dicebot.LocalizedDiceBot = (*Basic)(nil)
+var _ dicebot.MetadataDiceBot = (*Basic)(nil)
+
 // New は新しいダイスボットを構築する。
 func New() dicebot.DiceBot {
 	return &Basic{}
@@ -26,33 +37,43 @@ func New() dicebot.DiceBot {
 
 // GameID はゲーム識別子を返す。
 func (b *Basic) GameID() string {
-	return "DiceBot"
+	return GAME_ID
 }
 
 // GameName はゲームシステム名を返す。
 func (b *Basic) GameName() string {
-	return "ダイスボット (指定無し)"
+	return b.LocalizedGameName(locale.DEFAULT)
 }
 
 // Usage はダイスボットの使用法の説明を返す。
 func (b *Basic) Usage() string {
-	return `【ダイスボット】チャットにダイス用の文字を入力するとダイスロールが可能
-入力例）２ｄ６＋１　攻撃！
-出力例）2d6+1　攻撃！
-　　　　  diceBot: (2d6) → 7
-上記のようにダイス文字の後ろに空白を入れて発言する事も可能。
-以下、使用例
-　3D6+1>=9 ：3d6+1で目標値9以上かの判定
-　1D100<=50 ：D100で50％目標の下方ロールの例
-　3U6[5] ：3d6のダイス目が5以上の場合に振り足しして合計する(上方無限)
-　3B6 ：3d6のダイス目をバラバラのまま出力する（合計しない）
-　10B6>=4 ：10d6を振り4以上のダイス目の個数を数える
-　(8/2)D(4+6)<=(5*3)：個数・ダイス・達成値には四則演算も使用可能
-　C(10-4*3/2+2)：C(計算式）で計算だけの実行も可能
-　choice[a,b,c]：列挙した要素から一つを選択表示。ランダム攻撃対象決定などに
-　S3d6 ： 各コマンドの先頭に「S」を付けると他人結果の見えないシークレットロール
-　3d6/2 ： ダイス出目を割り算（切り捨て）。切り上げは /2U、四捨五入は /2R。
-　D66 ： D66ダイス。順序はゲームに依存。D66N：そのまま、D66S：昇順。`
+	return b.LocalizedUsage(locale.DEFAULT)
+}
+
+// LocalizedGameName は、指定されたロケールのゲームシステム名を返す。
+func (b *Basic) LocalizedGameName(l locale.Locale) string {
+	return l.Text(locale.MSG_BASIC_GAME_NAME)
+}
+
+// LocalizedUsage は、指定されたロケールのダイスボットの使用法の説明を返す。
+func (b *Basic) LocalizedUsage(l locale.Locale) string {
+	return l.Text(locale.MSG_BASIC_USAGE)
+}
+
+// SortKey は並べ替えのキーを返す。
+func (b *Basic) SortKey() string {
+	return SORT_KEY
+}
+
+// HelpMessage は、指定されたロケールのヘルプメッセージを返す。
+func (b *Basic) HelpMessage(l locale.Locale) string {
+	return b.LocalizedUsage(l)
+}
+
+// CommandPrefixes は、ゲームシステム固有のコマンドの接頭辞のスライスを返す。
+// 基本のダイスボットには特別なコマンドが存在しないため、空のスライスを返す。
+func (b *Basic) CommandPrefixes() []string {
+	return []string{}
 }
 
 // ExecuteCommand は指定されたコマンドを実行する。
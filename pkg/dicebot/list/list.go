@@ -2,51 +2,405 @@
 ダイスボットの一覧を管理するパッケージ。
 
 このパッケージを使用することで、指定したゲーム名のダイスボットを取得することができるようになる。
+
+各ゲームシステムのパッケージは、init 関数の中で Register を呼び出してダイスボットを登録する。
+
+	func init() {
+		list.Register(GAME_ID, New, "SwordWorld2_0")
+	}
+
+登録されたダイスボットを利用するには、そのパッケージをインポートする必要がある。
+すべてのゲームシステムを登録するには、gamesystem/all パッケージをインポートする。
+
+	import _ "github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/all"
+
+識別子の照合では大文字小文字を区別しない。
 */
 package list
 
 import (
 	"fmt"
-	"github.com/raa0121/GoBCDice/pkg/dicebot"
-	"github.com/raa0121/GoBCDice/pkg/dicebot/gamesystem/basic"
 	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+)
+
+const (
+	// 基本的なダイスボットのゲーム識別子
+	BASIC_GAME_ID = "DiceBot"
 )
 
+// 既定の登録簿
+var defaultRegistry = newRegistry()
+
+// Register は、ダイスボットのコンストラクタをゲーム識別子で登録する。
+// aliasesには、旧式の識別子などの別名を指定できる。
+//
+// 各ゲームシステムのパッケージの init 関数から呼び出すことを想定している。
+// 識別子または別名が（大文字小文字を区別せずに）すでに登録されているものと重複する場合は panic する。
+func Register(
+	gameID string,
+	constructor dicebot.DiceBotConstructor,
+	aliases ...string,
+) {
+	if err := defaultRegistry.register(gameID, constructor, aliases...); err != nil {
+		panic(err)
+	}
+}
+
+// Add は、Register と同様にダイスボットのコンストラクタを登録する。
+// 識別子または別名が重複する場合は、panic せずにエラーを返す。
+//
+// 設定ファイルなどから実行時に読み込むダイスボットの登録に使う。
+func Add(
+	gameID string,
+	constructor dicebot.DiceBotConstructor,
+	aliases ...string,
+) error {
+	return defaultRegistry.register(gameID, constructor, aliases...)
+}
+
 // Find は指定された識別子を持つゲームシステムのダイスボットのコンストラクタを返す。
+//
+// 識別子の照合では大文字小文字を区別しない。別名で指定することもできる。
 // ゲームシステムが見つからなかった場合はエラーを返す。
 func Find(gameID string) (dicebot.DiceBotConstructor, error) {
-	if gameID == basic.GAME_ID {
-		return basic.New, nil
+	return defaultRegistry.find(gameID)
+}
+
+// CanonicalGameID は、指定された識別子または別名に対応する正式なゲーム識別子を返す。
+// ゲームシステムが見つからなかった場合はエラーを返す。
+func CanonicalGameID(gameID string) (string, error) {
+	return defaultRegistry.canonicalGameID(gameID)
+}
+
+// AvailableGameIDs は利用可能なゲームシステムの識別子のスライスを返す。
+//
+// 識別子はダイスボットの並べ替えのキー（dicebot.SortKey）の順に並べられる。
+// キーが等しい場合は識別子の辞書順となる。別名は含まれない。
+// includeBasicDiceBotがtrueの場合、基本的なダイスボットの識別子が先頭に置かれる。
+// falseの場合、基本的なダイスボットの識別子は含まれない。
+func AvailableGameIDs(includeBasicDiceBot bool) []string {
+	return defaultRegistry.availableGameIDs(includeBasicDiceBot)
+}
+
+// Search は、検索語に一致するゲームシステムの識別子のスライスを返す。
+//
+// 識別子、別名およびゲームシステム名を対象として、大文字小文字と記号・空白を無視して照合する。
+// 結果は一致の度合いが高い順（完全一致、前方一致、部分一致、文字の並びの一致の順）に並べられ、
+// 同じ度合いのものは識別子の辞書順に並べられる。
+func Search(query string) []string {
+	return defaultRegistry.search(query)
+}
+
+// 登録されたダイスボットの情報
+type entry struct {
+	// ゲーム識別子
+	gameID string
+	// ダイスボットのコンストラクタ
+	constructor dicebot.DiceBotConstructor
+	// 別名
+	aliases []string
+	// ゲームシステム名（最初に必要になったときに取得する）
+	gameName string
+	// 並べ替えのキー（最初に必要になったときに取得する）
+	sortKey string
+	// ゲームシステム名と並べ替えのキーを一度だけ取得する
+	loadInfoOnce sync.Once
+}
+
+// ダイスボットの登録簿
+type registry struct {
+	// 登録簿へのアクセスを保護する
+	mu sync.RWMutex
+	// 正式なゲーム識別子と登録情報との対応
+	entries map[string]*entry
+	// 正規化された識別子または別名と登録情報との対応
+	keyToEntry map[string]*entry
+}
+
+// newRegistry は新しい登録簿を返す。
+func newRegistry() *registry {
+	return &registry{
+		entries:    map[string]*entry{},
+		keyToEntry: map[string]*entry{},
+	}
+}
+
+// lookupKey は識別子を照合用のキーに変換する。
+func lookupKey(gameID string) string {
+	return strings.ToLower(strings.TrimSpace(gameID))
+}
+
+// register はダイスボットのコンストラクタを登録する。
+// 識別子または別名が重複する場合はエラーを返す。
+func (r *registry) register(
+	gameID string,
+	constructor dicebot.DiceBotConstructor,
+	aliases ...string,
+) error {
+	if lookupKey(gameID) == "" {
+		return fmt.Errorf("dicebot list: empty game ID")
 	}
 
-	constructor, found := gameIDToDiceBotConstructor[gameID]
+	if constructor == nil {
+		return fmt.Errorf("dicebot list: nil constructor for %s", gameID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := &entry{
+		gameID:      gameID,
+		constructor: constructor,
+		aliases:     aliases,
+	}
+
+	keys := make([]string, 0, len(aliases)+1)
+	seen := map[string]bool{}
+	for _, id := range append([]string{gameID}, aliases...) {
+		key := lookupKey(id)
+		if key == "" {
+			return fmt.Errorf("dicebot list: empty alias for %s", gameID)
+		}
+
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if existing, found := r.keyToEntry[key]; found {
+			return fmt.Errorf(
+				"dicebot list: %s of %s is already registered by %s",
+				id, gameID, existing.gameID,
+			)
+		}
+
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		r.keyToEntry[key] = e
+	}
+	r.entries[gameID] = e
+
+	return nil
+}
+
+// lookup は識別子または別名に対応する登録情報を返す。
+func (r *registry) lookup(gameID string) (*entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, found := r.keyToEntry[lookupKey(gameID)]
 	if !found {
 		return nil, fmt.Errorf("unknown game system: %s", gameID)
 	}
 
-	return constructor, nil
+	return e, nil
 }
 
-// AvailableGameIDs は利用可能なゲームシステムの識別子のスライスを返す。
-func AvailableGameIDs(includeBasicDiceBot bool) []string {
-	gameIDs := make([]string, 0, len(gameIDToDiceBotConstructor))
-	for k := range gameIDToDiceBotConstructor {
-		gameIDs = append(gameIDs, k)
+// find は識別子または別名に対応するコンストラクタを返す。
+func (r *registry) find(gameID string) (dicebot.DiceBotConstructor, error) {
+	e, err := r.lookup(gameID)
+	if err != nil {
+		return nil, err
 	}
 
-	gameIDsStrSlice := sort.StringSlice(gameIDs)
-	sort.Sort(gameIDsStrSlice)
+	return e.constructor, nil
+}
+
+// canonicalGameID は識別子または別名に対応する正式なゲーム識別子を返す。
+func (r *registry) canonicalGameID(gameID string) (string, error) {
+	e, err := r.lookup(gameID)
+	if err != nil {
+		return "", err
+	}
+
+	return e.gameID, nil
+}
+
+// allEntries は登録情報のスライスを返す。
+//
+// ダイスボットのコンストラクタは登録簿を参照する可能性があるため、
+// 登録情報のゲームシステム名などは、このスライスを得た後にロックの外で取得する。
+func (r *registry) allEntries() []*entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+// availableGameIDs は登録されているゲーム識別子のスライスを返す。
+func (r *registry) availableGameIDs(includeBasicDiceBot bool) []string {
+	all := r.allEntries()
 
-	if !includeBasicDiceBot {
+	entries := make([]*entry, 0, len(all))
+	basicFound := false
+
+	for _, e := range all {
+		if e.gameID == BASIC_GAME_ID {
+			basicFound = true
+			continue
+		}
+
+		e.loadInfo()
+		entries = append(entries, e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].sortKey != entries[j].sortKey {
+			return entries[i].sortKey < entries[j].sortKey
+		}
+
+		return entries[i].gameID < entries[j].gameID
+	})
+
+	gameIDs := make([]string, 0, len(entries)+1)
+	for _, e := range entries {
+		gameIDs = append(gameIDs, e.gameID)
+	}
+
+	if !includeBasicDiceBot || !basicFound {
 		return gameIDs
 	}
 
-	gameIDsWithBasicDiceBot := make([]string, 1, len(gameIDs)+1)
-	gameIDsWithBasicDiceBot[0] = basic.GAME_ID
-	gameIDsWithBasicDiceBot = append(gameIDsWithBasicDiceBot, gameIDs...)
+	return append([]string{BASIC_GAME_ID}, gameIDs...)
+}
+
+// 検索の一致の度合い
+type matchRank int
+
+const (
+	// 一致しない
+	matchNone matchRank = iota
+	// 文字の並びが一致する
+	matchSubsequence
+	// 部分一致
+	matchSubstring
+	// 前方一致
+	matchPrefix
+	// 完全一致
+	matchExact
+)
+
+// search は検索語に一致するゲーム識別子のスライスを返す。
+func (r *registry) search(query string) []string {
+	q := searchKey(query)
+	if q == "" {
+		return []string{}
+	}
+
+	type hit struct {
+		gameID string
+		rank   matchRank
+	}
+
+	hits := []hit{}
+	for _, e := range r.allEntries() {
+		best := matchNone
+		for _, target := range e.searchTargets() {
+			if rank := matchSearchKey(searchKey(target), q); rank > best {
+				best = rank
+			}
+		}
+
+		if best != matchNone {
+			hits = append(hits, hit{gameID: e.gameID, rank: best})
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].rank != hits[j].rank {
+			return hits[i].rank > hits[j].rank
+		}
+
+		return hits[i].gameID < hits[j].gameID
+	})
+
+	gameIDs := make([]string, 0, len(hits))
+	for _, h := range hits {
+		gameIDs = append(gameIDs, h.gameID)
+	}
+
+	return gameIDs
+}
 
-	return gameIDsWithBasicDiceBot
+// loadInfo は、ダイスボットを構築してゲームシステム名と並べ替えのキーを取得する。
+// 取得済みの場合は何もしない。複数のゴルーチンから同時に呼び出してもよい。
+func (e *entry) loadInfo() {
+	e.loadInfoOnce.Do(func() {
+		b := e.constructor()
+		e.gameName = b.GameName()
+		e.sortKey = dicebot.SortKey(b)
+	})
 }
 
-// ゲーム識別子とダイスボットのコンストラクタとの対応
-var gameIDToDiceBotConstructor = map[string]dicebot.DiceBotConstructor{}
+// searchTargets は検索の対象となる文字列のスライスを返す。
+func (e *entry) searchTargets() []string {
+	e.loadInfo()
+
+	targets := make([]string, 0, len(e.aliases)+2)
+	targets = append(targets, e.gameID)
+	targets = append(targets, e.aliases...)
+	targets = append(targets, e.gameName)
+
+	return targets
+}
+
+// searchKey は、文字列から記号と空白を取り除き、小文字に変換した検索用のキーを返す。
+func searchKey(s string) string {
+	var b strings.Builder
+
+	for _, c := range s {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			b.WriteRune(unicode.ToLower(c))
+		}
+	}
+
+	return b.String()
+}
+
+// matchSearchKey は、対象のキーと検索語のキーとの一致の度合いを返す。
+func matchSearchKey(target string, query string) matchRank {
+	switch {
+	case target == query:
+		return matchExact
+	case strings.HasPrefix(target, query):
+		return matchPrefix
+	case strings.Contains(target, query):
+		return matchSubstring
+	case isSubsequence(target, query):
+		return matchSubsequence
+	default:
+		return matchNone
+	}
+}
+
+// isSubsequence は、queryの文字がtargetの中に同じ順で現れるかどうかを返す。
+func isSubsequence(target string, query string) bool {
+	t := []rune(target)
+	i := 0
+
+	for _, c := range query {
+		for i < len(t) && t[i] != c {
+			i++
+		}
+
+		if i >= len(t) {
+			return false
+		}
+
+		i++
+	}
+
+	return true
+}
@@ -0,0 +1,318 @@
+package list
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+)
+
+// テスト用のダイスボット
+type fakeDiceBot struct {
+	gameID   string
+	gameName string
+}
+
+func (b *fakeDiceBot) GameID() string   { return b.gameID }
+func (b *fakeDiceBot) GameName() string { return b.gameName }
+func (b *fakeDiceBot) Usage() string    { return "" }
+
+func (b *fakeDiceBot) ExecuteCommand(
+	_ string,
+	_ *evaluator.Evaluator,
+) (*command.Result, error) {
+	return nil, nil
+}
+
+// 並べ替えのキーを持つテスト用のダイスボット
+type fakeMetadataDiceBot struct {
+	fakeDiceBot
+	sortKey string
+}
+
+func (b *fakeMetadataDiceBot) SortKey() string                    { return b.sortKey }
+func (b *fakeMetadataDiceBot) HelpMessage(_ locale.Locale) string { return "" }
+func (b *fakeMetadataDiceBot) CommandPrefixes() []string          { return nil }
+
+// fakeConstructor はテスト用のダイスボットのコンストラクタを返す。
+func fakeConstructor(gameID string, gameName string) dicebot.DiceBotConstructor {
+	return func() dicebot.DiceBot {
+		return &fakeDiceBot{gameID: gameID, gameName: gameName}
+	}
+}
+
+// newTestRegistry はテスト用のダイスボットを登録した登録簿を返す。
+func newTestRegistry(t *testing.T) *registry {
+	r := newRegistry()
+
+	bots := []struct {
+		gameID   string
+		gameName string
+		aliases  []string
+	}{
+		{BASIC_GAME_ID, "ダイスボット (指定無し)", nil},
+		{"SwordWorld2.0", "ソード・ワールド2.0", []string{"SwordWorld2_0"}},
+		{"SwordWorld", "ソード・ワールドRPG", nil},
+		{"Cthulhu", "クトゥルフ神話TRPG", []string{"Call of Cthulhu"}},
+		{"DoubleCross", "ダブルクロス2nd,3rd", nil},
+	}
+
+	for _, b := range bots {
+		err := r.register(b.gameID, fakeConstructor(b.gameID, b.gameName), b.aliases...)
+		if err != nil {
+			t.Fatalf("登録エラー: %s", err)
+		}
+	}
+
+	return r
+}
+
+func TestRegistry_Find(t *testing.T) {
+	r := newTestRegistry(t)
+
+	testcases := []struct {
+		input    string
+		expected string
+	}{
+		{"SwordWorld2.0", "SwordWorld2.0"},
+		{"swordworld2.0", "SwordWorld2.0"},
+		{"SwordWorld2_0", "SwordWorld2.0"},
+		{"SWORDWORLD2_0", "SwordWorld2.0"},
+		{"SwordWorld", "SwordWorld"},
+		{"call of cthulhu", "Cthulhu"},
+		{" DiceBot ", BASIC_GAME_ID},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.input, func(t *testing.T) {
+			constructor, err := r.find(test.input)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+				return
+			}
+
+			actual := constructor().GameID()
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+
+			canonical, err := r.canonicalGameID(test.input)
+			if err != nil {
+				t.Fatalf("got err: %s", err)
+				return
+			}
+
+			if canonical != test.expected {
+				t.Errorf("canonicalGameID: got %q, want %q", canonical, test.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry_FindUnknown(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if _, err := r.find("SwordWorld2"); err == nil {
+		t.Error("未知のゲームシステムが見つかってしまった")
+	}
+}
+
+func TestRegistry_RegisterDuplicate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		gameID  string
+		aliases []string
+	}{
+		{"SameID", "SwordWorld2.0", nil},
+		{"CaseInsensitiveID", "swordworld2.0", nil},
+		{"IDConflictsWithAlias", "SwordWorld2_0", nil},
+		{"AliasConflictsWithID", "SW2", []string{"Cthulhu"}},
+		{"AliasConflictsWithAlias", "SW2", []string{"swordworld2_0"}},
+		{"EmptyID", "", nil},
+		{"EmptyAlias", "SW2", []string{" "}},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			r := newTestRegistry(t)
+
+			err := r.register(test.gameID, fakeConstructor(test.gameID, ""), test.aliases...)
+			if err == nil {
+				t.Fatal("重複した識別子を登録できてしまった")
+			}
+
+			// 失敗した登録の別名が残っていないことを確認する
+			if _, findErr := r.find("SW2"); findErr == nil {
+				t.Error("失敗した登録が残っている")
+			}
+		})
+	}
+}
+
+func TestRegister_PanicsOnDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("panicしなかった")
+		}
+	}()
+
+	original := defaultRegistry
+	defer func() { defaultRegistry = original }()
+
+	defaultRegistry = newRegistry()
+
+	Register("Test", fakeConstructor("Test", ""))
+	Register("test", fakeConstructor("test", ""))
+}
+
+func TestRegistry_AvailableGameIDs(t *testing.T) {
+	r := newTestRegistry(t)
+
+	testcases := []struct {
+		includeBasicDiceBot bool
+		expected            []string
+	}{
+		{
+			includeBasicDiceBot: true,
+			expected: []string{
+				BASIC_GAME_ID, "Cthulhu", "DoubleCross", "SwordWorld", "SwordWorld2.0",
+			},
+		},
+		{
+			includeBasicDiceBot: false,
+			expected: []string{
+				"Cthulhu", "DoubleCross", "SwordWorld", "SwordWorld2.0",
+			},
+		},
+	}
+
+	for _, test := range testcases {
+		actual := r.availableGameIDs(test.includeBasicDiceBot)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("includeBasicDiceBot=%v: got %v, want %v",
+				test.includeBasicDiceBot, actual, test.expected)
+		}
+	}
+}
+
+func TestRegistry_AvailableGameIDs_SortKey(t *testing.T) {
+	r := newRegistry()
+
+	bots := []struct {
+		gameID  string
+		sortKey string
+	}{
+		{"DoubleCross", "たふるくろす"},
+		{"SwordWorld2.0", "そおとわあると2.0"},
+		{"Cthulhu", "くとうるふしんわTRPG"},
+		{"Cthulhu7th", "くとうるふしんわTRPG"},
+	}
+
+	for _, b := range bots {
+		b := b
+		constructor := func() dicebot.DiceBot {
+			return &fakeMetadataDiceBot{
+				fakeDiceBot: fakeDiceBot{gameID: b.gameID},
+				sortKey:     b.sortKey,
+			}
+		}
+
+		if err := r.register(b.gameID, constructor); err != nil {
+			t.Fatalf("登録エラー: %s", err)
+		}
+	}
+
+	// 並べ替えのキーを持たないダイスボットは、小文字にしたゲーム識別子をキーとする
+	if err := r.register("Alpha", fakeConstructor("Alpha", "")); err != nil {
+		t.Fatalf("登録エラー: %s", err)
+	}
+
+	expected := []string{"Alpha", "Cthulhu", "Cthulhu7th", "SwordWorld2.0", "DoubleCross"}
+	if actual := r.availableGameIDs(false); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestRegistry_Search(t *testing.T) {
+	r := newTestRegistry(t)
+
+	testcases := []struct {
+		query    string
+		expected []string
+	}{
+		{"swordworld", []string{"SwordWorld", "SwordWorld2.0"}},
+		{"Sword World 2.0", []string{"SwordWorld2.0"}},
+		{"sw20", []string{"SwordWorld2.0"}},
+		{"ソード・ワールド", []string{"SwordWorld", "SwordWorld2.0"}},
+		{"クトゥルフ", []string{"Cthulhu"}},
+		{"cth", []string{"Cthulhu"}},
+		{"cross", []string{"DoubleCross"}},
+		{"dc", []string{"DiceBot", "DoubleCross"}},
+		{"xyz", []string{}},
+		{"", []string{}},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.query, func(t *testing.T) {
+			actual := r.search(test.query)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("got %v, want %v", actual, test.expected)
+			}
+		})
+	}
+}
+
+// ダイスボットのコンストラクタが登録簿を参照しても、一覧の取得や検索が止まらないことを確認する。
+func TestRegistry_ConstructorLooksUpRegistry(t *testing.T) {
+	r := newTestRegistry(t)
+
+	err := r.register("Nested", func() dicebot.DiceBot {
+		gameID, _ := r.canonicalGameID("SwordWorld2_0")
+		return &fakeDiceBot{gameID: "Nested", gameName: "入れ子 " + gameID}
+	})
+	if err != nil {
+		t.Fatalf("登録エラー: %s", err)
+		return
+	}
+
+	testcases := []struct {
+		name     string
+		f        func() []string
+		expected []string
+	}{
+		{
+			name: "availableGameIDs",
+			f:    func() []string { return r.availableGameIDs(false) },
+			expected: []string{
+				"Cthulhu", "DoubleCross", "Nested", "SwordWorld", "SwordWorld2.0",
+			},
+		},
+		{
+			name:     "search",
+			f:        func() []string { return r.search("入れ子") },
+			expected: []string{"Nested"},
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			done := make(chan []string, 1)
+			go func() {
+				done <- test.f()
+			}()
+
+			select {
+			case actual := <-done:
+				if !reflect.DeepEqual(actual, test.expected) {
+					t.Errorf("got %v, want %v", actual, test.expected)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("登録簿のロックを保持したままコンストラクタが呼び出された")
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package dicebot
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+)
+
+// コマンドを処理する関数の型。
+type Handler func(args *Args, ev *evaluator.Evaluator) (*command.Result, error)
+
+// ダイスボットのコマンドを振り分けるルーター。
+//
+// パターンは、大文字小文字を区別しない文字列と、型付きの引数の組み合わせで記述する。
+// 引数は {名前:型} の形式で記述する。型の後に "?" を付けると省略可能となる。
+// 固定文字列は省略できないため、固定文字列を含む部分が省略可能な場合は、パターンを分けて登録する。
+//
+// 利用できる型は以下の通り。
+//
+// * int : 符号付き整数（例："-3"）
+//
+// * expr: 加算ロール式または整数の計算式（例："2D6+1"、"(1+2)*3"）。コアの構文解析器で解析する
+//
+// * cmp : 比較演算子（"=", "<>", "<=", "<", ">=", ">"）。"==" は "="、"!=" は "<>" として扱う
+//
+// * str : 任意の文字列
+//
+// 例：
+//
+//	r := dicebot.NewRouter()
+//	r.Handle("K{power:int}[{crit:int}]", handleRating)
+//	r.Handle("K{power:int}", handleRating)
+//	r.Handle("{expr:expr}{op:cmp}{target:int}", handleCheck)
+//
+// パターンは登録された順に照合され、最初に一致したパターンの処理関数が呼び出される。
+type Router struct {
+	routes []*route
+}
+
+// 登録されたパターン
+type route struct {
+	// パターンの文字列
+	pattern string
+	// パターンに対応する正規表現
+	re *regexp.Regexp
+	// 引数
+	params []param
+	// 先頭の固定文字列（大文字）
+	prefix string
+	// 処理関数
+	handler Handler
+}
+
+// パターンの引数
+type param struct {
+	// 名前
+	name string
+	// 型
+	kind paramKind
+}
+
+// 引数の型
+type paramKind int
+
+const (
+	// 整数
+	paramInt paramKind = iota
+	// 式
+	paramExpr
+	// 比較演算子
+	paramCmp
+	// 文字列
+	paramStr
+)
+
+// 引数の型名と型との対応
+var paramKindNames = map[string]paramKind{
+	"int":  paramInt,
+	"expr": paramExpr,
+	"cmp":  paramCmp,
+	"str":  paramStr,
+}
+
+// 引数の型と正規表現との対応
+var paramKindPatterns = map[paramKind]string{
+	paramInt:  `[+-]?\d+`,
+	paramExpr: `[0-9A-Za-z+\-*/()\[\]{}.,]+?`,
+	paramCmp:  `<=|>=|<>|!=|==|=|<|>`,
+	paramStr:  `.+?`,
+}
+
+// パターン中の引数を表す正規表現
+var paramRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*):([a-z]+)(\?)?\}`)
+
+// NewRouter は新しいルーターを返す。
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle はパターンと処理関数を登録する。
+// パターンが不正な場合は panic する。
+func (r *Router) Handle(pattern string, handler Handler) {
+	rt, err := compileRoute(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	rt.handler = handler
+	r.routes = append(r.routes, rt)
+}
+
+// compileRoute はパターンを解析する。
+func compileRoute(pattern string) (*route, error) {
+	var reStr strings.Builder
+	reStr.WriteString(`(?i)\A`)
+
+	rt := &route{pattern: pattern}
+	names := map[string]bool{}
+
+	last := 0
+	for _, loc := range paramRe.FindAllStringSubmatchIndex(pattern, -1) {
+		literal := pattern[last:loc[0]]
+		if strings.ContainsAny(literal, "{}") {
+			return nil, fmt.Errorf("router: invalid pattern: %s", pattern)
+		}
+
+		if len(rt.params) == 0 {
+			rt.prefix = strings.ToUpper(literal)
+		}
+		reStr.WriteString(regexp.QuoteMeta(literal))
+
+		name := pattern[loc[2]:loc[3]]
+		kindName := pattern[loc[4]:loc[5]]
+		optional := loc[6] >= 0
+
+		kind, found := paramKindNames[kindName]
+		if !found {
+			return nil, fmt.Errorf("router: unknown parameter type %q in %s", kindName, pattern)
+		}
+
+		if names[name] {
+			return nil, fmt.Errorf("router: duplicate parameter %q in %s", name, pattern)
+		}
+		names[name] = true
+
+		reStr.WriteString("(" + paramKindPatterns[kind] + ")")
+		if optional {
+			reStr.WriteString("?")
+		}
+
+		rt.params = append(rt.params, param{name: name, kind: kind})
+		last = loc[1]
+	}
+
+	literal := pattern[last:]
+	if strings.ContainsAny(literal, "{}") {
+		return nil, fmt.Errorf("router: invalid pattern: %s", pattern)
+	}
+
+	if len(rt.params) == 0 {
+		rt.prefix = strings.ToUpper(literal)
+	}
+	reStr.WriteString(regexp.QuoteMeta(literal))
+	reStr.WriteString(`\z`)
+
+	re, err := regexp.Compile(reStr.String())
+	if err != nil {
+		return nil, fmt.Errorf("router: invalid pattern: %s: %s", pattern, err)
+	}
+	rt.re = re
+
+	return rt, nil
+}
+
+// Prefixes は、登録されたパターンの先頭の固定文字列（大文字）を辞書順に並べたスライスを返す。
+// 重複は取り除かれる。引数から始まるパターンの先頭の固定文字列（空文字列）は含まれない。
+//
+// 使用法の説明の生成に使うことができる。
+func (r *Router) Prefixes() []string {
+	seen := map[string]bool{}
+	prefixes := []string{}
+
+	for _, rt := range r.routes {
+		if rt.prefix == "" || seen[rt.prefix] {
+			continue
+		}
+
+		seen[rt.prefix] = true
+		prefixes = append(prefixes, rt.prefix)
+	}
+
+	sort.Strings(prefixes)
+
+	return prefixes
+}
+
+// MayMatch は、入力がいずれかのパターンに一致する可能性があるかどうかを返す。
+// 先頭の固定文字列のみを調べるため、高速に一致しない入力を除外できる。
+func (r *Router) MayMatch(input string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(input))
+
+	for _, rt := range r.routes {
+		if strings.HasPrefix(upper, rt.prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Execute は、入力に一致するパターンの処理関数を呼び出し、その結果を返す。
+// 一致するパターンがない場合はエラーを返す。
+//
+// DiceBot の ExecuteCommand の実装に使うことを想定している。
+func (r *Router) Execute(input string, ev *evaluator.Evaluator) (*command.Result, error) {
+	trimmed := strings.TrimSpace(input)
+
+	for _, rt := range r.routes {
+		args, matched := rt.match(trimmed)
+		if !matched {
+			continue
+		}
+
+		return rt.handler(args, ev)
+	}
+
+	return nil, fmt.Errorf("no matching command: %s", input)
+}
+
+// match は入力をパターンと照合し、引数を返す。
+// 正規表現に一致しても、引数の変換に失敗した場合は一致しないものとする。
+func (rt *route) match(input string) (*Args, bool) {
+	if !strings.HasPrefix(strings.ToUpper(input), rt.prefix) {
+		return nil, false
+	}
+
+	m := rt.re.FindStringSubmatch(input)
+	if m == nil {
+		return nil, false
+	}
+
+	args := &Args{
+		Input:   input,
+		Pattern: rt.pattern,
+		values:  map[string]interface{}{},
+	}
+
+	for i, p := range rt.params {
+		s := m[i+1]
+		if s == "" {
+			continue
+		}
+
+		v, ok := convertArg(p.kind, s)
+		if !ok {
+			return nil, false
+		}
+
+		args.values[p.name] = v
+	}
+
+	return args, true
+}
+
+// convertArg は引数の文字列を型に応じて変換する。
+func convertArg(kind paramKind, s string) (interface{}, bool) {
+	switch kind {
+	case paramInt:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, false
+		}
+
+		return n, true
+	case paramExpr:
+		e, err := parseExpr(s)
+		if err != nil {
+			return nil, false
+		}
+
+		return e, true
+	case paramCmp:
+		switch s {
+		case "==":
+			return "=", true
+		case "!=":
+			return "<>", true
+		default:
+			return s, true
+		}
+	default:
+		return s, true
+	}
+}
+
+// 式の引数
+type exprArg struct {
+	// 入力された式
+	source string
+	// 構文解析の結果
+	node ast.Node
+}
+
+// parseExpr は、加算ロール式または整数の計算式を構文解析する。
+func parseExpr(s string) (*exprArg, error) {
+	if root, err := parser.Parse("expr", []byte(s)); err == nil {
+		if c, ok := root.(*ast.Command); ok && c.Type() == ast.D_ROLL_EXPR_NODE {
+			return &exprArg{source: s, node: c}, nil
+		}
+	}
+
+	root, err := parser.Parse("expr", []byte("C("+s+")"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &exprArg{source: s, node: root.(ast.Node)}, nil
+}
+
+// パターンと照合された引数。
+type Args struct {
+	// 入力された文字列（前後の空白を除く）
+	Input string
+	// 一致したパターン
+	Pattern string
+	// 名前と変換された値との対応
+	values map[string]interface{}
+}
+
+// Has は、指定された名前の引数が入力されたかどうかを返す。
+func (a *Args) Has(name string) bool {
+	_, found := a.values[name]
+	return found
+}
+
+// Int は、指定された名前の整数の引数を返す。
+// 引数が省略された場合は0を返す。
+func (a *Args) Int(name string) int {
+	return a.IntOr(name, 0)
+}
+
+// IntOr は、指定された名前の整数の引数を返す。
+// 引数が省略された場合はdefaultValueを返す。
+func (a *Args) IntOr(name string, defaultValue int) int {
+	if n, ok := a.values[name].(int); ok {
+		return n
+	}
+
+	return defaultValue
+}
+
+// String は、指定された名前の文字列の引数を返す。
+// 比較演算子および式の引数の場合は、正規化された演算子および入力された式を返す。
+// 引数が省略された場合は空文字列を返す。
+func (a *Args) String(name string) string {
+	switch v := a.values[name].(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case *exprArg:
+		return v.source
+	default:
+		return ""
+	}
+}
+
+// Compare は、指定された名前の比較演算子の引数を返す。
+// 引数が省略された場合は空文字列を返す。
+func (a *Args) Compare(name string) string {
+	return a.String(name)
+}
+
+// Expr は、指定された名前の式の引数の抽象構文木を返す。
+// 引数が省略された場合はnilを返す。
+//
+// 加算ロール式の場合は加算ロールのコマンドのノード、
+// 整数の計算式の場合は計算コマンドのノードが返される。
+// 評価するたびに新しい抽象構文木が必要となるため、呼び出すごとに構文解析し直す。
+func (a *Args) Expr(name string) ast.Node {
+	e, ok := a.values[name].(*exprArg)
+	if !ok {
+		return nil
+	}
+
+	reparsed, err := parseExpr(e.source)
+	if err != nil {
+		return e.node
+	}
+
+	return reparsed.node
+}
+
+// EvalExpr は、指定された名前の式の引数を評価し、その結果を返す。
+// 最終的な数値は結果の Total に格納される。
+// 引数が省略された場合はエラーを返す。
+func (a *Args) EvalExpr(
+	name string,
+	gameID string,
+	ev *evaluator.Evaluator,
+) (*command.Result, error) {
+	node := a.Expr(name)
+	if node == nil {
+		return nil, fmt.Errorf("argument not given: %s", name)
+	}
+
+	return command.Execute(node, gameID, ev)
+}
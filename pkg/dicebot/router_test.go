@@ -0,0 +1,207 @@
+package dicebot
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// newTestRouter はテスト用のルーターを返す。
+// 各処理関数は、一致したパターンと引数を表す文字列を結果のメッセージとして返す。
+func newTestRouter() *Router {
+	r := NewRouter()
+
+	describe := func(args *Args, parts ...string) *command.Result {
+		return &command.Result{
+			GameID:       "Test",
+			MessageParts: append([]string{args.Pattern}, parts...),
+		}
+	}
+
+	handleRating := func(args *Args, _ *evaluator.Evaluator) (*command.Result, error) {
+		return describe(args,
+			fmt.Sprintf("power=%d", args.Int("power")),
+			fmt.Sprintf("crit=%d", args.IntOr("crit", 10)),
+			fmt.Sprintf("hasCrit=%v", args.Has("crit")),
+		), nil
+	}
+	r.Handle("K{power:int}[{crit:int?}]", handleRating)
+	r.Handle("K{power:int}", handleRating)
+	r.Handle("CC{target:int?}", func(args *Args, _ *evaluator.Evaluator) (*command.Result, error) {
+		return describe(args, args.String("target")), nil
+	})
+	r.Handle("ATK{expr:expr}{op:cmp}{target:expr}", func(args *Args, ev *evaluator.Evaluator) (*command.Result, error) {
+		return describe(args, args.String("expr"), args.Compare("op"), args.String("target")), nil
+	})
+	r.Handle("ROLL{expr:expr}", func(args *Args, ev *evaluator.Evaluator) (*command.Result, error) {
+		return args.EvalExpr("expr", "Test", ev)
+	})
+	r.Handle("SAY {msg:str}", func(args *Args, _ *evaluator.Evaluator) (*command.Result, error) {
+		return describe(args, args.String("msg")), nil
+	})
+
+	return r
+}
+
+func TestRouter_Execute(t *testing.T) {
+	r := newTestRouter()
+
+	testcases := []struct {
+		input    string
+		dice     []dice.Die
+		expected string
+	}{
+		{"K20", nil, "Test : K{power:int} ＞ power=20 ＞ crit=10 ＞ hasCrit=false"},
+		{"K20[]", nil, "Test : K{power:int}[{crit:int?}] ＞ power=20 ＞ crit=10 ＞ hasCrit=false"},
+		{"k20[9]", nil, "Test : K{power:int}[{crit:int?}] ＞ power=20 ＞ crit=9 ＞ hasCrit=true"},
+		{"K-5", nil, "Test : K{power:int} ＞ power=-5 ＞ crit=10 ＞ hasCrit=false"},
+		{" cc50 ", nil, "Test : CC{target:int?} ＞ 50"},
+		{"CC", nil, "Test : CC{target:int?} ＞ "},
+		{"ATK2D6+1>=10", nil, "Test : ATK{expr:expr}{op:cmp}{target:expr} ＞ 2D6+1 ＞ >= ＞ 10"},
+		{"atk(1+2)*3!=4", nil, "Test : ATK{expr:expr}{op:cmp}{target:expr} ＞ (1+2)*3 ＞ <> ＞ 4"},
+		{"ATK2d6==7", nil, "Test : ATK{expr:expr}{op:cmp}{target:expr} ＞ 2d6 ＞ = ＞ 7"},
+		{"ROLL2D6+1", []dice.Die{{3, 6}, {4, 6}}, "Test : (2D6+1) ＞ 7[3,4]+1 ＞ 8"},
+		{"ROLL1+2*3", nil, "Test : C(1+2*3) ＞ 計算結果 ＞ 7"},
+		{"say hello world", nil, "Test : SAY {msg:str} ＞ hello world"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.input, func(t *testing.T) {
+			ev := evaluator.NewEvaluator(
+				roller.New(feeder.NewQueue(test.dice)),
+				evaluator.NewEnvironment(),
+			)
+
+			result, err := r.Execute(test.input, ev)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+				return
+			}
+
+			actual := result.Message()
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestRouter_ExecuteNoMatch(t *testing.T) {
+	r := newTestRouter()
+
+	testcases := []string{
+		"",
+		"X20",
+		"K",
+		"K20[",
+		"Kabc",
+		"ATK2D6",
+		"ATK2D6+>=10",
+		"ROLL2D",
+		"SAY",
+	}
+
+	for _, input := range testcases {
+		t.Run(input, func(t *testing.T) {
+			ev := evaluator.NewEvaluator(
+				roller.New(feeder.NewEmptyQueue()),
+				evaluator.NewEnvironment(),
+			)
+
+			if _, err := r.Execute(input, ev); err == nil {
+				t.Errorf("一致しないはずの入力が一致した: %q", input)
+			}
+		})
+	}
+}
+
+func TestRouter_ExprReevaluation(t *testing.T) {
+	r := NewRouter()
+
+	var args *Args
+	r.Handle("R{e:expr}", func(a *Args, _ *evaluator.Evaluator) (*command.Result, error) {
+		args = a
+		return &command.Result{}, nil
+	})
+
+	ev := evaluator.NewEvaluator(roller.New(feeder.NewEmptyQueue()), evaluator.NewEnvironment())
+	if _, err := r.Execute("R2D6", ev); err != nil {
+		t.Fatalf("実行エラー: %s", err)
+	}
+
+	// 同じ引数を2回評価できることを確認する
+	for i, ds := range [][]dice.Die{{{1, 6}, {2, 6}}, {{5, 6}, {6, 6}}} {
+		ev := evaluator.NewEvaluator(roller.New(feeder.NewQueue(ds)), evaluator.NewEnvironment())
+
+		result, err := args.EvalExpr("e", "Test", ev)
+		if err != nil {
+			t.Fatalf("%d回目の評価エラー: %s", i+1, err)
+		}
+
+		expected := ds[0].Value + ds[1].Value
+		if result.Total == nil || *result.Total != expected {
+			t.Errorf("%d回目: got %v, want %d", i+1, result.Total, expected)
+		}
+	}
+}
+
+func TestRouter_Prefixes(t *testing.T) {
+	r := newTestRouter()
+	r.Handle("{n:int}ANY", func(*Args, *evaluator.Evaluator) (*command.Result, error) {
+		return nil, nil
+	})
+	r.Handle("cc", func(*Args, *evaluator.Evaluator) (*command.Result, error) {
+		return nil, nil
+	})
+
+	expected := []string{"ATK", "CC", "K", "ROLL", "SAY "}
+	actual := r.Prefixes()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestRouter_MayMatch(t *testing.T) {
+	r := newTestRouter()
+
+	testcases := []struct {
+		input    string
+		expected bool
+	}{
+		{"K20", true},
+		{"atk2d6>=3", true},
+		{"cc", true},
+		{"2D6", false},
+		{"X", false},
+	}
+
+	for _, test := range testcases {
+		actual := r.MayMatch(test.input)
+		if actual != test.expected {
+			t.Errorf("%q: got %v, want %v", test.input, actual, test.expected)
+		}
+	}
+}
+
+func TestRouter_HandleInvalidPattern(t *testing.T) {
+	testcases := []string{
+		"K{power:float}",
+		"K{power:int}{power:int}",
+		"K{power}",
+		"K{",
+	}
+
+	for _, pattern := range testcases {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := compileRoute(pattern); err == nil {
+				t.Errorf("不正なパターンが受け入れられた: %q", pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package script
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/raa0121/GoBCDice/pkg/core/ast"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/parser"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+const (
+	// 一度に振ることができるダイスの数の上限
+	maxDiceToRoll = 1000
+	// 正規表現の長さの上限
+	maxPatternLength = 1000
+)
+
+// スクリプトで使える大域変数（Starlark の組み込み関数を除く）。
+//
+// ファイルシステムやネットワークにアクセスする関数は提供しない。
+var predeclared = starlark.StringDict{
+	"bcdice": &starlarkstruct.Module{
+		Name: "bcdice",
+		Members: starlark.StringDict{
+			"roll":    starlark.NewBuiltin("bcdice.roll", builtinRoll),
+			"sum":     starlark.NewBuiltin("bcdice.sum", builtinSum),
+			"execute": starlark.NewBuiltin("bcdice.execute", builtinExecute),
+			"match":   starlark.NewBuiltin("bcdice.match", builtinMatch),
+		},
+	},
+}
+
+func init() {
+	predeclared.Freeze()
+}
+
+// consume は、組み込み関数の処理量に応じて実行ステップ数を加算する。
+// 上限を超えた場合は、次の命令の実行前にスレッドが中止される。
+func consume(thread *starlark.Thread, n int) {
+	if n > 0 {
+		thread.Steps += uint64(n)
+	}
+}
+
+// threadEvaluator は、スレッドに設定された評価器を返す。
+// コマンドの実行中でない場合はnilを返す。
+func threadEvaluator(thread *starlark.Thread) *evaluator.Evaluator {
+	ev, _ := thread.Local(localEvaluator).(*evaluator.Evaluator)
+	return ev
+}
+
+// intList は、整数のスライスをリストに変換する。
+func intList(values []int) *starlark.List {
+	elems := make([]starlark.Value, 0, len(values))
+	for _, v := range values {
+		elems = append(elems, starlark.MakeInt(v))
+	}
+
+	return starlark.NewList(elems)
+}
+
+// builtinRoll は bcdice.roll(num, sides) を実行する。
+// 評価器を使ってダイスを振り、出目のリストを返す。振られたダイスは結果に記録される。
+func builtinRoll(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var num, sides int
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &num, &sides); err != nil {
+		return nil, err
+	}
+
+	ev := threadEvaluator(thread)
+	if ev == nil {
+		return nil, fmt.Errorf("%s: dice can be rolled only while executing a command", b.Name())
+	}
+
+	if num < 0 || num > maxDiceToRoll {
+		return nil, fmt.Errorf("%s: invalid number of dice: %d", b.Name(), num)
+	}
+
+	consume(thread, num)
+
+	rolledDice, err := ev.RollDice(num, sides)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	values := make([]int, 0, len(rolledDice))
+	for _, d := range rolledDice {
+		values = append(values, d.Value)
+	}
+
+	return intList(values), nil
+}
+
+// builtinSum は bcdice.sum(...) を実行する。引数には整数のリストも指定できる。
+func builtinSum(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: unexpected keyword arguments", b.Name())
+	}
+
+	sum := starlark.MakeInt(0)
+	for i, a := range args {
+		switch v := a.(type) {
+		case starlark.Int:
+			sum = sum.Add(v)
+		case starlark.Indexable:
+			if _, isString := v.(starlark.String); isString {
+				return nil, fmt.Errorf("%s: for parameter %d: got string, want int or list of ints", b.Name(), i+1)
+			}
+
+			consume(thread, v.Len())
+
+			for j := 0; j < v.Len(); j++ {
+				n, ok := v.Index(j).(starlark.Int)
+				if !ok {
+					return nil, fmt.Errorf("%s: for parameter %d: got %s in list, want int", b.Name(), i+1, v.Index(j).Type())
+				}
+
+				sum = sum.Add(n)
+			}
+		default:
+			return nil, fmt.Errorf("%s: for parameter %d: got %s, want int or list of ints", b.Name(), i+1, a.Type())
+		}
+	}
+
+	return sum, nil
+}
+
+// builtinExecute は bcdice.execute(command) を実行する。
+//
+// BCDiceの基本コマンドを構文解析して実行し、結果を構造体として返す。
+// 構造体のフィールドは以下の通り。
+//
+// * message: メッセージの部分を " ＞ " で結合した文字列
+//
+// * parts: メッセージの部分のリスト
+//
+// * total: 最終的な数値（ない場合はNone）
+//
+// * success: 成功判定の結果（成功ならTrue、失敗ならFalse、判定しない場合はNone）
+//
+// * critical、fumble、special: クリティカルなどかどうか
+//
+// * dice: このコマンドで振られたダイスの出目のリスト
+func builtinExecute(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var input string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &input); err != nil {
+		return nil, err
+	}
+
+	ev := threadEvaluator(thread)
+	if ev == nil {
+		return nil, fmt.Errorf("%s: commands can be executed only while executing a command", b.Name())
+	}
+
+	node, err := parser.Parse("script", []byte(input))
+	if err != nil {
+		return nil, fmt.Errorf("%s: syntax error: %s", b.Name(), input)
+	}
+
+	numOfRolledDice := len(ev.RolledDice())
+	gameID, _ := thread.Local(localGameID).(string)
+
+	r, err := command.Execute(node.(ast.Node), gameID, ev)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	rolledDice := ev.RolledDice()[numOfRolledDice:]
+	consume(thread, len(rolledDice))
+
+	return resultToStruct(r, rolledDice), nil
+}
+
+// resultToStruct はコマンドの実行結果を構造体に変換する。
+// rolledDiceはそのコマンドで振られたダイス。
+func resultToStruct(r *command.Result, rolledDice []dice.Die) *starlarkstruct.Struct {
+	parts := make([]starlark.Value, 0, len(r.MessageParts))
+	for _, p := range r.MessageParts {
+		parts = append(parts, starlark.String(p))
+	}
+
+	var total starlark.Value = starlark.None
+	if r.Total != nil {
+		total = starlark.MakeInt(*r.Total)
+	}
+
+	var success starlark.Value = starlark.None
+	switch r.SuccessCheckResult {
+	case command.SUCCESS_CHECK_SUCCESS:
+		success = starlark.True
+	case command.SUCCESS_CHECK_FAILURE:
+		success = starlark.False
+	}
+
+	values := make([]int, 0, len(rolledDice))
+	for _, d := range rolledDice {
+		values = append(values, d.Value)
+	}
+
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"message":  starlark.String(r.JoinedMessageParts()),
+		"parts":    starlark.NewList(parts),
+		"total":    total,
+		"success":  success,
+		"critical": starlark.Bool(r.IsCritical),
+		"fumble":   starlark.Bool(r.IsFumble),
+		"special":  starlark.Bool(r.IsSpecial),
+		"dice":     intList(values),
+	})
+}
+
+// builtinMatch は bcdice.match(s, pattern) を実行する。
+//
+// Go の正規表現（RE2構文）で照合し、一致した場合は部分一致の文字列のリストを返す。
+// リストの要素 [0] は一致した全体、[1] 以降は各グループとなる。
+// 一致しなかったグループは空文字列となる。一致しなかった場合はNoneを返す。
+func builtinMatch(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var s, pattern string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 2, &s, &pattern); err != nil {
+		return nil, err
+	}
+
+	if len(pattern) > maxPatternLength {
+		return nil, fmt.Errorf("%s: pattern too long", b.Name())
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return starlark.None, nil
+	}
+
+	groups := make([]starlark.Value, 0, len(m))
+	for _, sub := range m {
+		groups = append(groups, starlark.String(sub))
+	}
+
+	return starlark.NewList(groups), nil
+}
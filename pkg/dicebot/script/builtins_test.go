@@ -0,0 +1,106 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"go.starlark.net/starlark"
+)
+
+// runSource はソースコードを実行し、大域変数 r の値を文字列として返す。
+func runSource(src string, budget int) (string, error) {
+	_, program, err := starlark.SourceProgramOptions(fileOptions, "test.star", src, predeclared.Has)
+	if err != nil {
+		return "", err
+	}
+
+	s := &Script{Name: "test.star", program: program}
+	thread := newThread(budget, "", newTestEvaluator(nil))
+
+	globals, err := s.init(thread)
+	if err != nil {
+		return "", s.wrapError(thread, err)
+	}
+
+	r, ok := starlark.AsString(globals["r"])
+	if !ok {
+		return globals["r"].String(), nil
+	}
+
+	return r, nil
+}
+
+func TestBuiltins(t *testing.T) {
+	testcases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"Sum", "r = bcdice.sum([1, 2], 3)", "6"},
+		{"SumEmpty", "r = bcdice.sum()", "0"},
+		{"Match", `m = bcdice.match("2DX@8", "^(\\d+)DX(?:@(\\d+))?(x)?$")` + "\nr = '|'.join(m)", "2DX@8|2|8|"},
+		{"MatchFailure", `r = bcdice.match("abc", "^\\d+$")`, "None"},
+		{"Execute", `r = str(bcdice.execute("C(1+2)").total)`, "3"},
+		{"While", "def f():\n    i = 0\n    while i < 5:\n        i += 1\n    return i\nr = f()", "5"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := runSource(test.src, DEFAULT_INSTRUCTION_BUDGET)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+				return
+			}
+
+			if r != test.expected {
+				t.Errorf("got %q, want %q", r, test.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltins_Errors(t *testing.T) {
+	testcases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"RollArguments", "bcdice.roll('a', 6)", "test.star:1:12: bcdice.roll: for parameter 1: got string, want int"},
+		{"RollTooMany", "bcdice.roll(1001, 6)", "bcdice.roll: invalid number of dice: 1001"},
+		{"SumString", "bcdice.sum('12')", "bcdice.sum: for parameter 1: got string, want int or list of ints"},
+		{"SumListOfStrings", "bcdice.sum(['1'])", "bcdice.sum: for parameter 1: got string in list, want int"},
+		{"ExecuteSyntaxError", "bcdice.execute('(')", "bcdice.execute: syntax error: ("},
+		{"InvalidPattern", "bcdice.match('a', '(')", "bcdice.match: error parsing regexp"},
+		{"PatternTooLong", "bcdice.match('a', 'a' * 1001)", "bcdice.match: pattern too long"},
+		{"Fail", "\n\nfail('独自のエラー')", "test.star:3:5: fail: 独自のエラー"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := runSource(test.src, DEFAULT_INSTRUCTION_BUDGET)
+			if err == nil {
+				t.Fatal("エラーが発生しなかった")
+				return
+			}
+
+			if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("got %q, want to contain %q", err.Error(), test.expected)
+			}
+		})
+	}
+}
+
+func TestInstructionBudget(t *testing.T) {
+	testcases := []string{
+		"def f():\n    while True:\n        pass\nf()",
+		"def f():\n    r = 0\n    for i in range(1000):\n        r += 1\nf()",
+		"r = sorted(range(1000), key = lambda x: -x)",
+		"def f():\n    for i in range(10):\n        bcdice.sum(list(range(100)))\nf()",
+	}
+
+	for _, src := range testcases {
+		if _, err := runSource(src, 1000); err != ErrBudgetExceeded {
+			t.Errorf("%q: got %v, want %v", src, err, ErrBudgetExceeded)
+		}
+	}
+}
@@ -0,0 +1,547 @@
+/*
+スクリプトで定義したゲームシステムのダイスボットのパッケージ。
+
+宣言的な定義（dicebot/declarative パッケージ）では表現できない処理を、
+Starlark（go.starlark.net）で記述できる。
+
+スクリプトでは、大域変数 gameId（必須）、gameName、sortKey、usage、aliases、prefixes と、
+入力されたコマンドを受け取る関数 command（必須）を定義する。
+
+	gameId = "Mini"
+	gameName = "小さなシステム"
+
+	def command(input):
+	    m = bcdice.match(input, "^(?i)M(\\d+)$")
+	    if m == None:
+	        return None
+
+	    dice = bcdice.roll(2, 6)
+	    total = bcdice.sum(dice)
+
+	    return {
+	        "parts": ["(2D6>=" + m[1] + ")", total],
+	        "total": total,
+	        "success": total >= int(m[1]),
+	    }
+
+command は、コマンドに該当しない場合はNoneを、該当する場合は文字列または辞書を返す。
+辞書では以下のキーを使う。
+
+* parts: メッセージの部分のリスト（message で1つの文字列を指定することもできる）
+
+* total: 最終的な数値
+
+* success: 成功判定の結果（True または False）
+
+* critical、fumble、special: クリティカルなどかどうか
+
+モジュール bcdice の関数を使って、評価器によるダイスロール（roll）、
+BCDiceの基本コマンドの実行（execute）、正規表現による照合（match）、合計（sum）ができる。
+そのほかに使えるのは Starlark の組み込み関数のみである。
+while 文は使えるが、再帰呼び出しと load 文は使えない。print の出力は捨てられる。
+
+ファイルシステムやネットワークにアクセスする関数は提供しない。
+また、コマンドの実行ごとに実行ステップ数の上限（InstructionBudget）があり、
+超えた場合は実行を中止する。
+スクリプトの状態はコマンドの実行ごとに初期化される。
+*/
+package script
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/core/locale"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+	"github.com/raa0121/GoBCDice/pkg/dicebot/list"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const (
+	// 実行ステップ数の上限の既定値
+	DEFAULT_INSTRUCTION_BUDGET = 100000
+	// スクリプトファイルの拡張子
+	FILE_EXT = ".star"
+)
+
+// 実行ステップ数の上限を超えたことを示すエラー
+var ErrBudgetExceeded = errors.New("script: instruction budget exceeded")
+
+// スクリプトの構文解析のオプション。
+//
+// 実行ステップ数の上限で停止が保証されるため、while 文を許可する。
+// 再帰呼び出しはGoのスタックを消費するため許可しない。
+var fileOptions = &syntax.FileOptions{
+	While: true,
+}
+
+// スレッドローカルな値のキー
+const (
+	// 実行ステップ数の上限を超えたかどうか
+	localBudgetExceeded = "budgetExceeded"
+	// 評価器
+	localEvaluator = "evaluator"
+	// ゲーム識別子
+	localGameID = "gameID"
+)
+
+// 構文解析済みのスクリプト。
+type Script struct {
+	// スクリプトの名前（エラーメッセージで使う）
+	Name string
+	// ゲーム識別子
+	GameID string
+	// ゲームシステム名
+	GameName string
+	// 並べ替えのキー
+	SortKey string
+	// 使用法の説明
+	Usage string
+	// ゲーム識別子の別名
+	Aliases []string
+	// コマンドの接頭辞
+	CommandPrefixes []string
+	// コマンドの実行ごとの実行ステップ数の上限
+	InstructionBudget int
+
+	// コンパイルされたプログラム
+	program *starlark.Program
+}
+
+// Compile は、スクリプトをコンパイルし、大域変数からゲームシステムの情報を読み込む。
+// nameはエラーメッセージで使うスクリプトの名前。
+func Compile(name string, src []byte) (*Script, error) {
+	_, program, err := starlark.SourceProgramOptions(fileOptions, name, src, predeclared.Has)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Script{
+		Name:              name,
+		InstructionBudget: DEFAULT_INSTRUCTION_BUDGET,
+		program:           program,
+	}
+
+	thread := newThread(s.InstructionBudget, "", nil)
+
+	globals, err := s.init(thread)
+	if err != nil {
+		return nil, s.wrapError(thread, err)
+	}
+
+	if err := s.loadInfo(globals); err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err)
+	}
+
+	return s, nil
+}
+
+// newThread は、スクリプトを実行するスレッドを返す。
+//
+// budgetは実行ステップ数の上限。上限に達すると、ホスト側のフックで
+// 上限を超えたことを記録し、実行を中止する。
+// evはダイスロールに使う評価器。nilの場合はダイスロールできない。
+func newThread(budget int, gameID string, ev *evaluator.Evaluator) *starlark.Thread {
+	thread := &starlark.Thread{
+		Print: func(_ *starlark.Thread, _ string) {},
+		OnMaxSteps: func(thread *starlark.Thread) {
+			thread.SetLocal(localBudgetExceeded, true)
+			thread.Cancel("too many steps")
+		},
+	}
+
+	thread.SetMaxExecutionSteps(uint64(budget))
+	thread.SetLocal(localGameID, gameID)
+	if ev != nil {
+		thread.SetLocal(localEvaluator, ev)
+	}
+
+	return thread
+}
+
+// init は、スクリプトの最上位のコードを実行し、大域変数を返す。
+func (s *Script) init(thread *starlark.Thread) (globals starlark.StringDict, err error) {
+	err = protect(func() error {
+		var initErr error
+		globals, initErr = s.program.Init(thread, predeclared)
+
+		return initErr
+	})
+
+	return globals, err
+}
+
+// protect は、fを実行し、ホスト側で発生したパニックをエラーとして返す。
+func protect(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return f()
+}
+
+// wrapError は、スクリプトの実行中に発生したエラーに位置情報を付加する。
+// 実行ステップ数の上限を超えた場合は ErrBudgetExceeded を返す。
+func (s *Script) wrapError(thread *starlark.Thread, err error) error {
+	if exceeded, _ := thread.Local(localBudgetExceeded).(bool); exceeded {
+		return ErrBudgetExceeded
+	}
+
+	evalErr, ok := err.(*starlark.EvalError)
+	if !ok {
+		return fmt.Errorf("%s: %s", s.Name, err)
+	}
+
+	// 組み込み関数のフレームを除き、最も内側のスクリプトの位置を示す
+	for i := 0; i < len(evalErr.CallStack); i++ {
+		pos := evalErr.CallStack.At(i).Pos
+		if pos.Filename() == s.Name {
+			return fmt.Errorf("%s: %s", pos, evalErr.Msg)
+		}
+	}
+
+	return fmt.Errorf("%s: %s", s.Name, evalErr.Msg)
+}
+
+// loadInfo は、大域変数からゲームシステムの情報を読み込む。
+func (s *Script) loadInfo(globals starlark.StringDict) error {
+	gameID, ok := starlark.AsString(globals["gameId"])
+	if !ok || strings.TrimSpace(gameID) == "" {
+		return fmt.Errorf("gameId must be a non-empty string")
+	}
+	s.GameID = gameID
+
+	s.GameName = gameID
+	if v := globals["gameName"]; v != nil {
+		gameName, ok := starlark.AsString(v)
+		if !ok {
+			return fmt.Errorf("gameName must be a string")
+		}
+
+		s.GameName = gameName
+	}
+
+	s.SortKey = strings.ToLower(gameID)
+	if v := globals["sortKey"]; v != nil {
+		sortKey, ok := starlark.AsString(v)
+		if !ok {
+			return fmt.Errorf("sortKey must be a string")
+		}
+
+		s.SortKey = sortKey
+	}
+
+	if v := globals["usage"]; v != nil {
+		usage, ok := starlark.AsString(v)
+		if !ok {
+			return fmt.Errorf("usage must be a string")
+		}
+
+		s.Usage = usage
+	}
+
+	aliases, err := stringsGlobal(globals, "aliases")
+	if err != nil {
+		return err
+	}
+	s.Aliases = aliases
+
+	prefixes, err := stringsGlobal(globals, "prefixes")
+	if err != nil {
+		return err
+	}
+
+	s.CommandPrefixes = prefixes
+	if s.CommandPrefixes == nil {
+		s.CommandPrefixes = []string{}
+	}
+
+	if _, ok := globals["command"].(*starlark.Function); !ok {
+		return fmt.Errorf("function command is not defined")
+	}
+
+	return nil
+}
+
+// stringsGlobal は、文字列のリストである大域変数の値をスライスとして返す。
+// 変数が定義されていない場合はnilを返す。
+func stringsGlobal(globals starlark.StringDict, name string) ([]string, error) {
+	v := globals[name]
+	if v == nil {
+		return nil, nil
+	}
+
+	strs, ok := stringSlice(v)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of strings", name)
+	}
+
+	return strs, nil
+}
+
+// stringSlice は、文字列のリストまたはタプルをスライスに変換する。
+func stringSlice(v starlark.Value) ([]string, bool) {
+	seq, ok := v.(starlark.Indexable)
+	if !ok {
+		return nil, false
+	}
+
+	if _, isString := v.(starlark.String); isString {
+		return nil, false
+	}
+
+	strs := []string{}
+	for i := 0; i < seq.Len(); i++ {
+		str, ok := starlark.AsString(seq.Index(i))
+		if !ok {
+			return nil, false
+		}
+
+		strs = append(strs, str)
+	}
+
+	return strs, true
+}
+
+// LoadFile はスクリプトファイルを読み込む。
+func LoadFile(path string) (*Script, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(filepath.Base(path), data)
+}
+
+// スクリプトで定義したダイスボット。
+type DiceBot struct {
+	// スクリプト
+	script *Script
+}
+
+var _ dicebot.MetadataDiceBot = (*DiceBot)(nil)
+
+// GameID はゲーム識別子を返す。
+func (b *DiceBot) GameID() string {
+	return b.script.GameID
+}
+
+// GameName はゲームシステム名を返す。
+func (b *DiceBot) GameName() string {
+	return b.script.GameName
+}
+
+// Usage はダイスボットの使用法の説明を返す。
+func (b *DiceBot) Usage() string {
+	return b.script.Usage
+}
+
+// SortKey は並べ替えのキーを返す。
+func (b *DiceBot) SortKey() string {
+	return b.script.SortKey
+}
+
+// HelpMessage はヘルプメッセージを返す。
+// スクリプトはロケールに対応していないため、どのロケールでも使用法の説明を返す。
+func (b *DiceBot) HelpMessage(_ locale.Locale) string {
+	return b.script.Usage
+}
+
+// CommandPrefixes は、ゲームシステム固有のコマンドの接頭辞のスライスを返す。
+func (b *DiceBot) CommandPrefixes() []string {
+	return append([]string{}, b.script.CommandPrefixes...)
+}
+
+// ExecuteCommand は、スクリプトの関数 command を呼び出して、指定されたコマンドを実行する。
+//
+// 実行ステップ数の上限を超えた場合は ErrBudgetExceeded を返す。
+func (b *DiceBot) ExecuteCommand(c string, ev *evaluator.Evaluator) (*command.Result, error) {
+	s := b.script
+	thread := newThread(s.InstructionBudget, s.GameID, ev)
+
+	globals, err := s.init(thread)
+	if err != nil {
+		return nil, s.wrapError(thread, err)
+	}
+
+	var v starlark.Value
+	err = protect(func() error {
+		var callErr error
+		v, callErr = starlark.Call(thread, globals["command"], starlark.Tuple{starlark.String(strings.TrimSpace(c))}, nil)
+
+		return callErr
+	})
+	if err != nil {
+		return nil, s.wrapError(thread, err)
+	}
+
+	if v == starlark.None {
+		return nil, fmt.Errorf("no game-system-specific command: %s", c)
+	}
+
+	result, err := newResult(v, s.GameID, ev)
+	if err != nil {
+		return nil, fmt.Errorf("%s: command returned an invalid result: %s", s.Name, err)
+	}
+
+	return result, nil
+}
+
+// newResult は、スクリプトの関数 command が返した値からコマンドの実行結果を作る。
+func newResult(v starlark.Value, gameID string, ev *evaluator.Evaluator) (*command.Result, error) {
+	result := &command.Result{
+		GameID:         gameID,
+		RolledDice:     ev.RolledDice(),
+		DieDefinitions: ev.RolledDieDefinitions(),
+		Nonces:         ev.RolledDieNonces(),
+		Locale:         ev.Locale,
+	}
+
+	switch x := v.(type) {
+	case starlark.String:
+		result.MessageParts = []string{string(x)}
+		return result, nil
+	case *starlark.Dict:
+		if err := fillResult(result, x); err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("string or dict expected, got %s", v.Type())
+	}
+}
+
+// dictGet は、辞書の文字列のキーに対応する値を返す。キーがない場合はNoneを返す。
+func dictGet(d *starlark.Dict, key string) starlark.Value {
+	v, found, _ := d.Get(starlark.String(key))
+	if !found {
+		return starlark.None
+	}
+
+	return v
+}
+
+// fillResult は、辞書の内容をコマンドの実行結果に設定する。
+func fillResult(result *command.Result, d *starlark.Dict) error {
+	switch parts := dictGet(d, "parts").(type) {
+	case starlark.NoneType:
+		message, ok := starlark.AsString(dictGet(d, "message"))
+		if !ok {
+			return fmt.Errorf("parts or message is required")
+		}
+
+		result.MessageParts = []string{message}
+	case *starlark.List, starlark.Tuple:
+		seq := parts.(starlark.Indexable)
+		for i := 0; i < seq.Len(); i++ {
+			switch p := seq.Index(i).(type) {
+			case starlark.String:
+				result.MessageParts = append(result.MessageParts, string(p))
+			case starlark.Int:
+				result.MessageParts = append(result.MessageParts, p.String())
+			default:
+				return fmt.Errorf("parts must be a list of strings")
+			}
+		}
+	default:
+		return fmt.Errorf("parts must be a list of strings")
+	}
+
+	switch total := dictGet(d, "total").(type) {
+	case starlark.NoneType:
+	case starlark.Int:
+		n, ok := total.Int64()
+		if !ok {
+			return fmt.Errorf("total is out of range")
+		}
+
+		t := int(n)
+		result.Total = &t
+	default:
+		return fmt.Errorf("total must be an int")
+	}
+
+	switch success := dictGet(d, "success").(type) {
+	case starlark.NoneType:
+	case starlark.Bool:
+		if success {
+			result.SuccessCheckResult = command.SUCCESS_CHECK_SUCCESS
+		} else {
+			result.SuccessCheckResult = command.SUCCESS_CHECK_FAILURE
+		}
+	default:
+		return fmt.Errorf("success must be a bool")
+	}
+
+	result.IsCritical = bool(dictGet(d, "critical").Truth())
+	result.IsFumble = bool(dictGet(d, "fumble").Truth())
+	result.IsSpecial = bool(dictGet(d, "special").Truth())
+
+	return nil
+}
+
+// NewConstructor は、スクリプトからダイスボットのコンストラクタを作る。
+func NewConstructor(s *Script) dicebot.DiceBotConstructor {
+	return func() dicebot.DiceBot {
+		return &DiceBot{script: s}
+	}
+}
+
+// Register は、スクリプトで定義したダイスボットを dicebot/list パッケージに登録する。
+// 識別子が登録済みのものと重複する場合はエラーを返す。
+func Register(s *Script) error {
+	return list.Add(s.GameID, NewConstructor(s), s.Aliases...)
+}
+
+// LoadDir は、ディレクトリ内のスクリプトファイル（.star）をファイル名の順に読み込み、
+// dicebot/list パッケージに登録する。返り値は登録したゲーム識別子のスライスとエラー。
+//
+// エラーが発生した場合はその時点で読み込みを中止する。それまでに読み込んだものは登録されたままとなる。
+func LoadDir(dir string) ([]string, error) {
+	return loadDir(dir, list.Add)
+}
+
+// ダイスボットを登録する関数の型
+type addFunc func(gameID string, constructor dicebot.DiceBotConstructor, aliases ...string) error
+
+// loadDir は、ディレクトリ内のスクリプトファイルを読み込み、addを使って登録する。
+func loadDir(dir string, add addFunc) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, f := range files {
+		if !f.IsDir() && strings.EqualFold(filepath.Ext(f.Name()), FILE_EXT) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	gameIDs := []string{}
+	for _, name := range names {
+		s, err := LoadFile(filepath.Join(dir, name))
+		if err != nil {
+			return gameIDs, err
+		}
+
+		if err := add(s.GameID, NewConstructor(s), s.Aliases...); err != nil {
+			return gameIDs, fmt.Errorf("%s: %s", name, err)
+		}
+
+		gameIDs = append(gameIDs, s.GameID)
+	}
+
+	return gameIDs, nil
+}
@@ -0,0 +1,384 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+	"github.com/raa0121/GoBCDice/pkg/dicebot"
+)
+
+// loadTestDiceBot はテスト用のスクリプトからダイスボットを構築する。
+func loadTestDiceBot(t *testing.T, filename string) dicebot.DiceBot {
+	s, err := LoadFile(filepath.Join("testdata", filename))
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	return NewConstructor(s)()
+}
+
+// newTestEvaluator は、指定したダイスを順に返す評価器を返す。
+func newTestEvaluator(dice []dice.Die) *evaluator.Evaluator {
+	return evaluator.NewEvaluator(
+		roller.New(feeder.NewQueue(dice)),
+		evaluator.NewEnvironment(),
+	)
+}
+
+func TestDiceBot_ExecuteCommand(t *testing.T) {
+	testcases := []struct {
+		input            string
+		dice             []dice.Die
+		expected         string
+		expectedTotal    int
+		expectedSuccess  command.SuccessCheckResultType
+		expectedIsFumble bool
+	}{
+		{
+			input:         "2DX",
+			dice:          []dice.Die{{7, 10}, {5, 10}},
+			expected:      "CritChain : (2DX) ＞ 7[7,5] ＞ 7",
+			expectedTotal: 7,
+		},
+		{
+			input:         "3dx@8",
+			dice:          []dice.Die{{8, 10}, {2, 10}, {9, 10}, {10, 10}, {3, 10}, {4, 10}},
+			expected:      "CritChain : (3dx@8) ＞ 9[8,2,9]+10[10,3]+4[4] ＞ 24",
+			expectedTotal: 24,
+		},
+		{
+			input:           "2DX>=10",
+			dice:            []dice.Die{{10, 10}, {1, 10}, {3, 10}},
+			expected:        "CritChain : (2DX>=10) ＞ 10[10,1]+3[3] ＞ 13 ＞ 成功",
+			expectedTotal:   13,
+			expectedSuccess: command.SUCCESS_CHECK_SUCCESS,
+		},
+		{
+			input:           "2DX@7>=10",
+			dice:            []dice.Die{{6, 10}, {1, 10}},
+			expected:        "CritChain : (2DX@7>=10) ＞ 6[6,1] ＞ 6 ＞ 失敗",
+			expectedTotal:   6,
+			expectedSuccess: command.SUCCESS_CHECK_FAILURE,
+		},
+		{
+			input:            "2DX",
+			dice:             []dice.Die{{1, 10}, {1, 10}},
+			expected:         "CritChain : (2DX) ＞ 1[1,1] ＞ 0 ＞ ファンブル",
+			expectedTotal:    0,
+			expectedSuccess:  command.SUCCESS_CHECK_FAILURE,
+			expectedIsFumble: true,
+		},
+	}
+
+	b := loadTestDiceBot(t, "crit_chain.star")
+
+	for _, test := range testcases {
+		t.Run(test.input, func(t *testing.T) {
+			ev := newTestEvaluator(test.dice)
+
+			r, err := b.ExecuteCommand(test.input, ev)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+				return
+			}
+
+			if actual := r.Message(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+
+			if r.Total == nil || *r.Total != test.expectedTotal {
+				t.Errorf("Total: got %v, want %d", r.Total, test.expectedTotal)
+			}
+
+			if r.SuccessCheckResult != test.expectedSuccess {
+				t.Errorf("SuccessCheckResult: got %s, want %s", r.SuccessCheckResult, test.expectedSuccess)
+			}
+
+			if r.IsFumble != test.expectedIsFumble {
+				t.Errorf("IsFumble: got %v, want %v", r.IsFumble, test.expectedIsFumble)
+			}
+
+			if !reflect.DeepEqual(r.RolledDice, test.dice) {
+				t.Errorf("RolledDice: got %v, want %v", r.RolledDice, test.dice)
+			}
+		})
+	}
+}
+
+func TestDiceBot_ExecuteUnknownCommand(t *testing.T) {
+	b := loadTestDiceBot(t, "crit_chain.star")
+
+	for _, input := range []string{"2D6", "DX", "0DX"} {
+		if _, err := b.ExecuteCommand(input, newTestEvaluator(nil)); err == nil {
+			t.Errorf("%q: エラーが発生しなかった", input)
+		}
+	}
+}
+
+func TestDiceBot_ScriptError(t *testing.T) {
+	b := loadTestDiceBot(t, "crit_chain.star")
+
+	_, err := b.ExecuteCommand("2DX@1", newTestEvaluator(nil))
+	if err == nil {
+		t.Fatal("エラーが発生しなかった")
+	}
+
+	expected := "crit_chain.star:29:13: fail: critical value must be 2 or greater"
+	if actual := err.Error(); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
+
+func TestDiceBot_Info(t *testing.T) {
+	s, err := LoadFile(filepath.Join("testdata", "crit_chain.star"))
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	b := NewConstructor(s)()
+
+	if actual := b.GameID(); actual != "CritChain" {
+		t.Errorf("GameID: got %q", actual)
+	}
+
+	if actual := b.GameName(); actual != "クリティカル連鎖" {
+		t.Errorf("GameName: got %q", actual)
+	}
+
+	if !strings.HasPrefix(b.Usage(), "nDX@c：") {
+		t.Errorf("Usage: got %q", b.Usage())
+	}
+
+	if expected := []string{"CC"}; !reflect.DeepEqual(s.Aliases, expected) {
+		t.Errorf("Aliases: got %v, want %v", s.Aliases, expected)
+	}
+
+	if actual := dicebot.SortKey(b); actual != "くりていかるれんさ" {
+		t.Errorf("SortKey: got %q", actual)
+	}
+
+	if expected := []string{`\d+DX`}; !reflect.DeepEqual(dicebot.CommandPrefixes(b), expected) {
+		t.Errorf("CommandPrefixes: got %v, want %v", dicebot.CommandPrefixes(b), expected)
+	}
+}
+
+func TestDiceBot_Execute(t *testing.T) {
+	s, err := Compile("execute.star", []byte(`
+gameId = "Exec"
+
+def command(input):
+    r = bcdice.execute(input)
+    parts = [r.message, "合計%d" % r.total, "出目" + ",".join([str(d) for d in r.dice])]
+    if r.success != None:
+        parts.append(str(r.success))
+    return {"parts": parts, "total": r.total, "success": r.success, "critical": r.total >= 12}
+`))
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	b := NewConstructor(s)()
+
+	r, err := b.ExecuteCommand("2D6>=7", newTestEvaluator([]dice.Die{{6, 6}, {6, 6}}))
+	if err != nil {
+		t.Fatalf("実行エラー: %s", err)
+	}
+
+	expected := "Exec : (2D6>=7) ＞ 12[6,6] ＞ 12 ＞ 成功 ＞ 合計12 ＞ 出目6,6 ＞ True"
+	if actual := r.Message(); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+
+	if r.SuccessCheckResult != command.SUCCESS_CHECK_SUCCESS || !r.IsCritical {
+		t.Errorf("判定結果が正しくない: %s, IsCritical=%v", r.SuccessCheckResult, r.IsCritical)
+	}
+}
+
+func TestDiceBot_InstructionBudget(t *testing.T) {
+	s, err := Compile("loop.star", []byte(`
+gameId = "Loop"
+
+def command(input):
+    while True:
+        bcdice.roll(1, 6)
+`))
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	s.InstructionBudget = 100
+
+	dice := make([]dice.Die, 100)
+	for i := range dice {
+		dice[i].Value = 1
+		dice[i].Sides = 6
+	}
+
+	b := NewConstructor(s)()
+	if _, err := b.ExecuteCommand("X", newTestEvaluator(dice)); err != ErrBudgetExceeded {
+		t.Errorf("got %v, want %v", err, ErrBudgetExceeded)
+	}
+}
+
+func TestDiceBot_StateIsResetForEachCommand(t *testing.T) {
+	s, err := Compile("state.star", []byte(`
+gameId = "State"
+calls = []
+
+def command(input):
+    calls.append(input)
+    return "回数%d" % len(calls)
+`))
+	if err != nil {
+		t.Fatalf("構築エラー: %s", err)
+	}
+
+	b := NewConstructor(s)()
+	for i := 0; i < 2; i++ {
+		r, err := b.ExecuteCommand("X", newTestEvaluator(nil))
+		if err != nil {
+			t.Fatalf("実行エラー: %s", err)
+		}
+
+		if actual := r.Message(); actual != "State : 回数1" {
+			t.Errorf("got %q", actual)
+		}
+	}
+}
+
+func TestCompile_NoSystemAccess(t *testing.T) {
+	testcases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"Open", "gameId = 'X'\nopen('/etc/passwd')", "undefined: open"},
+		{"OS", "gameId = 'X'\nos.getenv('HOME')", "undefined: os"},
+		{"Exec", "gameId = 'X'\nexec('x')", "undefined: exec"},
+		{"Load", "load('other.star', 'x')\ngameId = 'X'", "load not implemented"},
+		{"Recursion", "gameId = 'X'\ndef f(n):\n    return f(n - 1)\ndef command(input):\n    return f(1)\nf(1)", "called recursively"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compile("bad.star", []byte(test.src))
+			if err == nil {
+				t.Fatal("エラーが発生しなかった")
+				return
+			}
+
+			if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("got %q, want to contain %q", err.Error(), test.expected)
+			}
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	testcases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"SyntaxError", "gameId = ", "bad.star:1:10: got end of file, want primary expression"},
+		{"NoGameID", "def command(input):\n    pass", "bad.star: gameId must be a non-empty string"},
+		{"NoCommand", "gameId = 'X'", "bad.star: function command is not defined"},
+		{"InvalidAliases", "gameId = 'X'\naliases = [1]\ndef command(input):\n    pass", "bad.star: aliases must be a list of strings"},
+		{"RuntimeError", "gameId = 'X'\nx = None + 1", "bad.star:2:10: unknown binary op: NoneType + int"},
+		{"RollAtTopLevel", "gameId = 'X'\nbcdice.roll(1, 6)", "bad.star:2:12: bcdice.roll: dice can be rolled only while executing a command"},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compile("bad.star", []byte(test.src))
+			if err == nil {
+				t.Fatal("エラーが発生しなかった")
+				return
+			}
+
+			if !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("got %q, want to contain %q", err.Error(), test.expected)
+			}
+		})
+	}
+}
+
+func TestDiceBot_InvalidResult(t *testing.T) {
+	testcases := []string{
+		"return 1",
+		"return {}",
+		"return {'parts': 'x'}",
+		"return {'parts': [{}]}",
+		"return {'message': 'x', 'total': 'y'}",
+		"return {'message': 'x', 'success': 1}",
+	}
+
+	for _, body := range testcases {
+		s, err := Compile("result.star", []byte("gameId = 'R'\ndef command(input):\n    "+body))
+		if err != nil {
+			t.Fatalf("構築エラー: %s", err)
+		}
+
+		if _, err := NewConstructor(s)().ExecuteCommand("X", newTestEvaluator(nil)); err == nil {
+			t.Errorf("%q: エラーが発生しなかった", body)
+		}
+	}
+}
+
+func TestProtect(t *testing.T) {
+	err := protect(func() error {
+		panic("ホスト側のパニック")
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "ホスト側のパニック") {
+		t.Errorf("パニックがエラーとして返されなかった: %v", err)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"b.star":     "gameId = 'B'\naliases = ['BB']\ndef command(input):\n    pass",
+		"a.STAR":     "gameId = 'A'\ndef command(input):\n    pass",
+		"c.lua":      "無視される",
+		"readme.txt": "無視される",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registered := map[string][]string{}
+	add := func(gameID string, _ dicebot.DiceBotConstructor, aliases ...string) error {
+		registered[gameID] = aliases
+		return nil
+	}
+
+	gameIDs, err := loadDir(dir, add)
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	if expected := []string{"A", "B"}; !reflect.DeepEqual(gameIDs, expected) {
+		t.Errorf("got %v, want %v", gameIDs, expected)
+	}
+
+	if expected := []string{"BB"}; !reflect.DeepEqual(registered["B"], expected) {
+		t.Errorf("aliases: got %v, want %v", registered["B"], expected)
+	}
+}
@@ -0,0 +1,112 @@
+package table
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// D66の出目の並べ方の型
+type D66Order int
+
+const (
+	// 振った順に並べる（1個目が十の位、2個目が一の位）
+	D66_AS_ROLLED D66Order = iota
+	// 小さい方を十の位、大きい方を一の位とする
+	D66_ASCENDING
+	// ゲームシステムの既定値（評価器の SortD66）に従う
+	D66_GAME_DEFAULT
+)
+
+// D66の出目に項目を対応させる表。
+type D66Table struct {
+	// 表の名前
+	Title string
+	// 出目の並べ方
+	Order D66Order
+	// 出目ごとの項目
+	Items []D66Item
+}
+
+// D66の出目と対応する項目。
+type D66Item struct {
+	// 出目（11〜66）
+	Value int
+	// 項目
+	Item
+}
+
+var _ Table = (*D66Table)(nil)
+
+// Name は表の名前を返す。
+func (t *D66Table) Name() string {
+	return t.Title
+}
+
+// RollExpression は表を引くときのダイスロールの表記を返す。
+func (t *D66Table) RollExpression() string {
+	switch t.Order {
+	case D66_ASCENDING:
+		return "D66S"
+	case D66_GAME_DEFAULT:
+		return "D66"
+	}
+
+	return "D66N"
+}
+
+// Rows は表の各行を出目の順に返す。
+func (t *D66Table) Rows() []Row {
+	items := make([]D66Item, len(t.Items))
+	copy(items, t.Items)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Value < items[j].Value
+	})
+
+	rows := make([]Row, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, Row{Key: strconv.Itoa(item.Value), Item: item.Item})
+	}
+
+	return rows
+}
+
+// Roll はダイスを振り、出目に対応する項目を返す。
+// 出目に対応する項目が存在しない場合はエラーを返す。
+func (t *D66Table) Roll(ev *evaluator.Evaluator) (*Outcome, error) {
+	rolledDice, err := ev.RollDice(2, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	tens := rolledDice[0].Value
+	ones := rolledDice[1].Value
+	ascending := t.Order == D66_ASCENDING ||
+		(t.Order == D66_GAME_DEFAULT && ev.SortD66)
+	if ascending && tens > ones {
+		tens, ones = ones, tens
+	}
+
+	value := tens*10 + ones
+
+	item, found := t.Lookup(value)
+	if !found {
+		return nil, fmt.Errorf("table %s: no item for %d", t.Title, value)
+	}
+
+	return &Outcome{Item: item, Value: value, Notation: strconv.Itoa(value)}, nil
+}
+
+// Lookup は出目に対応する項目を返す。
+func (t *D66Table) Lookup(value int) (Item, bool) {
+	for _, item := range t.Items {
+		if item.Value == value {
+			return item.Item, true
+		}
+	}
+
+	return Item{}, false
+}
@@ -0,0 +1,73 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// 出目の合計の各値に項目を対応させる表。
+//
+// Items の最初の要素が出目の最小値（Num）に対応し、以降は出目が1ずつ増える。
+// 例えば 2D6 の表では、Items は 2〜12 に対応する11個の要素を持つ。
+type LookupTable struct {
+	// 表の名前
+	Title string
+	// 振るダイスの数
+	Num int
+	// ダイスの面数
+	Sides int
+	// 出目の最小値から順に並べた項目
+	Items []Item
+}
+
+var _ Table = (*LookupTable)(nil)
+
+// Name は表の名前を返す。
+func (t *LookupTable) Name() string {
+	return t.Title
+}
+
+// RollExpression は表を引くときのダイスロールの表記を返す。
+func (t *LookupTable) RollExpression() string {
+	return rollExpression(t.Num, t.Sides)
+}
+
+// Rows は表の各行を返す。
+func (t *LookupTable) Rows() []Row {
+	rows := make([]Row, 0, len(t.Items))
+	for i, item := range t.Items {
+		rows = append(rows, Row{Key: strconv.Itoa(t.Num + i), Item: item})
+	}
+
+	return rows
+}
+
+// Roll はダイスを振り、出目に対応する項目を返す。
+// 出目に対応する項目が存在しない場合はエラーを返す。
+func (t *LookupTable) Roll(ev *evaluator.Evaluator) (*Outcome, error) {
+	rolledDice, err := ev.RollDice(t.Num, t.Sides)
+	if err != nil {
+		return nil, err
+	}
+
+	value, notation := sumRollNotation(rolledDice)
+
+	item, found := t.Lookup(value)
+	if !found {
+		return nil, fmt.Errorf("table %s: no item for %d", t.Title, value)
+	}
+
+	return &Outcome{Item: item, Value: value, Notation: notation}, nil
+}
+
+// Lookup は出目に対応する項目を返す。
+func (t *LookupTable) Lookup(value int) (Item, bool) {
+	i := value - t.Num
+	if i < 0 || i >= len(t.Items) {
+		return Item{}, false
+	}
+
+	return t.Items[i], true
+}
@@ -0,0 +1,98 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// 出目の範囲と項目を対応させる表。
+//
+// 例：1D6 で 1-3 は X、4-6 は Y
+//
+//	&table.RangeTable{
+//		Title: "遭遇表",
+//		Num:   1,
+//		Sides: 6,
+//		Items: []table.RangeItem{
+//			{Min: 1, Max: 3, Item: table.Item{Text: "X"}},
+//			{Min: 4, Max: 6, Item: table.Item{Text: "Y"}},
+//		},
+//	}
+type RangeTable struct {
+	// 表の名前
+	Title string
+	// 振るダイスの数
+	Num int
+	// ダイスの面数
+	Sides int
+	// 出目の範囲ごとの項目
+	Items []RangeItem
+}
+
+// 出目の範囲と対応する項目。
+type RangeItem struct {
+	// 出目の最小値
+	Min int
+	// 出目の最大値
+	Max int
+	// 項目
+	Item
+}
+
+var _ Table = (*RangeTable)(nil)
+
+// Name は表の名前を返す。
+func (t *RangeTable) Name() string {
+	return t.Title
+}
+
+// RollExpression は表を引くときのダイスロールの表記を返す。
+func (t *RangeTable) RollExpression() string {
+	return rollExpression(t.Num, t.Sides)
+}
+
+// Rows は表の各行を返す。
+func (t *RangeTable) Rows() []Row {
+	rows := make([]Row, 0, len(t.Items))
+	for _, item := range t.Items {
+		key := strconv.Itoa(item.Min)
+		if item.Max != item.Min {
+			key = fmt.Sprintf("%d-%d", item.Min, item.Max)
+		}
+
+		rows = append(rows, Row{Key: key, Item: item.Item})
+	}
+
+	return rows
+}
+
+// Roll はダイスを振り、出目に対応する項目を返す。
+// 出目に対応する項目が存在しない場合はエラーを返す。
+func (t *RangeTable) Roll(ev *evaluator.Evaluator) (*Outcome, error) {
+	rolledDice, err := ev.RollDice(t.Num, t.Sides)
+	if err != nil {
+		return nil, err
+	}
+
+	value, notation := sumRollNotation(rolledDice)
+
+	item, found := t.Lookup(value)
+	if !found {
+		return nil, fmt.Errorf("table %s: no item for %d", t.Title, value)
+	}
+
+	return &Outcome{Item: item, Value: value, Notation: notation}, nil
+}
+
+// Lookup は出目に対応する項目を返す。
+func (t *RangeTable) Lookup(value int) (Item, bool) {
+	for _, item := range t.Items {
+		if value >= item.Min && value <= item.Max {
+			return item.Item, true
+		}
+	}
+
+	return Item{}, false
+}
@@ -0,0 +1,62 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// コマンド名と表との対応。
+type Command struct {
+	// 表を引くコマンド名（例："FT"）
+	Name string
+	// 表
+	Table Table
+}
+
+// ダイスボットが持つ表の集合。
+// 宣言した順に列挙される。
+type Set []Command
+
+// Find は、指定されたコマンド名の表を返す。
+// コマンド名の照合では大文字小文字を区別しない。
+func (s Set) Find(name string) (Table, bool) {
+	for _, c := range s {
+		if strings.EqualFold(c.Name, name) {
+			return c.Table, true
+		}
+	}
+
+	return nil, false
+}
+
+// Execute は、指定されたコマンド名の表を引き、その結果を返す。
+// 表が見つからなかった場合はエラーを返す。
+func (s Set) Execute(
+	name string,
+	gameID string,
+	ev *evaluator.Evaluator,
+) (*command.Result, error) {
+	t, found := s.Find(name)
+	if !found {
+		return nil, fmt.Errorf("unknown table: %s", name)
+	}
+
+	return Execute(t, gameID, ev)
+}
+
+// Usage は、コマンド名と表の名前の一覧を返す。
+// ダイスボットの使用法の説明に使う。
+//
+//	FT：ファンブル表(2D6)
+func (s Set) Usage() string {
+	lines := make([]string, 0, len(s))
+	for _, c := range s {
+		lines = append(lines,
+			fmt.Sprintf("%s：%s(%s)", c.Name, c.Table.Name(), c.Table.RollExpression()))
+	}
+
+	return strings.Join(lines, "\n")
+}
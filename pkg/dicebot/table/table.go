@@ -0,0 +1,171 @@
+/*
+ダイスボットで使用するロール表のパッケージ。
+
+以下の種類の表を Go のリテラルとして宣言できる。
+
+* RangeTable: 出目の範囲と項目を対応させる表（例：1D6 で 1-3 は X、4-6 は Y）
+
+* LookupTable: 出目の合計の各値に項目を対応させる表（例：2D6 の 2〜12）
+
+* D66Table: D66 の出目に項目を対応させる表
+
+項目に Next を指定すると、その項目が選ばれたときに続けて別の表を引く（連鎖する表）。
+
+表を引いた結果は command.Result として返され、メッセージは以下の形式となる。
+
+	表の名前(出目) ＞ 項目 ＞ 続けて引いた表の名前(出目) ＞ 項目
+
+例：
+
+	var fumbleTable = &table.LookupTable{
+		Title: "ファンブル表",
+		Num:   2,
+		Sides: 6,
+		Items: []table.Item{
+			{Text: "武器を落とす"},
+			// ...
+		},
+	}
+*/
+package table
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// ロール表のインターフェース。
+type Table interface {
+	// Name は表の名前を返す。
+	Name() string
+	// RollExpression は表を引くときのダイスロールの表記を返す。
+	RollExpression() string
+	// Rows は表の各行を出目の順に返す。ヘルプの出力などに使う。
+	Rows() []Row
+	// Roll はダイスを振り、出目に対応する項目を返す。
+	Roll(ev *evaluator.Evaluator) (*Outcome, error)
+}
+
+// 表の項目。
+type Item struct {
+	// 項目の文字列
+	Text string
+	// 項目が選ばれたときに続けて引く表（nilの場合は引かない）
+	Next Table
+}
+
+// 表の行。表の内容を列挙するときに使う。
+type Row struct {
+	// 行に対応する出目の表記（例："1-3"、"11"）
+	Key string
+	// 項目
+	Item
+}
+
+// 表を引いた結果。
+type Outcome struct {
+	// 選ばれた項目
+	Item
+	// 出目
+	Value int
+	// 出目の表記（例："7[3,4]"）
+	Notation string
+}
+
+// Execute は表を引き、その結果を返す。
+//
+// 項目に続けて引く表が指定されている場合は、その表も続けて引く。
+// 結果の Total には最初の表の出目が設定される。
+func Execute(t Table, gameID string, ev *evaluator.Evaluator) (*command.Result, error) {
+	result := &command.Result{
+		GameID: gameID,
+		Locale: ev.Locale,
+	}
+
+	// 表の連鎖が循環している場合に停止させるための上限
+	const maxDepth = 16
+
+	current := t
+	for depth := 0; current != nil; depth++ {
+		if depth >= maxDepth {
+			return nil, fmt.Errorf("table %s: too many chained tables", t.Name())
+		}
+
+		outcome, err := current.Roll(ev)
+		if err != nil {
+			return nil, err
+		}
+
+		if depth == 0 {
+			total := outcome.Value
+			result.Total = &total
+		}
+
+		result.MessageParts = append(
+			result.MessageParts,
+			fmt.Sprintf("%s(%s)", current.Name(), outcome.Notation),
+		)
+
+		if outcome.Text != "" {
+			result.MessageParts = append(result.MessageParts, outcome.Text)
+		}
+
+		current = outcome.Next
+	}
+
+	result.RolledDice = ev.RolledDice()
+	result.DieDefinitions = ev.RolledDieDefinitions()
+	result.Nonces = ev.RolledDieNonces()
+
+	return result, nil
+}
+
+// Help は表の内容を列挙した説明を返す。
+//
+// 形式は以下の通り。続けて引く表がある項目には、その表の名前が付加される。
+//
+//	表の名前(2D6)
+//	  2: 項目
+//	  3: 項目 → 続けて引く表の名前
+func Help(t Table) string {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "%s(%s)", t.Name(), t.RollExpression())
+
+	for _, row := range t.Rows() {
+		fmt.Fprintf(&out, "\n  %s: %s", row.Key, row.Text)
+
+		if row.Next != nil {
+			fmt.Fprintf(&out, " → %s", row.Next.Name())
+		}
+	}
+
+	return out.String()
+}
+
+// sumRollNotation は加算ロールの出目の表記を返す。
+// ダイスが1個の場合は出目のみ、複数の場合は "7[3,4]" のような形式となる。
+func sumRollNotation(rolledDice []dice.Die) (int, string) {
+	sum := 0
+	values := make([]string, 0, len(rolledDice))
+	for _, d := range rolledDice {
+		sum += d.Value
+		values = append(values, strconv.Itoa(d.Value))
+	}
+
+	if len(rolledDice) == 1 {
+		return sum, values[0]
+	}
+
+	return sum, fmt.Sprintf("%d[%s]", sum, strings.Join(values, ","))
+}
+
+// rollExpression は加算ロールの表記を返す。
+func rollExpression(num int, sides int) string {
+	return fmt.Sprintf("%dD%d", num, sides)
+}
@@ -0,0 +1,297 @@
+package table
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/roller"
+	"github.com/raa0121/GoBCDice/pkg/core/evaluator"
+)
+
+// テスト用の表
+var (
+	weaponTable = &RangeTable{
+		Title: "武器表",
+		Num:   1,
+		Sides: 6,
+		Items: []RangeItem{
+			{Min: 1, Max: 3, Item: Item{Text: "剣"}},
+			{Min: 4, Max: 5, Item: Item{Text: "槍"}},
+			{Min: 6, Max: 6, Item: Item{Text: "斧"}},
+		},
+	}
+
+	encounterTable = &LookupTable{
+		Title: "遭遇表",
+		Num:   2,
+		Sides: 6,
+		Items: []Item{
+			{Text: "竜"},
+			{Text: "巨人"},
+			{Text: "盗賊"},
+			{Text: "狼"},
+			{Text: "武器を拾う", Next: weaponTable},
+			{Text: "何もなし"},
+			{Text: "商人"},
+			{Text: "旅人"},
+			{Text: "騎士"},
+			{Text: "妖精"},
+			{Text: "宝箱"},
+		},
+	}
+
+	sceneTable = &D66Table{
+		Title: "シーン表",
+		Order: D66_ASCENDING,
+		Items: []D66Item{
+			{Value: 16, Item: Item{Text: "酒場"}},
+			{Value: 11, Item: Item{Text: "街角"}},
+			{Value: 36, Item: Item{Text: "森"}},
+		},
+	}
+
+	asRolledTable = &D66Table{
+		Title: "順序表",
+		Order: D66_AS_ROLLED,
+		Items: []D66Item{
+			{Value: 36, Item: Item{Text: "36"}},
+			{Value: 63, Item: Item{Text: "63"}},
+		},
+	}
+
+	testSet = Set{
+		{Name: "WT", Table: weaponTable},
+		{Name: "ET", Table: encounterTable},
+		{Name: "ST", Table: sceneTable},
+	}
+)
+
+// newTestEvaluator は、指定されたダイスを順に返す評価器を返す。
+func newTestEvaluator(ds []dice.Die) *evaluator.Evaluator {
+	return evaluator.NewEvaluator(
+		roller.New(feeder.NewQueue(ds)),
+		evaluator.NewEnvironment(),
+	)
+}
+
+func TestExecute(t *testing.T) {
+	testcases := []struct {
+		name          string
+		table         Table
+		dice          []dice.Die
+		expected      string
+		expectedTotal int
+	}{
+		{
+			name:          "Range",
+			table:         weaponTable,
+			dice:          []dice.Die{{2, 6}},
+			expected:      "DiceBot : 武器表(2) ＞ 剣",
+			expectedTotal: 2,
+		},
+		{
+			name:          "RangeLast",
+			table:         weaponTable,
+			dice:          []dice.Die{{6, 6}},
+			expected:      "DiceBot : 武器表(6) ＞ 斧",
+			expectedTotal: 6,
+		},
+		{
+			name:          "Lookup",
+			table:         encounterTable,
+			dice:          []dice.Die{{1, 6}, {1, 6}},
+			expected:      "DiceBot : 遭遇表(2[1,1]) ＞ 竜",
+			expectedTotal: 2,
+		},
+		{
+			name:          "LookupLast",
+			table:         encounterTable,
+			dice:          []dice.Die{{6, 6}, {6, 6}},
+			expected:      "DiceBot : 遭遇表(12[6,6]) ＞ 宝箱",
+			expectedTotal: 12,
+		},
+		{
+			name:          "Chained",
+			table:         encounterTable,
+			dice:          []dice.Die{{3, 6}, {3, 6}, {5, 6}},
+			expected:      "DiceBot : 遭遇表(6[3,3]) ＞ 武器を拾う ＞ 武器表(5) ＞ 槍",
+			expectedTotal: 6,
+		},
+		{
+			name:          "D66Ascending",
+			table:         sceneTable,
+			dice:          []dice.Die{{6, 6}, {3, 6}},
+			expected:      "DiceBot : シーン表(36) ＞ 森",
+			expectedTotal: 36,
+		},
+		{
+			name:          "D66AsRolled",
+			table:         asRolledTable,
+			dice:          []dice.Die{{6, 6}, {3, 6}},
+			expected:      "DiceBot : 順序表(63) ＞ 63",
+			expectedTotal: 63,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.name, func(t *testing.T) {
+			ev := newTestEvaluator(test.dice)
+
+			r, err := Execute(test.table, "DiceBot", ev)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+				return
+			}
+
+			actual := r.Message()
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+
+			if r.Total == nil || *r.Total != test.expectedTotal {
+				t.Errorf("Total: got %v, want %d", r.Total, test.expectedTotal)
+			}
+
+			if !reflect.DeepEqual(r.RolledDice, test.dice) {
+				t.Errorf("RolledDice: got %v, want %v", r.RolledDice, test.dice)
+			}
+		})
+	}
+}
+
+func TestExecute_NoItem(t *testing.T) {
+	ev := newTestEvaluator([]dice.Die{{2, 6}, {2, 6}})
+
+	if _, err := Execute(sceneTable, "DiceBot", ev); err == nil {
+		t.Error("項目のない出目でエラーが発生しなかった")
+	}
+}
+
+func TestExecute_Cycle(t *testing.T) {
+	cyclic := &RangeTable{Title: "循環表", Num: 1, Sides: 1}
+	cyclic.Items = []RangeItem{{Min: 1, Max: 1, Item: Item{Next: cyclic}}}
+
+	ds := make([]dice.Die, 100)
+	for i := range ds {
+		ds[i] = dice.Die{1, 1}
+	}
+
+	if _, err := Execute(cyclic, "DiceBot", newTestEvaluator(ds)); err == nil {
+		t.Error("循環する表でエラーが発生しなかった")
+	}
+}
+
+func TestD66Table_GameDefault(t *testing.T) {
+	gameDefaultTable := &D66Table{
+		Title: "既定表",
+		Order: D66_GAME_DEFAULT,
+		Items: []D66Item{
+			{Value: 36, Item: Item{Text: "36"}},
+			{Value: 63, Item: Item{Text: "63"}},
+		},
+	}
+
+	testcases := []struct {
+		sortD66  bool
+		expected string
+	}{
+		{false, "DiceBot : 既定表(63) ＞ 63"},
+		{true, "DiceBot : 既定表(36) ＞ 36"},
+	}
+
+	for _, test := range testcases {
+		t.Run(fmt.Sprintf("SortD66=%t", test.sortD66), func(t *testing.T) {
+			ev := newTestEvaluator([]dice.Die{{6, 6}, {3, 6}})
+			ev.SortD66 = test.sortD66
+
+			r, err := Execute(gameDefaultTable, "DiceBot", ev)
+			if err != nil {
+				t.Fatalf("実行エラー: %s", err)
+			}
+
+			if actual := r.Message(); actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+
+	if actual := gameDefaultTable.RollExpression(); actual != "D66" {
+		t.Errorf("RollExpression: got %q, want %q", actual, "D66")
+	}
+}
+
+func TestHelp(t *testing.T) {
+	testcases := []struct {
+		table    Table
+		expected string
+	}{
+		{
+			table: weaponTable,
+			expected: `武器表(1D6)
+  1-3: 剣
+  4-5: 槍
+  6: 斧`,
+		},
+		{
+			table: sceneTable,
+			expected: `シーン表(D66S)
+  11: 街角
+  16: 酒場
+  36: 森`,
+		},
+	}
+
+	for _, test := range testcases {
+		t.Run(test.table.Name(), func(t *testing.T) {
+			actual := Help(test.table)
+			if actual != test.expected {
+				t.Errorf("got %q, want %q", actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestHelp_Chained(t *testing.T) {
+	rows := encounterTable.Rows()
+	if len(rows) != 11 {
+		t.Fatalf("行数: got %d, want 11", len(rows))
+	}
+
+	row := rows[4]
+	if row.Key != "6" || row.Next != weaponTable {
+		t.Errorf("got %+v", row)
+	}
+}
+
+func TestSet(t *testing.T) {
+	ev := newTestEvaluator([]dice.Die{{4, 6}})
+
+	r, err := testSet.Execute("wt", "DiceBot", ev)
+	if err != nil {
+		t.Fatalf("実行エラー: %s", err)
+		return
+	}
+
+	expected := "DiceBot : 武器表(4) ＞ 槍"
+	if actual := r.Message(); actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+
+	if _, err := testSet.Execute("XX", "DiceBot", ev); err == nil {
+		t.Error("未知の表でエラーが発生しなかった")
+	}
+}
+
+func TestSet_Usage(t *testing.T) {
+	expected := `WT：武器表(1D6)
+ET：遭遇表(2D6)
+ST：シーン表(D66S)`
+
+	actual := testSet.Usage()
+	if actual != expected {
+		t.Errorf("got %q, want %q", actual, expected)
+	}
+}
@@ -0,0 +1,414 @@
+package testing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/raa0121/GoBCDice/pkg/bcdice"
+	"github.com/raa0121/GoBCDice/pkg/core/command"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+	"github.com/raa0121/GoBCDice/pkg/core/dice/feeder"
+)
+
+// テストケースの結果の種類
+type Status int
+
+const (
+	// 成功した
+	STATUS_PASSED Status = iota
+	// 失敗した
+	STATUS_FAILED
+	// ダイスボットが実装されていない
+	STATUS_UNIMPLEMENTED
+)
+
+// String は結果の種類を表す文字列を返す。
+func (s Status) String() string {
+	switch s {
+	case STATUS_PASSED:
+		return "PASSED"
+	case STATUS_FAILED:
+		return "FAILED"
+	case STATUS_UNIMPLEMENTED:
+		return "UNIMPLEMENTED"
+	}
+
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// テストケースの実行結果。
+type CaseResult struct {
+	// テストケース
+	TestCase *DiceBotTestCase
+	// 結果の種類
+	Status Status
+	// 失敗した理由（成功した場合は空）
+	Message string
+}
+
+// Name はテストケースの名前を返す。
+func (r *CaseResult) Name() string {
+	return caseName(r.TestCase)
+}
+
+// caseName はテストケースの名前を返す。
+func caseName(c *DiceBotTestCase) string {
+	return fmt.Sprintf(
+		"%s-%d:%q[%s]",
+		c.GameID,
+		c.Index,
+		strings.Join(c.Input, "\n"),
+		dice.FormatDiceWithoutSpaces(c.Dice),
+	)
+}
+
+// RunCase はテストケースを実行し、その結果を返す。
+//
+// 入力文字列の各行のコマンドを順に実行し、出力を改行で連結したものを予想される出力と比較する。
+// 判定結果を確認する場合は、最後の行の実行結果の判定結果を予想と比較する。
+// エラーが予想される場合は、いずれかの行でエラーが発生すれば成功とする。
+// ゲーム識別子に対応するダイスボットが登録されていない場合は、STATUS_UNIMPLEMENTED となる。
+func RunCase(test *DiceBotTestCase) *CaseResult {
+	result := &CaseResult{TestCase: test, Status: STATUS_PASSED}
+
+	f := feeder.NewQueue(test.Dice)
+	b := bcdice.New(f)
+
+	if err := b.SetDiceBotByGameID(test.GameID); err != nil {
+		result.Status = STATUS_UNIMPLEMENTED
+		result.Message = err.Error()
+		return result
+	}
+
+	outputs := make([]string, 0, len(test.Input))
+	var lastResult *command.Result
+	var commandErr error
+
+	for _, input := range test.Input {
+		r, err := b.ExecuteCommand(input)
+		if err != nil {
+			commandErr = err
+			break
+		}
+
+		lastResult = r
+
+		if r.IsSecret {
+			outputs = append(outputs, r.Message()+secretDiceSuffix)
+		} else {
+			outputs = append(outputs, r.Message())
+		}
+	}
+
+	switch {
+	case commandErr != nil && !test.ExpectError:
+		// 予期せぬエラー
+		return result.fail(fmt.Sprintf("コマンド実行エラー: %s", commandErr))
+	case commandErr != nil:
+		if !strings.Contains(commandErr.Error(), test.ExpectedErrorMessage) {
+			return result.fail(fmt.Sprintf(
+				"エラーメッセージ: got %q, want %q を含む",
+				commandErr.Error(),
+				test.ExpectedErrorMessage,
+			))
+		}
+
+		// 予想通りエラーが発生した
+		return result
+	case test.ExpectError:
+		// エラーが発生するはずなのに発生しなかった
+		return result.fail("エラーが発生しませんでした")
+	}
+
+	if actual := strings.Join(outputs, "\n"); actual != test.Output {
+		return result.fail(fmt.Sprintf("got: %q, want: %q", actual, test.Output))
+	}
+
+	if test.CheckOutcome && lastResult != nil {
+		if message := checkOutcome(test, lastResult); message != "" {
+			return result.fail(message)
+		}
+	}
+
+	if !f.IsEmpty() {
+		return result.fail("ダイス残り: " + dice.FormatDice(f.Dice()))
+	}
+
+	return result
+}
+
+// checkOutcome は、コマンドの実行結果の判定結果を予想と比較する。
+// 一致しない場合は、その内容を表すメッセージを返す。
+func checkOutcome(test *DiceBotTestCase, r *command.Result) string {
+	outcomes := []struct {
+		name     string
+		actual   bool
+		expected bool
+	}{
+		{"成功", r.SuccessCheckResult == command.SUCCESS_CHECK_SUCCESS, test.Success},
+		{"失敗", r.SuccessCheckResult == command.SUCCESS_CHECK_FAILURE, test.Failure},
+		{"クリティカル", r.IsCritical, test.Critical},
+		{"ファンブル", r.IsFumble, test.Fumble},
+	}
+
+	for _, o := range outcomes {
+		if o.actual != o.expected {
+			return fmt.Sprintf("%s: got %t, want %t", o.name, o.actual, o.expected)
+		}
+	}
+
+	return ""
+}
+
+// fail は結果を失敗にして返す。
+func (r *CaseResult) fail(message string) *CaseResult {
+	r.Status = STATUS_FAILED
+	r.Message = message
+	return r
+}
+
+// ゲームシステムごとの適合性テストの結果。
+type GameReport struct {
+	// ゲーム識別子
+	GameID string
+	// テストデータファイルのパス
+	Files []string
+	// ダイスボットが実装されているか
+	Implemented bool
+	// テストケースの実行結果
+	Results []*CaseResult
+}
+
+// NumPassed は成功したテストケースの数を返す。
+func (g *GameReport) NumPassed() int {
+	return g.count(STATUS_PASSED)
+}
+
+// NumFailed は失敗したテストケースの数を返す。
+func (g *GameReport) NumFailed() int {
+	return g.count(STATUS_FAILED)
+}
+
+// Failures は失敗したテストケースの実行結果を返す。
+func (g *GameReport) Failures() []*CaseResult {
+	failures := []*CaseResult{}
+
+	for _, r := range g.Results {
+		if r.Status == STATUS_FAILED {
+			failures = append(failures, r)
+		}
+	}
+
+	return failures
+}
+
+// count は指定された種類の結果の数を返す。
+func (g *GameReport) count(s Status) int {
+	n := 0
+
+	for _, r := range g.Results {
+		if r.Status == s {
+			n++
+		}
+	}
+
+	return n
+}
+
+// 適合性テストの結果。
+type Report struct {
+	// ゲームシステムごとの結果（ゲーム識別子の順）
+	Games []*GameReport
+}
+
+// NumPassed は成功したテストケースの総数を返す。
+func (r *Report) NumPassed() int {
+	n := 0
+
+	for _, g := range r.Games {
+		n += g.NumPassed()
+	}
+
+	return n
+}
+
+// NumFailed は失敗したテストケースの総数を返す。
+func (r *Report) NumFailed() int {
+	n := 0
+
+	for _, g := range r.Games {
+		n += g.NumFailed()
+	}
+
+	return n
+}
+
+// UnimplementedGameIDs は、ダイスボットが実装されていないゲーム識別子のスライスを返す。
+func (r *Report) UnimplementedGameIDs() []string {
+	gameIDs := []string{}
+
+	for _, g := range r.Games {
+		if !g.Implemented {
+			gameIDs = append(gameIDs, g.GameID)
+		}
+	}
+
+	return gameIDs
+}
+
+// Summary は結果の概要を表す文字列を返す。
+func (r *Report) Summary() string {
+	var b strings.Builder
+
+	fmt.Fprintf(
+		&b,
+		"成功: %d, 失敗: %d, 未実装: %d ゲームシステム\n",
+		r.NumPassed(),
+		r.NumFailed(),
+		len(r.UnimplementedGameIDs()),
+	)
+
+	for _, g := range r.Games {
+		if !g.Implemented {
+			fmt.Fprintf(&b, "%s: 未実装 (%d 件)\n", g.GameID, len(g.Results))
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s: 成功 %d, 失敗 %d\n", g.GameID, g.NumPassed(), g.NumFailed())
+
+		for _, f := range g.Failures() {
+			fmt.Fprintf(&b, "  %s: %s\n", f.Name(), f.Message)
+		}
+	}
+
+	return b.String()
+}
+
+// テストデータファイルの拡張子
+var testDataExts = []string{".txt", TOML_EXT}
+
+// isTestDataExt は、extがテストデータファイルの拡張子かどうかを返す。
+func isTestDataExt(ext string) bool {
+	for _, e := range testDataExts {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DiscoverTestData は、ディレクトリ内のテストデータファイルをゲーム識別子ごとにまとめて返す。
+//
+// "<dir>/<ゲーム識別子>.txt" および "<dir>/<ゲーム識別子>/*.txt" をテストデータファイルとする。
+// 拡張子が ".toml" のBCDice本家の形式のファイルも同様に扱う。
+// 各ゲーム識別子のファイルはパスの順に並べられる。
+func DiscoverTestData(dir string) (map[string][]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]string{}
+
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+
+		if e.IsDir() {
+			for _, ext := range testDataExts {
+				matches, err := filepath.Glob(filepath.Join(path, "*"+ext))
+				if err != nil {
+					return nil, err
+				}
+
+				files[name] = append(files[name], matches...)
+			}
+
+			continue
+		}
+
+		if ext := filepath.Ext(name); isTestDataExt(ext) {
+			gameID := strings.TrimSuffix(name, ext)
+			files[gameID] = append(files[gameID], path)
+		}
+	}
+
+	for gameID, paths := range files {
+		if len(paths) == 0 {
+			delete(files, gameID)
+			continue
+		}
+
+		sort.Strings(paths)
+	}
+
+	return files, nil
+}
+
+// RunConformance は、ディレクトリ内のすべてのテストデータファイルを、
+// 対応する登録済みのダイスボットで実行し、その結果を返す。
+//
+// テストデータファイルの探し方は DiscoverTestData と同じ。
+// 複数のディレクトリに同じゲーム識別子のファイルがある場合は、まとめて実行する。
+func RunConformance(dirs ...string) (*Report, error) {
+	files := map[string][]string{}
+
+	for _, dir := range dirs {
+		found, err := DiscoverTestData(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for gameID, paths := range found {
+			files[gameID] = append(files[gameID], paths...)
+		}
+	}
+
+	gameIDs := make([]string, 0, len(files))
+	for gameID := range files {
+		gameIDs = append(gameIDs, gameID)
+	}
+	sort.Strings(gameIDs)
+
+	report := &Report{Games: []*GameReport{}}
+
+	for _, gameID := range gameIDs {
+		testcases, err := ParseFiles(files[gameID], gameID)
+		if err != nil {
+			return nil, err
+		}
+
+		g := &GameReport{
+			GameID:      gameID,
+			Files:       files[gameID],
+			Implemented: true,
+			Results:     make([]*CaseResult, 0, len(testcases)),
+		}
+
+		for _, test := range testcases {
+			r := RunCase(test)
+			if r.Status == STATUS_UNIMPLEMENTED {
+				g.Implemented = false
+			}
+
+			g.Results = append(g.Results, r)
+		}
+
+		report.Games = append(report.Games, g)
+	}
+
+	return report, nil
+}
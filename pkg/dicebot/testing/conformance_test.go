@@ -0,0 +1,258 @@
+package testing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+func TestRunCase(t *testing.T) {
+	testcases := []struct {
+		name     string
+		test     DiceBotTestCase
+		expected Status
+	}{
+		{
+			name: "成功",
+			test: DiceBotTestCase{
+				GameID: "DiceBot",
+				Input:  []string{"2D6"},
+				Output: "DiceBot : (2D6) ＞ 7[3,4] ＞ 7",
+				Dice:   []dice.Die{{3, 6}, {4, 6}},
+			},
+			expected: STATUS_PASSED,
+		},
+		{
+			name: "複数行",
+			test: DiceBotTestCase{
+				GameID: "DiceBot",
+				Input:  []string{"1D6", "S1D6"},
+				Output: "DiceBot : (1D6) ＞ 3[3] ＞ 3\nDiceBot : (1D6) ＞ 5[5] ＞ 5###secret dice###",
+				Dice:   []dice.Die{{3, 6}, {5, 6}},
+			},
+			expected: STATUS_PASSED,
+		},
+		{
+			name: "出力の不一致",
+			test: DiceBotTestCase{
+				GameID: "DiceBot",
+				Input:  []string{"2D6"},
+				Output: "DiceBot : (2D6) ＞ 8[4,4] ＞ 8",
+				Dice:   []dice.Die{{3, 6}, {4, 6}},
+			},
+			expected: STATUS_FAILED,
+		},
+		{
+			name: "ダイス残り",
+			test: DiceBotTestCase{
+				GameID: "DiceBot",
+				Input:  []string{"1D6"},
+				Output: "DiceBot : (1D6) ＞ 3",
+				Dice:   []dice.Die{{3, 6}, {4, 6}},
+			},
+			expected: STATUS_FAILED,
+		},
+		{
+			name: "予想通りのエラー",
+			test: DiceBotTestCase{
+				GameID:      "DiceBot",
+				Input:       []string{"XYZ"},
+				Dice:        []dice.Die{},
+				ExpectError: true,
+			},
+			expected: STATUS_PASSED,
+		},
+		{
+			name: "エラーメッセージの不一致",
+			test: DiceBotTestCase{
+				GameID:               "DiceBot",
+				Input:                []string{"XYZ"},
+				Dice:                 []dice.Die{},
+				ExpectError:          true,
+				ExpectedErrorMessage: "存在しないメッセージ",
+			},
+			expected: STATUS_FAILED,
+		},
+		{
+			name: "エラーが発生しない",
+			test: DiceBotTestCase{
+				GameID:      "DiceBot",
+				Input:       []string{"1D6"},
+				Dice:        []dice.Die{{3, 6}},
+				ExpectError: true,
+			},
+			expected: STATUS_FAILED,
+		},
+		{
+			name: "未実装",
+			test: DiceBotTestCase{
+				GameID: "NotImplementedGame",
+				Input:  []string{"2D6"},
+				Output: "NotImplementedGame : (2D6) ＞ 7[3,4] ＞ 7",
+				Dice:   []dice.Die{{3, 6}, {4, 6}},
+			},
+			expected: STATUS_UNIMPLEMENTED,
+		},
+	}
+
+	for _, test := range testcases {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			r := RunCase(&test.test)
+
+			if r.Status != test.expected {
+				t.Errorf("got %s, want %s (%s)", r.Status, test.expected, r.Message)
+			}
+
+			if r.Status == STATUS_PASSED && r.Message != "" {
+				t.Errorf("成功時のメッセージ: %q", r.Message)
+			}
+		})
+	}
+}
+
+func TestDiscoverTestData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"DiceBot.txt":          "",
+		"Cthulhu/b.txt":        "",
+		"Cthulhu/a.txt":        "",
+		"Cthulhu/README.md":    "",
+		"README.md":            "",
+		".hidden.txt":          "",
+		"Empty/not-a-test.dat": "",
+	})
+
+	actual, err := DiscoverTestData(dir)
+	if err != nil {
+		t.Fatalf("探索エラー: %s", err)
+	}
+
+	expected := map[string][]string{
+		"DiceBot": {filepath.Join(dir, "DiceBot.txt")},
+		"Cthulhu": {
+			filepath.Join(dir, "Cthulhu", "a.txt"),
+			filepath.Join(dir, "Cthulhu", "b.txt"),
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, want %v", actual, expected)
+	}
+}
+
+func TestRunConformance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"DiceBot.txt": `input:
+2D6
+output:
+DiceBot : (2D6) ＞ 7[3,4] ＞ 7
+rand:3/6,4/6
+============================
+input:
+2D6
+output:
+DiceBot : (2D6) ＞ 8[4,4] ＞ 8
+rand:3/6,4/6
+============================
+input:
+XYZ
+output:
+!error
+rand:
+`,
+		"NotImplementedGame.txt": `input:
+2D6
+output:
+NotImplementedGame : (2D6) ＞ 7[3,4] ＞ 7
+rand:3/6,4/6
+`,
+	})
+
+	report, err := RunConformance(dir, filepath.Join(dir, "not-found"))
+	if err != nil {
+		t.Fatalf("実行エラー: %s", err)
+	}
+
+	if len(report.Games) != 2 {
+		t.Fatalf("ゲームシステム数: got %d, want 2", len(report.Games))
+	}
+
+	if report.Games[0].GameID != "DiceBot" || !report.Games[0].Implemented {
+		t.Errorf("DiceBot: got %+v", report.Games[0])
+	}
+
+	if report.NumPassed() != 2 {
+		t.Errorf("成功: got %d, want 2", report.NumPassed())
+	}
+
+	if report.NumFailed() != 1 {
+		t.Errorf("失敗: got %d, want 1", report.NumFailed())
+	}
+
+	expectedUnimplemented := []string{"NotImplementedGame"}
+	if actual := report.UnimplementedGameIDs(); !reflect.DeepEqual(actual, expectedUnimplemented) {
+		t.Errorf("未実装: got %v, want %v", actual, expectedUnimplemented)
+	}
+
+	summary := report.Summary()
+	for _, s := range []string{
+		"成功: 2, 失敗: 1, 未実装: 1 ゲームシステム",
+		"DiceBot: 成功 2, 失敗 1",
+		"DiceBot-2:",
+		"NotImplementedGame: 未実装 (1 件)",
+	} {
+		if !strings.Contains(summary, s) {
+			t.Errorf("概要に %q が含まれていない:\n%s", s, summary)
+		}
+	}
+}
+
+// TestConformance は、ダイスボットの共通のテストデータを実行し、概要を記録する。
+//
+// 共通のテストデータはBCDice本家のものであり、出力の形式が異なる部分があるため、
+// 失敗したテストケースがあってもテストを失敗させない。
+func TestConformance(t *testing.T) {
+	report, err := RunConformance(filepath.Join("..", "testdata"))
+	if err != nil {
+		t.Fatalf("実行エラー: %s", err)
+	}
+
+	if len(report.Games) == 0 {
+		t.Fatal("テストデータが見つからない")
+	}
+
+	t.Log("\n" + report.Summary())
+}
+
+// writeFiles は、ディレクトリ内にファイルを作る。
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"github.com/raa0121/GoBCDice/pkg/core/dice"
 	"io/ioutil"
+	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -21,13 +21,32 @@ type DiceBotTestCase struct {
 	Output string
 	// 入力するダイス列
 	Dice []dice.Die
+	// エラーが発生することを予想するか
+	ExpectError bool
+	// 予想するエラーメッセージに含まれる文字列（空の場合は確認しない）
+	ExpectedErrorMessage string
+	// 判定結果（成功・失敗・クリティカル・ファンブル）を確認するか
+	CheckOutcome bool
+	// 成功を予想するか
+	Success bool
+	// 失敗を予想するか
+	Failure bool
+	// クリティカルを予想するか
+	Critical bool
+	// ファンブルを予想するか
+	Fumble bool
 }
 
+// エラーの予想を表す出力文字列の接頭辞
+//
+// 出力文字列を "!error" とすると、コマンドの実行でエラーが発生することを予想する。
+// "!error: メッセージ" とすると、エラーメッセージにそのメッセージが含まれることも確認する。
+// 互換性のため、出力文字列が空の場合もエラーが発生することを予想する。
+const ERROR_EXPECTATION_PREFIX = "!error"
+
 var (
 	// テストケースのソースコードを表す正規表現
 	sourceRe = regexp.MustCompile("(?s)\\Ainput:\n(.+)\noutput:(.*)\nrand:(.*)")
-	// テストケースのソースコード内のダイス表記を表す正規表現
-	diceRe = regexp.MustCompile(`\A\s*(\d+)/(\d+)\s*\z`)
 )
 
 // Parse はテストケースのソースコードを構文解析し、その内容のDiceBotTestCaseを構築して返す。
@@ -49,39 +68,74 @@ func Parse(source string, gameID string, index int) (*DiceBotTestCase, error) {
 	input := strings.Split(matches[1], "\n")
 	output := strings.TrimLeft(matches[2], "\n")
 
-	return &DiceBotTestCase{
+	testCase := &DiceBotTestCase{
 		GameID: gameID,
 		Index:  index,
 		Input:  input,
 		Output: output,
 		Dice:   ds,
-	}, nil
+	}
+
+	if output == "" {
+		testCase.ExpectError = true
+	} else if expected, ok := parseErrorExpectation(output); ok {
+		testCase.Output = ""
+		testCase.ExpectError = true
+		testCase.ExpectedErrorMessage = expected
+	}
+
+	return testCase, nil
 }
 
-// ParseDice はテストケースのダイス表記を解析し、振られたダイスのスライスを返す。
-func ParseDice(source string) ([]dice.Die, error) {
-	rolledDice := []dice.Die{}
+// parseErrorExpectation は、出力文字列がエラーの予想かどうかを判定する。
+// エラーの予想の場合は、予想するエラーメッセージとtrueを返す。
+func parseErrorExpectation(output string) (string, bool) {
+	if !strings.HasPrefix(output, ERROR_EXPECTATION_PREFIX) || strings.Contains(output, "\n") {
+		return "", false
+	}
 
-	if source == "" {
-		return rolledDice, nil
+	rest := output[len(ERROR_EXPECTATION_PREFIX):]
+	if rest == "" {
+		return "", true
 	}
 
-	diceStrs := strings.Split(source, ",")
-	for i, diceStr := range diceStrs {
-		matches := diceRe.FindStringSubmatch(diceStr)
-		if matches == nil {
-			return nil, fmt.Errorf("ParseDice: #%d: %q: ダイス構文エラー", i+1, diceStr)
-		}
+	if !strings.HasPrefix(rest, ":") {
+		return "", false
+	}
 
-		Value, _ := strconv.Atoi(matches[1])
-		Sides, _ := strconv.Atoi(matches[2])
-		rolledDice = append(rolledDice, dice.Die{Value, Sides})
+	return strings.TrimSpace(rest[1:]), true
+}
+
+// Source はテストケースのソースコードを返す。
+// 結果は Parse で読み込むことができる。
+//
+// 記録型ダイス供給機（feeder.Recorder）と組み合わせることで、
+// 実際のセッションで発生した問題をテストケースにすることができる。
+func (c *DiceBotTestCase) Source() string {
+	output := c.Output
+	if c.ExpectError {
+		output = ERROR_EXPECTATION_PREFIX
+		if c.ExpectedErrorMessage != "" {
+			output += ": " + c.ExpectedErrorMessage
+		}
 	}
 
-	return rolledDice, nil
+	return fmt.Sprintf(
+		"input:\n%s\noutput:\n%s\nrand:%s",
+		strings.Join(c.Input, "\n"),
+		output,
+		dice.FormatDiceWithoutSpaces(c.Dice),
+	)
+}
+
+// ParseDice はテストケースのダイス表記を解析し、振られたダイスのスライスを返す。
+// 解析処理は dice.ParseDice に委譲する。
+func ParseDice(source string) ([]dice.Die, error) {
+	return dice.ParseDice(source)
 }
 
 // ParseFile はテストデータファイルを解析し、テストケースのスライスを返す。
+// 拡張子が ".toml" のファイルは、BCDice本家のTOML形式として ParseTOML で解析する。
 //
 // filename: テストデータファイルのパス,
 // gameID: ゲーム識別子。
@@ -91,6 +145,10 @@ func ParseFile(filename string, gameID string) ([]*DiceBotTestCase, error) {
 		return nil, err
 	}
 
+	if filepath.Ext(filename) == TOML_EXT {
+		return ParseTOML(string(contentBytes), gameID)
+	}
+
 	content := strings.TrimRight(string(contentBytes), "\n")
 	testCaseSources := strings.Split(content, "\n============================\n")
 	testCases := []*DiceBotTestCase{}
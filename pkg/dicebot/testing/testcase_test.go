@@ -104,6 +104,61 @@ rand:1/6,3/6`,
 		},
 		err: false,
 	},
+	{
+		source: `input:
+2D0
+output:
+!error
+rand:`,
+		gameID: "DiceBot",
+		index:  3,
+		expected: DiceBotTestCase{
+			GameID:      "DiceBot",
+			Index:       3,
+			Input:       []string{"2D0"},
+			Output:      "",
+			Dice:        []dice.Die{},
+			ExpectError: true,
+		},
+		err: false,
+	},
+	{
+		source: `input:
+2D6{unknown}
+output:
+!error: unknown die definition
+rand:`,
+		gameID: "DiceBot",
+		index:  4,
+		expected: DiceBotTestCase{
+			GameID:               "DiceBot",
+			Index:                4,
+			Input:                []string{"2D6{unknown}"},
+			Output:               "",
+			Dice:                 []dice.Die{},
+			ExpectError:          true,
+			ExpectedErrorMessage: "unknown die definition",
+		},
+		err: false,
+	},
+	{
+		// "!error" で始まっても、エラーの予想の構文でなければ通常の出力文字列として扱う
+		source: `input:
+CHOICE[!errors]
+output:
+DiceBot : (CHOICE[!errors]) ＞ !errors
+rand:1/1`,
+		gameID: "DiceBot",
+		index:  5,
+		expected: DiceBotTestCase{
+			GameID: "DiceBot",
+			Index:  5,
+			Input:  []string{"CHOICE[!errors]"},
+			Output: "DiceBot : (CHOICE[!errors]) ＞ !errors",
+			Dice:   []dice.Die{{1, 1}},
+		},
+		err: false,
+	},
 }
 
 func TestParse(t *testing.T) {
@@ -320,3 +375,27 @@ func TestParseFiles(t *testing.T) {
 			actualLastIndex, expectedLastIndex)
 	}
 }
+
+func TestDiceBotTestCase_Source(t *testing.T) {
+	for _, test := range parseTestCases {
+		if test.err {
+			continue
+		}
+
+		t.Run(fmt.Sprintf("%s-%d", test.gameID, test.index), func(t *testing.T) {
+			source := test.expected.Source()
+			if source != test.source {
+				t.Errorf("wrong source: got %q, want %q", source, test.source)
+			}
+
+			actual, err := Parse(source, test.gameID, test.index)
+			if err != nil {
+				t.Fatalf("got err: %v", err)
+			}
+
+			if !reflect.DeepEqual(*actual, test.expected) {
+				t.Errorf("got: %+v, want: %+v", *actual, test.expected)
+			}
+		})
+	}
+}
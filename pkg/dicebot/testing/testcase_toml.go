@@ -0,0 +1,218 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+// BCDice本家のTOML形式のテストデータファイルの拡張子
+const TOML_EXT = ".toml"
+
+// シークレットダイスの出力文字列に付加される文字列
+const secretDiceSuffix = "###secret dice###"
+
+// ParseTOML は、BCDice本家のTOML形式のテストデータを解析し、テストケースのスライスを返す。
+//
+// テストケースは以下のような [[test]] テーブルの配列として記述する。
+//
+//	[[test]]
+//	game_system = "DiceBot"
+//	input = "2D6>=7"
+//	output = "(2D6>=7) ＞ 7[3,4] ＞ 7 ＞ 成功"
+//	success = true
+//	rands = [
+//	  { sides = 6, value = 3 },
+//	  { sides = 6, value = 4 },
+//	]
+//
+// 出力文字列には "<ゲーム識別子> : " が前に付加され、secret が真の場合は
+// シークレットダイスを表す文字列が後に付加される。出力文字列が空の場合はエラーを予想する。
+// 判定結果（success, failure, critical, fumble）は、省略した場合は偽として確認する。
+// TOMLの解析には github.com/BurntSushi/toml を使う。
+//
+// source: TOMLのソースコード,
+// gameID: ゲーム識別子（game_system を省略したテストケースで使われる）。
+func ParseTOML(source string, gameID string) ([]*DiceBotTestCase, error) {
+	var doc map[string]interface{}
+	if _, err := toml.Decode(source, &doc); err != nil {
+		return nil, err
+	}
+
+	tests, ok := tomlArray(doc["test"])
+	if !ok {
+		return nil, fmt.Errorf("ParseTOML: [[test]] がありません")
+	}
+
+	testCases := make([]*DiceBotTestCase, 0, len(tests))
+
+	for i, t := range tests {
+		index := i + 1
+
+		table, ok := t.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("ParseTOML: %s#%d: test はテーブルでなければなりません", gameID, index)
+		}
+
+		testCase, err := newTestCaseFromTOML(table, gameID, index)
+		if err != nil {
+			return nil, fmt.Errorf("ParseTOML: %s#%d: %s", gameID, index, err)
+		}
+
+		testCases = append(testCases, testCase)
+	}
+
+	return testCases, nil
+}
+
+// newTestCaseFromTOML は、TOMLの [[test]] テーブルからテストケースを構築する。
+func newTestCaseFromTOML(t map[string]interface{}, gameID string, index int) (*DiceBotTestCase, error) {
+	gameSystem, err := tomlString(t, "game_system", gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := tomlString(t, "input", "")
+	if err != nil {
+		return nil, err
+	}
+
+	if input == "" {
+		return nil, fmt.Errorf("input がありません")
+	}
+
+	output, err := tomlString(t, "output", "")
+	if err != nil {
+		return nil, err
+	}
+
+	flags := map[string]bool{}
+	for _, key := range []string{"secret", "success", "failure", "critical", "fumble"} {
+		v, err := tomlBool(t, key)
+		if err != nil {
+			return nil, err
+		}
+
+		flags[key] = v
+	}
+
+	ds, err := tomlRands(t)
+	if err != nil {
+		return nil, err
+	}
+
+	testCase := &DiceBotTestCase{
+		GameID:       gameSystem,
+		Index:        index,
+		Input:        strings.Split(input, "\n"),
+		Dice:         ds,
+		CheckOutcome: true,
+		Success:      flags["success"],
+		Failure:      flags["failure"],
+		Critical:     flags["critical"],
+		Fumble:       flags["fumble"],
+	}
+
+	if output == "" {
+		testCase.ExpectError = true
+		testCase.CheckOutcome = false
+
+		return testCase, nil
+	}
+
+	testCase.Output = gameSystem + " : " + output
+	if flags["secret"] {
+		testCase.Output += secretDiceSuffix
+	}
+
+	return testCase, nil
+}
+
+// tomlString はテーブルから文字列の値を取り出す。
+// キーが存在しない場合は defaultValue を返す。
+func tomlString(t map[string]interface{}, key string, defaultValue string) (string, error) {
+	v, exists := t[key]
+	if !exists {
+		return defaultValue, nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s は文字列でなければなりません", key)
+	}
+
+	return s, nil
+}
+
+// tomlBool はテーブルから真偽値を取り出す。キーが存在しない場合は偽を返す。
+func tomlBool(t map[string]interface{}, key string) (bool, error) {
+	v, exists := t[key]
+	if !exists {
+		return false, nil
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s は真偽値でなければなりません", key)
+	}
+
+	return b, nil
+}
+
+// tomlRands はテーブルの rands から入力するダイス列を取り出す。
+func tomlRands(t map[string]interface{}) ([]dice.Die, error) {
+	ds := []dice.Die{}
+
+	v, exists := t["rands"]
+	if !exists {
+		return ds, nil
+	}
+
+	rands, ok := tomlArray(v)
+	if !ok {
+		return nil, fmt.Errorf("rands は配列でなければなりません")
+	}
+
+	for i, r := range rands {
+		table, ok := r.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("rands[%d] はテーブルでなければなりません", i)
+		}
+
+		sides, sidesOk := table["sides"].(int64)
+		value, valueOk := table["value"].(int64)
+		if !sidesOk || !valueOk {
+			return nil, fmt.Errorf("rands[%d] には整数の sides と value が必要です", i)
+		}
+
+		if sides < 1 || value < 1 || value > sides {
+			return nil, fmt.Errorf("rands[%d]: 不正なダイス: %d/%d", i, value, sides)
+		}
+
+		ds = append(ds, dice.Die{Value: int(value), Sides: int(sides)})
+	}
+
+	return ds, nil
+}
+
+// tomlArray は、TOMLの配列を []interface{} として返す。
+//
+// テーブルの配列は []map[string]interface{} として解析されるため、
+// 要素の型をそろえる。配列でない場合は偽を返す。
+func tomlArray(v interface{}) ([]interface{}, bool) {
+	switch a := v.(type) {
+	case []interface{}:
+		return a, true
+	case []map[string]interface{}:
+		result := make([]interface{}, 0, len(a))
+		for _, t := range a {
+			result = append(result, t)
+		}
+
+		return result, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,138 @@
+package testing
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/raa0121/GoBCDice/pkg/core/dice"
+)
+
+func TestParseTOMLTestCases(t *testing.T) {
+	source := `[[test]]
+game_system = "Cthulhu"
+input = "CC<=50"
+output = "(1D100<=50) ＞ 1 ＞ 決定的成功/スペシャル"
+success = true
+critical = true
+rands = [
+  { sides = 100, value = 1 },
+]
+
+[[test]]
+input = "S2D6"
+output = "(2D6) ＞ 7[3,4] ＞ 7"
+secret = true
+rands = [{ sides = 6, value = 3 }, { sides = 6, value = 4 }]
+
+[[test]]
+game_system = "DiceBot"
+input = "XYZ"
+output = ""
+rands = []
+`
+
+	expected := []*DiceBotTestCase{
+		{
+			GameID:       "Cthulhu",
+			Index:        1,
+			Input:        []string{"CC<=50"},
+			Output:       "Cthulhu : (1D100<=50) ＞ 1 ＞ 決定的成功/スペシャル",
+			Dice:         []dice.Die{{1, 100}},
+			CheckOutcome: true,
+			Success:      true,
+			Critical:     true,
+		},
+		{
+			GameID:       "DiceBot",
+			Index:        2,
+			Input:        []string{"S2D6"},
+			Output:       "DiceBot : (2D6) ＞ 7[3,4] ＞ 7###secret dice###",
+			Dice:         []dice.Die{{3, 6}, {4, 6}},
+			CheckOutcome: true,
+		},
+		{
+			GameID:      "DiceBot",
+			Index:       3,
+			Input:       []string{"XYZ"},
+			Dice:        []dice.Die{},
+			ExpectError: true,
+		},
+	}
+
+	actual, err := ParseTOML(source, "DiceBot")
+	if err != nil {
+		t.Fatalf("解析エラー: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %+v, want %+v", actual, expected)
+	}
+}
+
+func TestParseTOMLTestCases_Error(t *testing.T) {
+	testcases := []struct {
+		name   string
+		source string
+	}{
+		{"TOML構文エラー", `[[test]`},
+		{"testがない", `input = "2D6"`},
+		{"testの要素がテーブルでない", `test = [1]`},
+		{"inputがない", "[[test]]\noutput = \"x\""},
+		{"inputが文字列でない", "[[test]]\ninput = 1"},
+		{"フラグが真偽値でない", "[[test]]\ninput = \"2D6\"\nsuccess = 1"},
+		{"randsが配列でない", "[[test]]\ninput = \"2D6\"\nrands = 1"},
+		{"randsの要素が不完全", "[[test]]\ninput = \"2D6\"\nrands = [{ sides = 6 }]"},
+		{"出目が面の数を超える", "[[test]]\ninput = \"2D6\"\nrands = [{ sides = 6, value = 7 }]"},
+		{"出目が整数でない", "[[test]]\ninput = \"2D6\"\nrands = [{ sides = 6, value = 3.0 }]"},
+	}
+
+	for _, test := range testcases {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseTOML(test.source, "DiceBot"); err == nil {
+				t.Error("エラーが発生しませんでした")
+			}
+		})
+	}
+}
+
+func TestParseFile_TOML(t *testing.T) {
+	testcases, err := ParseFile(filepath.Join("testdata", "DiceBot.toml"), "DiceBot")
+	if err != nil {
+		t.Fatalf("読み込みエラー: %s", err)
+	}
+
+	if len(testcases) != 4 {
+		t.Fatalf("テストケース数: got %d, want 4", len(testcases))
+	}
+
+	for _, test := range testcases {
+		if r := RunCase(test); r.Status != STATUS_PASSED {
+			t.Errorf("%s: %s: %s", r.Name(), r.Status, r.Message)
+		}
+	}
+}
+
+func TestRunCase_Outcome(t *testing.T) {
+	test := &DiceBotTestCase{
+		GameID:       "DiceBot",
+		Input:        []string{"2D6>=7"},
+		Output:       "DiceBot : (2D6>=7) ＞ 7[3,4] ＞ 7 ＞ 成功",
+		Dice:         []dice.Die{{3, 6}, {4, 6}},
+		CheckOutcome: true,
+		Failure:      true,
+	}
+
+	if r := RunCase(test); r.Status != STATUS_FAILED {
+		t.Errorf("判定結果の不一致が検出されなかった: %s", r.Status)
+	}
+
+	test.Failure = false
+	test.Success = true
+
+	if r := RunCase(test); r.Status != STATUS_PASSED {
+		t.Errorf("got %s, want %s (%s)", r.Status, STATUS_PASSED, r.Message)
+	}
+}